@@ -0,0 +1,339 @@
+package bt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+)
+
+/*
+General format of a Background Evaluation Extended Format (BEEF) transaction, see BRC-62
+--------------------------------------------------------------------------------------
+Field            Description                                                               Size
+
+version          0x0100BEEF                                                                4 bytes
+
+nBUMPs           number of merkle paths which follow                                       1 - 9 bytes VI = VarInt
+
+BUMPs            BRC-74 merkle paths of the confirmed ancestors                             <nBUMPs>-many BUMPs
+
+nTransactions    number of transactions which follow                                       1 - 9 bytes VI = VarInt
+
+for each tx:
+  rawTx          the transaction in standard format                                        <rawTx length>-many bytes
+  hasBUMP        0x01 if a BUMP index follows, 0x00 otherwise                              1 byte
+  BUMP index     index of the BUMP proving this tx                                         1 - 9 bytes VI = VarInt
+
+Transactions are ordered so that every parent precedes its children, the last
+transaction being the one the BEEF is delivering.
+--------------------------------------------------------------------------------------
+*/
+
+// BeefVersion is the BRC-62 version marker (0x0100BEEF) read as a little endian uint32.
+const BeefVersion uint32 = 0xEFBE0001
+
+// BEEF "has BUMP" markers.
+const (
+	beefNoBUMP  byte = 0x00
+	beefHasBUMP byte = 0x01
+)
+
+// Beef is a BRC-62 envelope bundling a transaction with its ancestors and the
+// merkle paths of the ancestors which have been mined, allowing the receiver
+// to perform SPV on the transaction.
+type Beef struct {
+	// Tx is the transaction being delivered.
+	Tx *Tx
+	// Ancestors are the transactions Tx depends on, back to (and including)
+	// the first mined ancestor on each path.
+	Ancestors Txs
+	// MerklePaths prove the inclusion of the mined Ancestors.
+	MerklePaths []*MerklePath
+}
+
+// NewBeef creates a Beef for the tx, with its ancestors topologically sorted.
+func NewBeef(tx *Tx, ancestors Txs, merklePaths ...*MerklePath) *Beef {
+	b := &Beef{
+		Tx:          tx,
+		Ancestors:   ancestors,
+		MerklePaths: merklePaths,
+	}
+	b.SortAncestors()
+
+	return b
+}
+
+// NewBeefFromBytes decodes a BRC-62 binary encoded BEEF.
+func NewBeefFromBytes(b []byte) (*Beef, error) {
+	beef := &Beef{}
+	n, err := beef.ReadFrom(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	if int(n) != len(b) {
+		return nil, errors.Wrapf(ErrBeefTrailingBytes, "read %d of %d bytes", n, len(b))
+	}
+
+	return beef, nil
+}
+
+// NewBeefFromString decodes a hex string of a BRC-62 binary encoded BEEF.
+func NewBeefFromString(str string) (*Beef, error) {
+	b, err := hex.DecodeString(str)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBeefFromBytes(b)
+}
+
+// ReadFrom reads a BRC-62 binary encoded BEEF from the `io.Reader` into the `bt.Beef`.
+//
+// The last transaction read becomes Tx, and all preceding transactions become
+// the Ancestors. Every BUMP index is checked to be in range and to reference a
+// merkle path which contains the transaction.
+func (b *Beef) ReadFrom(r io.Reader) (int64, error) {
+	*b = Beef{}
+	var bytesRead int64
+
+	var version [4]byte
+	n, err := io.ReadFull(r, version[:])
+	bytesRead += int64(n)
+	if err != nil {
+		return bytesRead, errors.Wrapf(err, "version(4): got %d bytes", n)
+	}
+	if v := binary.LittleEndian.Uint32(version[:]); v != BeefVersion {
+		return bytesRead, errors.Wrapf(ErrBeefVersion, "got %#08x", v)
+	}
+
+	var nBUMPs VarInt
+	n64, err := nBUMPs.ReadFrom(r)
+	bytesRead += n64
+	if err != nil {
+		return bytesRead, err
+	}
+
+	for i := uint64(0); i < uint64(nBUMPs); i++ {
+		mp := &MerklePath{}
+		n64, err = mp.ReadFrom(r)
+		bytesRead += n64
+		if err != nil {
+			return bytesRead, errors.Wrapf(err, "BUMP %d", i)
+		}
+		b.MerklePaths = append(b.MerklePaths, mp)
+	}
+
+	var nTxs VarInt
+	n64, err = nTxs.ReadFrom(r)
+	bytesRead += n64
+	if err != nil {
+		return bytesRead, err
+	}
+	if nTxs == 0 {
+		return bytesRead, ErrBeefNoTxs
+	}
+
+	txs := make(Txs, 0)
+	var hasBUMP [1]byte
+	for i := uint64(0); i < uint64(nTxs); i++ {
+		tx := new(Tx)
+		n64, err = tx.ReadFrom(r)
+		bytesRead += n64
+		if err != nil {
+			return bytesRead, errors.Wrapf(err, "tx %d", i)
+		}
+
+		n, err = io.ReadFull(r, hasBUMP[:])
+		bytesRead += int64(n)
+		if err != nil {
+			return bytesRead, errors.Wrapf(err, "hasBUMP(1): got %d bytes", n)
+		}
+
+		switch hasBUMP[0] {
+		case beefNoBUMP:
+		case beefHasBUMP:
+			var idx VarInt
+			n64, err = idx.ReadFrom(r)
+			bytesRead += n64
+			if err != nil {
+				return bytesRead, err
+			}
+			if uint64(idx) >= uint64(len(b.MerklePaths)) {
+				return bytesRead, errors.Wrapf(ErrBeefBUMPIndex, "tx %d references BUMP %d of %d", i, idx, len(b.MerklePaths))
+			}
+			if !b.MerklePaths[idx].ContainsTxID(tx.TxIDChainHash()) {
+				return bytesRead, errors.Wrapf(ErrBeefBUMPMismatch, "tx %s and BUMP %d", tx.TxID(), idx)
+			}
+		default:
+			return bytesRead, errors.Wrapf(ErrBeefInvalidBUMPFlag, "got %#x", hasBUMP[0])
+		}
+
+		txs = append(txs, tx)
+	}
+
+	b.Tx = txs[len(txs)-1]
+	if len(txs) > 1 {
+		b.Ancestors = txs[:len(txs)-1]
+	}
+
+	return bytesRead, nil
+}
+
+// Bytes encodes the BEEF into the BRC-62 binary format. ErrBeefNoTx is
+// returned if Tx is nil, as the last transaction of a BEEF is read as its Tx.
+//
+// Ancestors are written in topological order regardless of the order they are
+// held in, and each transaction found in one of the MerklePaths is written with
+// the index of that path.
+func (b *Beef) Bytes() ([]byte, error) {
+	if b.Tx == nil {
+		return nil, ErrBeefNoTx
+	}
+
+	h := binary.LittleEndian.AppendUint32(make([]byte, 0), BeefVersion)
+
+	h = VarInt(uint64(len(b.MerklePaths))).AppendTo(h)
+	for _, mp := range b.MerklePaths {
+		h = append(h, mp.Bytes()...)
+	}
+
+	txs := append(b.sortedAncestors(), b.Tx)
+
+	h = VarInt(uint64(len(txs))).AppendTo(h)
+	for _, tx := range txs {
+		h = tx.AppendBytes(h)
+
+		idx, ok := b.merklePathIdx(tx.TxIDChainHash())
+		if !ok {
+			h = append(h, beefNoBUMP)
+			continue
+		}
+
+		h = append(h, beefHasBUMP)
+		h = VarInt(uint64(idx)).AppendTo(h)
+	}
+
+	return h, nil
+}
+
+// WriteTo writes the BRC-62 binary encoded BEEF to w.
+func (b *Beef) WriteTo(w io.Writer) (int64, error) {
+	bb, err := b.Bytes()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(bb)
+	return int64(n), err
+}
+
+// String encodes the BEEF into a BRC-62 hex string.
+func (b *Beef) String() (string, error) {
+	bb, err := b.Bytes()
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(bb), nil
+}
+
+// SortAncestors sorts the Ancestors topologically, so that every transaction
+// comes after all the Ancestors it spends from. The relative order of
+// unrelated transactions is preserved.
+func (b *Beef) SortAncestors() {
+	b.Ancestors = b.sortedAncestors()
+}
+
+// sortedAncestors returns a topologically sorted copy of the Ancestors.
+func (b *Beef) sortedAncestors() Txs {
+	byTxID := make(map[chainhash.Hash]*Tx, len(b.Ancestors))
+	for _, tx := range b.Ancestors {
+		byTxID[*tx.TxIDChainHash()] = tx
+	}
+
+	sorted := make(Txs, 0, len(b.Ancestors))
+	visited := make(map[chainhash.Hash]bool, len(b.Ancestors))
+
+	var visit func(tx *Tx)
+	visit = func(tx *Tx) {
+		txID := *tx.TxIDChainHash()
+		if visited[txID] {
+			return
+		}
+		visited[txID] = true
+
+		for _, in := range tx.Inputs {
+			if in.previousTxIDHash == nil {
+				continue
+			}
+			if parent, ok := byTxID[*in.previousTxIDHash]; ok {
+				visit(parent)
+			}
+		}
+
+		sorted = append(sorted, tx)
+	}
+
+	for _, tx := range b.Ancestors {
+		visit(tx)
+	}
+
+	return sorted
+}
+
+// merklePathIdx returns the index of the first merkle path proving the txid.
+func (b *Beef) merklePathIdx(txID *chainhash.Hash) (int, bool) {
+	for i, mp := range b.MerklePaths {
+		if mp.ContainsTxID(txID) {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// ToExtendedTx returns a copy of Tx in extended format, with the PreviousTxScript
+// and PreviousTxSatoshis of every input filled from the parent transactions
+// bundled as Ancestors.
+//
+// An ErrBeefMissingParent error is returned if a parent is not bundled.
+func (b *Beef) ToExtendedTx() (*ExtendedTx, error) {
+	if b.Tx == nil {
+		return nil, ErrTxNil
+	}
+
+	parents := make(map[chainhash.Hash]*Tx, len(b.Ancestors))
+	for _, tx := range b.Ancestors {
+		parents[*tx.TxIDChainHash()] = tx
+	}
+
+	tx := b.Tx.Clone()
+	for i, in := range tx.Inputs {
+		if in.previousTxIDHash == nil {
+			return nil, fmt.Errorf("%w at index %d", ErrEmptyPreviousTxID, i)
+		}
+
+		parent, ok := parents[*in.previousTxIDHash]
+		if !ok {
+			return nil, fmt.Errorf("%w %s for input %d", ErrBeefMissingParent, in.PreviousTxIDStr(), i)
+		}
+
+		out := parent.OutputIdx(int(in.PreviousTxOutIndex))
+		if out == nil {
+			return nil, fmt.Errorf("%w: %s:%d for input %d",
+				ErrOutputNoExist, in.PreviousTxIDStr(), in.PreviousTxOutIndex, i)
+		}
+
+		in.PreviousTxSatoshis = out.Satoshis
+		in.PreviousTxScript = cloneScript(out.LockingScript)
+	}
+	tx.SetExtended(true)
+
+	return &ExtendedTx{Tx: tx}, nil
+}
@@ -0,0 +1,286 @@
+package bt_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+)
+
+const beefTestAddress = "1NRoySJ9Lvby6DuE2UQYnyT67AASwNZxGb"
+
+// newSpendingTx creates a tx spending output vout of each of the parents.
+func newSpendingTx(t *testing.T, satoshis uint64, parents ...*bt.Tx) *bt.Tx {
+	t.Helper()
+
+	tx := bt.NewTx()
+	for _, parent := range parents {
+		out := parent.Outputs[0]
+		require.NoError(t, tx.From(parent.TxID(), 0, out.LockingScriptHexString(), out.Satoshis))
+	}
+	require.NoError(t, tx.PayToAddress(beefTestAddress, satoshis))
+
+	return tx
+}
+
+// beefTestChain returns a mined tx, an unmined child of it, and an unmined
+// grandchild, along with a merkle path proving the mined tx.
+func beefTestChain(t *testing.T) (*bt.Tx, *bt.Tx, *bt.Tx, *bt.MerklePath) {
+	t.Helper()
+
+	mined := bt.NewTx()
+	require.NoError(t, mined.From("3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5", 0,
+		"76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac", 3000))
+	require.NoError(t, mined.PayToAddress(beefTestAddress, 2000))
+
+	child := newSpendingTx(t, 1000, mined)
+	grandchild := newSpendingTx(t, 500, child)
+
+	sibling := chainhash.DoubleHashH([]byte("sibling"))
+	mp := &bt.MerklePath{
+		BlockHeight: 813706,
+		Path: [][]*bt.PathElement{{
+			{Offset: 0, Hash: mined.TxIDChainHash(), TxID: true},
+			{Offset: 1, Hash: &sibling},
+		}},
+	}
+
+	return mined, child, grandchild, mp
+}
+
+func TestBeef_Bytes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round trip", func(t *testing.T) {
+		mined, child, grandchild, mp := beefTestChain(t)
+
+		beef := bt.NewBeef(grandchild, bt.Txs{mined, child}, mp)
+		bb, err := beef.Bytes()
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0x01, 0x00, 0xbe, 0xef}, bb[:4])
+
+		got, err := bt.NewBeefFromBytes(bb)
+		require.NoError(t, err)
+		assert.Equal(t, grandchild.TxID(), got.Tx.TxID())
+		require.Len(t, got.Ancestors, 2)
+		assert.Equal(t, mined.TxID(), got.Ancestors[0].TxID())
+		assert.Equal(t, child.TxID(), got.Ancestors[1].TxID())
+		require.Len(t, got.MerklePaths, 1)
+		assert.Equal(t, mp.Bytes(), got.MerklePaths[0].Bytes())
+		gotBB, err := got.Bytes()
+		require.NoError(t, err)
+		assert.Equal(t, bb, gotBB)
+	})
+
+	t.Run("round trip from string", func(t *testing.T) {
+		mined, child, grandchild, mp := beefTestChain(t)
+
+		beef := bt.NewBeef(grandchild, bt.Txs{child, mined}, mp)
+		s, err := beef.String()
+		require.NoError(t, err)
+		got, err := bt.NewBeefFromString(s)
+		require.NoError(t, err)
+		gotS, err := got.String()
+		require.NoError(t, err)
+		assert.Equal(t, s, gotS)
+	})
+
+	t.Run("ancestors are written in topological order", func(t *testing.T) {
+		mined, child, grandchild, mp := beefTestChain(t)
+
+		beef := &bt.Beef{Tx: grandchild, Ancestors: bt.Txs{child, mined}, MerklePaths: []*bt.MerklePath{mp}}
+		bb, err := beef.Bytes()
+		require.NoError(t, err)
+		got, err := bt.NewBeefFromBytes(bb)
+		require.NoError(t, err)
+		require.Len(t, got.Ancestors, 2)
+		assert.Equal(t, mined.TxID(), got.Ancestors[0].TxID())
+		assert.Equal(t, child.TxID(), got.Ancestors[1].TxID())
+
+		// the receiver is not reordered by serialization
+		assert.Equal(t, child.TxID(), beef.Ancestors[0].TxID())
+	})
+
+	t.Run("tx without ancestors", func(t *testing.T) {
+		mined, _, _, mp := beefTestChain(t)
+
+		bb, err := bt.NewBeef(mined, nil, mp).Bytes()
+		require.NoError(t, err)
+		got, err := bt.NewBeefFromBytes(bb)
+		require.NoError(t, err)
+		assert.Equal(t, mined.TxID(), got.Tx.TxID())
+		assert.Empty(t, got.Ancestors)
+	})
+
+	t.Run("write to", func(t *testing.T) {
+		mined, child, grandchild, mp := beefTestChain(t)
+		beef := bt.NewBeef(grandchild, bt.Txs{mined, child}, mp)
+
+		var buf bytes.Buffer
+		n, err := beef.WriteTo(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, int64(buf.Len()), n)
+		bb, err := beef.Bytes()
+		require.NoError(t, err)
+		assert.Equal(t, bb, buf.Bytes())
+	})
+
+	t.Run("no tx", func(t *testing.T) {
+		mined, _, _, mp := beefTestChain(t)
+		beef := &bt.Beef{Ancestors: bt.Txs{mined}, MerklePaths: []*bt.MerklePath{mp}}
+
+		_, err := beef.Bytes()
+		require.ErrorIs(t, err, bt.ErrBeefNoTx)
+
+		_, err = beef.String()
+		require.ErrorIs(t, err, bt.ErrBeefNoTx)
+
+		var buf bytes.Buffer
+		n, err := beef.WriteTo(&buf)
+		require.ErrorIs(t, err, bt.ErrBeefNoTx)
+		assert.Zero(t, n)
+		assert.Zero(t, buf.Len())
+	})
+}
+
+func TestBeef_SortAncestors(t *testing.T) {
+	t.Parallel()
+
+	mined, child, grandchild, _ := beefTestChain(t)
+	unrelated := newSpendingTx(t, 100, mined)
+	greatGrandchild := newSpendingTx(t, 250, grandchild, unrelated)
+
+	beef := &bt.Beef{Tx: greatGrandchild, Ancestors: bt.Txs{grandchild, unrelated, child, mined}}
+	beef.SortAncestors()
+
+	pos := make(map[string]int)
+	for i, tx := range beef.Ancestors {
+		pos[tx.TxID()] = i
+	}
+	require.Len(t, pos, 4)
+	assert.Less(t, pos[mined.TxID()], pos[child.TxID()])
+	assert.Less(t, pos[mined.TxID()], pos[unrelated.TxID()])
+	assert.Less(t, pos[child.TxID()], pos[grandchild.TxID()])
+}
+
+func TestNewBeefFromBytes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid version", func(t *testing.T) {
+		_, err := bt.NewBeefFromBytes([]byte{0x01, 0x00, 0x00, 0xef, 0x00, 0x00})
+		require.ErrorIs(t, err, bt.ErrBeefVersion)
+	})
+
+	t.Run("no transactions", func(t *testing.T) {
+		_, err := bt.NewBeefFromBytes([]byte{0x01, 0x00, 0xbe, 0xef, 0x00, 0x00})
+		require.ErrorIs(t, err, bt.ErrBeefNoTxs)
+	})
+
+	t.Run("too short", func(t *testing.T) {
+		_, err := bt.NewBeefFromBytes([]byte{0x01, 0x00})
+		require.Error(t, err)
+	})
+
+	t.Run("bump index out of range", func(t *testing.T) {
+		mined, _, _, _ := beefTestChain(t)
+
+		bb := []byte{0x01, 0x00, 0xbe, 0xef, 0x00, 0x01}
+		bb = append(bb, mined.Bytes()...)
+		bb = append(bb, 0x01, 0x00)
+
+		_, err := bt.NewBeefFromBytes(bb)
+		require.ErrorIs(t, err, bt.ErrBeefBUMPIndex)
+	})
+
+	t.Run("bump not containing the tx", func(t *testing.T) {
+		_, child, _, mp := beefTestChain(t)
+
+		bb := []byte{0x01, 0x00, 0xbe, 0xef, 0x01}
+		bb = append(bb, mp.Bytes()...)
+		bb = append(bb, 0x01)
+		bb = append(bb, child.Bytes()...)
+		bb = append(bb, 0x01, 0x00)
+
+		_, err := bt.NewBeefFromBytes(bb)
+		require.ErrorIs(t, err, bt.ErrBeefBUMPMismatch)
+	})
+
+	t.Run("invalid bump flag", func(t *testing.T) {
+		mined, _, _, _ := beefTestChain(t)
+
+		bb := []byte{0x01, 0x00, 0xbe, 0xef, 0x00, 0x01}
+		bb = append(bb, mined.Bytes()...)
+		bb = append(bb, 0x02)
+
+		_, err := bt.NewBeefFromBytes(bb)
+		require.ErrorIs(t, err, bt.ErrBeefInvalidBUMPFlag)
+	})
+
+	t.Run("invalid bump", func(t *testing.T) {
+		mined, _, _, mp := beefTestChain(t)
+		mp.Path = nil
+
+		bb, err := bt.NewBeef(mined, nil, mp).Bytes()
+		require.NoError(t, err)
+
+		_, err = bt.NewBeefFromBytes(bb)
+		require.ErrorIs(t, err, bt.ErrMerklePathTreeHeight)
+	})
+
+	t.Run("trailing bytes", func(t *testing.T) {
+		mined, _, _, mp := beefTestChain(t)
+
+		bb, err := bt.NewBeef(mined, nil, mp).Bytes()
+		require.NoError(t, err)
+
+		_, err = bt.NewBeefFromBytes(append(bb, 0x00))
+		require.ErrorIs(t, err, bt.ErrBeefTrailingBytes)
+	})
+}
+
+func TestBeef_ToExtendedTx(t *testing.T) {
+	t.Parallel()
+
+	t.Run("inputs are filled from parents", func(t *testing.T) {
+		mined, child, grandchild, mp := beefTestChain(t)
+
+		// strip the previous outputs, as a receiver would see them
+		raw, err := bt.NewTxFromBytes(grandchild.Bytes())
+		require.NoError(t, err)
+		require.False(t, raw.IsExtended())
+
+		etx, err := bt.NewBeef(raw, bt.Txs{mined, child}, mp).ToExtendedTx()
+		require.NoError(t, err)
+		assert.True(t, etx.IsExtended())
+		assert.Equal(t, grandchild.TxID(), etx.TxID())
+		assert.Equal(t, child.Outputs[0].Satoshis, etx.Inputs[0].PreviousTxSatoshis)
+		assert.Equal(t, child.Outputs[0].LockingScript, etx.Inputs[0].PreviousTxScript)
+
+		// the beef tx is left untouched
+		assert.Nil(t, raw.Inputs[0].PreviousTxScript)
+	})
+
+	t.Run("missing parent", func(t *testing.T) {
+		mined, _, grandchild, mp := beefTestChain(t)
+
+		_, err := bt.NewBeef(grandchild, bt.Txs{mined}, mp).ToExtendedTx()
+		require.ErrorIs(t, err, bt.ErrBeefMissingParent)
+	})
+
+	t.Run("missing parent output", func(t *testing.T) {
+		mined, child, grandchild, mp := beefTestChain(t)
+		grandchild.Inputs[0].PreviousTxOutIndex = 5
+
+		_, err := bt.NewBeef(grandchild, bt.Txs{mined, child}, mp).ToExtendedTx()
+		require.ErrorIs(t, err, bt.ErrOutputNoExist)
+	})
+
+	t.Run("nil tx", func(t *testing.T) {
+		_, err := (&bt.Beef{}).ToExtendedTx()
+		require.ErrorIs(t, err, bt.ErrTxNil)
+	})
+}
@@ -0,0 +1,159 @@
+package bt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+)
+
+/*
+General format of a block
+-------------------------
+Field            Description                                                               Size
+
+block header     see BlockHeader                                                           80 bytes
+
+tx count         number of transactions in the block                                       1 - 9 bytes VI = VarInt
+
+txs              the transactions in the block, the first being the coinbase                <tx count>-many txs
+-------------------------
+*/
+
+// Block is a representation of a block, its header and all of its transactions.
+type Block struct {
+	Header *BlockHeader
+	Txs    Txs
+}
+
+// NewBlockFromBytes decodes a serialized block.
+func NewBlockFromBytes(b []byte) (*Block, error) {
+	block := &Block{}
+	n, err := block.ReadFrom(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	if int(n) != len(b) {
+		return nil, errors.Wrapf(ErrBlockTrailingBytes, "read %d of %d bytes", n, len(b))
+	}
+
+	return block, nil
+}
+
+// NewBlockFromString decodes a hex string of a serialized block.
+func NewBlockFromString(str string) (*Block, error) {
+	b, err := hex.DecodeString(str)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBlockFromBytes(b)
+}
+
+// ReadFrom reads from the `io.Reader` into the `bt.Block`.
+//
+// The whole block is held in memory. Use BlockHeader.ReadFrom followed by
+// Txs.ReadFrom, or a streaming reader, to avoid this with large blocks.
+func (b *Block) ReadFrom(r io.Reader) (int64, error) {
+	*b = Block{Header: &BlockHeader{}}
+
+	bytesRead, err := b.Header.ReadFrom(r)
+	if err != nil {
+		return bytesRead, err
+	}
+
+	n, err := b.Txs.ReadFrom(r)
+	bytesRead += n
+
+	return bytesRead, err
+}
+
+// Bytes encodes the block into its serialized format.
+func (b *Block) Bytes() []byte {
+	size := BlockHeaderLen + VarInt(uint64(len(b.Txs))).Length()
+	for _, tx := range b.Txs {
+		size += tx.Size()
+	}
+
+	h := make([]byte, 0, size)
+	if b.Header != nil {
+		h = append(h, b.Header.Bytes()...)
+	} else {
+		h = append(h, make([]byte, BlockHeaderLen)...)
+	}
+
+	h = VarInt(uint64(len(b.Txs))).AppendTo(h)
+	for _, tx := range b.Txs {
+		h = tx.AppendBytes(h)
+	}
+
+	return h
+}
+
+// WriteTo writes the serialized block to w.
+func (b *Block) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(b.Bytes())
+	return int64(n), err
+}
+
+// String encodes the block into a hex string.
+func (b *Block) String() string {
+	return hex.EncodeToString(b.Bytes())
+}
+
+// Hash returns the block hash, being the hash of its header, or nil if the
+// block has no header.
+func (b *Block) Hash() *chainhash.Hash {
+	if b.Header == nil {
+		return nil
+	}
+
+	return b.Header.Hash()
+}
+
+// MerkleRoot computes the merkle root of the txs in the block.
+func (b *Block) MerkleRoot() *chainhash.Hash {
+	hashes := make([]chainhash.Hash, len(b.Txs))
+
+	var scratch []byte
+	for i, tx := range b.Txs {
+		hashes[i], scratch = tx.HashTxIDInto(scratch)
+	}
+
+	root := merkleRoot(hashes)
+	return &root
+}
+
+// IsMerkleRootValid returns true if the merkle root computed from the txs in
+// the block matches the merkle root of the block header.
+func (b *Block) IsMerkleRootValid() bool {
+	if b.Header == nil {
+		return false
+	}
+
+	return b.MerkleRoot().IsEqual(&b.Header.MerkleRoot)
+}
+
+// merkleRoot computes the merkle root of the hashes, reusing the hashes
+// slice as working space. Levels of an odd width have their last node
+// paired with itself.
+func merkleRoot(hashes []chainhash.Hash) chainhash.Hash {
+	if len(hashes) == 0 {
+		return chainhash.Hash{}
+	}
+
+	for n := len(hashes); n > 1; n = (n + 1) / 2 {
+		for i := 0; i < n; i += 2 {
+			right := i + 1
+			if right == n {
+				right = i
+			}
+			hashes[i/2] = merkleTreeParent(&hashes[i], &hashes[right])
+		}
+	}
+
+	return hashes[0]
+}
@@ -0,0 +1,169 @@
+package bt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"math/big"
+
+	"github.com/pkg/errors"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+)
+
+/*
+General format of a block header
+--------------------------------
+Field            Description                                                               Size
+
+version          block version number                                                      4 bytes
+
+hashPrevBlock    hash of the previous block header                                         32 bytes
+
+hashMerkleRoot   merkle root of the transactions in the block                              32 bytes
+
+time             block timestamp as seconds since the unix epoch                           4 bytes
+
+bits             target threshold of the block hash in compact format                      4 bytes
+
+nonce            the nonce used to generate the block hash                                 4 bytes
+--------------------------------
+*/
+
+// BlockHeaderLen is the length in bytes of a serialized block header.
+const BlockHeaderLen = 80
+
+// BlockHeader is a representation of a block header.
+type BlockHeader struct {
+	Version       uint32
+	PrevBlockHash chainhash.Hash
+	MerkleRoot    chainhash.Hash
+	Timestamp     uint32
+	Bits          uint32
+	Nonce         uint32
+}
+
+// NewBlockHeaderFromBytes decodes an 80 byte serialized block header.
+func NewBlockHeaderFromBytes(b []byte) (*BlockHeader, error) {
+	if len(b) != BlockHeaderLen {
+		return nil, errors.Wrapf(ErrBlockHeaderLength, "got %d bytes", len(b))
+	}
+
+	bh := &BlockHeader{}
+	if _, err := bh.ReadFrom(bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+
+	return bh, nil
+}
+
+// NewBlockHeaderFromString decodes a hex string of a serialized block header.
+func NewBlockHeaderFromString(str string) (*BlockHeader, error) {
+	b, err := hex.DecodeString(str)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBlockHeaderFromBytes(b)
+}
+
+// ReadFrom reads from the `io.Reader` into the `bt.BlockHeader`.
+func (bh *BlockHeader) ReadFrom(r io.Reader) (int64, error) {
+	var b [BlockHeaderLen]byte
+	n, err := io.ReadFull(r, b[:])
+	if err != nil {
+		return int64(n), errors.Wrapf(err, "blockHeader(%d): got %d bytes", BlockHeaderLen, n)
+	}
+
+	bh.Version = binary.LittleEndian.Uint32(b[0:4])
+	copy(bh.PrevBlockHash[:], b[4:36])
+	copy(bh.MerkleRoot[:], b[36:68])
+	bh.Timestamp = binary.LittleEndian.Uint32(b[68:72])
+	bh.Bits = binary.LittleEndian.Uint32(b[72:76])
+	bh.Nonce = binary.LittleEndian.Uint32(b[76:80])
+
+	return int64(n), nil
+}
+
+// Bytes encodes the block header into its 80 byte serialized format.
+func (bh *BlockHeader) Bytes() []byte {
+	h := make([]byte, 0, BlockHeaderLen)
+	h = binary.LittleEndian.AppendUint32(h, bh.Version)
+	h = append(h, bh.PrevBlockHash[:]...)
+	h = append(h, bh.MerkleRoot[:]...)
+	h = binary.LittleEndian.AppendUint32(h, bh.Timestamp)
+	h = binary.LittleEndian.AppendUint32(h, bh.Bits)
+
+	return binary.LittleEndian.AppendUint32(h, bh.Nonce)
+}
+
+// WriteTo writes the serialized block header to w.
+func (bh *BlockHeader) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(bh.Bytes())
+	return int64(n), err
+}
+
+// String encodes the block header into a hex string.
+func (bh *BlockHeader) String() string {
+	return hex.EncodeToString(bh.Bytes())
+}
+
+// Hash returns the hash of the block header, which is also the block hash.
+func (bh *BlockHeader) Hash() *chainhash.Hash {
+	hash := chainhash.DoubleHashH(bh.Bytes())
+	return &hash
+}
+
+// Target returns the target threshold encoded in the compact Bits field.
+// A block hash must be no greater than the target to be valid.
+//
+// The compact format is a 3 byte mantissa and a 1 byte base 256 exponent,
+// where the highest bit of the mantissa is a sign bit.
+func (bh *BlockHeader) Target() *big.Int {
+	return CompactToBig(bh.Bits)
+}
+
+// IsProofOfWorkValid returns true if the block hash meets the target encoded in
+// the Bits field of the header.
+//
+// Only the header itself is checked. Whether the target is below the proof of
+// work limit of a network, or is correct for the height of the block, is not.
+func (bh *BlockHeader) IsProofOfWorkValid() bool {
+	target := bh.Target()
+	if target.Sign() <= 0 {
+		return false
+	}
+
+	return HashToBig(bh.Hash()).Cmp(target) <= 0
+}
+
+// CompactToBig converts a compact representation of a 256-bit number, as used
+// for the target threshold of a block header, into a big.Int.
+func CompactToBig(compact uint32) *big.Int {
+	mantissa := compact & 0x007fffff
+	isNegative := compact&0x00800000 != 0
+	exponent := uint(compact >> 24)
+
+	var bn *big.Int
+	if exponent <= 3 {
+		mantissa >>= 8 * (3 - exponent)
+		bn = big.NewInt(int64(mantissa))
+	} else {
+		bn = big.NewInt(int64(mantissa))
+		bn.Lsh(bn, 8*(exponent-3))
+	}
+
+	if isNegative {
+		bn = bn.Neg(bn)
+	}
+
+	return bn
+}
+
+// HashToBig converts a chainhash.Hash into a big.Int which can be compared
+// against a target threshold. Hashes are stored little endian, so the bytes
+// are reversed to build the big endian big.Int.
+func HashToBig(hash *chainhash.Hash) *big.Int {
+	return new(big.Int).SetBytes(ReverseBytes(hash[:]))
+}
@@ -0,0 +1,146 @@
+package bt_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+)
+
+const genesisBlockHeader = "0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c"
+
+func TestNewBlockHeaderFromString(t *testing.T) {
+	t.Parallel()
+
+	t.Run("genesis header", func(t *testing.T) {
+		bh, err := bt.NewBlockHeaderFromString(genesisBlockHeader)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint32(1), bh.Version)
+		assert.Equal(t, "0000000000000000000000000000000000000000000000000000000000000000", bh.PrevBlockHash.String())
+		assert.Equal(t, "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b", bh.MerkleRoot.String())
+		assert.Equal(t, uint32(1231006505), bh.Timestamp)
+		assert.Equal(t, uint32(0x1d00ffff), bh.Bits)
+		assert.Equal(t, uint32(2083236893), bh.Nonce)
+
+		assert.Equal(t, "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f", bh.Hash().String())
+		assert.Equal(t, genesisBlockHeader, bh.String())
+	})
+
+	t.Run("invalid length", func(t *testing.T) {
+		_, err := bt.NewBlockHeaderFromString(genesisBlockHeader[:158])
+		require.ErrorIs(t, err, bt.ErrBlockHeaderLength)
+
+		_, err = bt.NewBlockHeaderFromString(genesisBlockHeader + "00")
+		require.ErrorIs(t, err, bt.ErrBlockHeaderLength)
+	})
+
+	t.Run("invalid hex", func(t *testing.T) {
+		_, err := bt.NewBlockHeaderFromString("zz")
+		require.Error(t, err)
+	})
+}
+
+func TestBlockHeader_ReadFrom(t *testing.T) {
+	t.Parallel()
+
+	bh, err := bt.NewBlockHeaderFromString(genesisBlockHeader)
+	require.NoError(t, err)
+
+	t.Run("roundtrip", func(t *testing.T) {
+		var buf bytes.Buffer
+		n, err := bh.WriteTo(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, int64(bt.BlockHeaderLen), n)
+
+		var read bt.BlockHeader
+		n, err = read.ReadFrom(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, int64(bt.BlockHeaderLen), n)
+		assert.Equal(t, *bh, read)
+	})
+
+	t.Run("short read", func(t *testing.T) {
+		var read bt.BlockHeader
+		n, err := read.ReadFrom(bytes.NewReader(bh.Bytes()[:50]))
+		require.Error(t, err)
+		assert.Equal(t, int64(50), n)
+	})
+}
+
+func TestBlockHeader_IsProofOfWorkValid(t *testing.T) {
+	t.Parallel()
+
+	t.Run("genesis header is valid", func(t *testing.T) {
+		bh, err := bt.NewBlockHeaderFromString(genesisBlockHeader)
+		require.NoError(t, err)
+		assert.True(t, bh.IsProofOfWorkValid())
+	})
+
+	t.Run("wrong nonce is invalid", func(t *testing.T) {
+		bh, err := bt.NewBlockHeaderFromString(genesisBlockHeader)
+		require.NoError(t, err)
+		bh.Nonce++
+		assert.False(t, bh.IsProofOfWorkValid())
+	})
+
+	t.Run("harder target is invalid", func(t *testing.T) {
+		bh, err := bt.NewBlockHeaderFromString(genesisBlockHeader)
+		require.NoError(t, err)
+		bh.Bits = 0x1b00ffff
+		assert.False(t, bh.IsProofOfWorkValid())
+	})
+
+	t.Run("zero and negative targets are invalid", func(t *testing.T) {
+		bh, err := bt.NewBlockHeaderFromString(genesisBlockHeader)
+		require.NoError(t, err)
+
+		bh.Bits = 0
+		assert.False(t, bh.IsProofOfWorkValid())
+
+		bh.Bits = 0x1d80ffff
+		assert.False(t, bh.IsProofOfWorkValid())
+	})
+}
+
+func TestCompactToBig(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		compact uint32
+		exp     string
+	}{
+		"zero": {
+			compact: 0,
+			exp:     "0",
+		},
+		"small exponent": {
+			compact: 0x01123456,
+			exp:     "12",
+		},
+		"exponent of three": {
+			compact: 0x03123456,
+			exp:     "123456",
+		},
+		"large exponent": {
+			compact: 0x1d00ffff,
+			exp:     "ffff0000000000000000000000000000000000000000000000000000",
+		},
+		"negative": {
+			compact: 0x04923456,
+			exp:     "-12345600",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			exp, ok := new(big.Int).SetString(test.exp, 16)
+			require.True(t, ok)
+			assert.Equal(t, 0, exp.Cmp(bt.CompactToBig(test.compact)))
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package bt_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/go-bt/v2/testing/data"
+)
+
+const genesisBlock = genesisBlockHeader + "01" +
+	"01000000010000000000000000000000000000000000000000000000000000000000000000ffffffff4d04ffff001d0104455468652054696d65732030332f4a616e2f32303039204368616e63656c6c6f72206f6e206272696e6b206f66207365636f6e64206261696c6f757420666f722062616e6b73ffffffff0100f2052a01000000434104678afdb0fe5548271967f1a67130b7105cd6a828e03909a67962e0ea1f61deb649f6bc3f4cef38c4f35504e51ec112de5c384df7ba0b8d578a4c702b6bf11d5fac00000000"
+
+func readTestBlock(t *testing.T) []byte {
+	t.Helper()
+
+	f, err := data.TxBinData.Open("block.bin")
+	require.NoError(t, err)
+	defer func() {
+		_ = f.Close()
+	}()
+
+	b, err := io.ReadAll(f)
+	require.NoError(t, err)
+
+	return b
+}
+
+func TestNewBlockFromBytes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("genesis block", func(t *testing.T) {
+		block, err := bt.NewBlockFromString(genesisBlock)
+		require.NoError(t, err)
+
+		assert.Equal(t, "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f", block.Hash().String())
+		require.Len(t, block.Txs, 1)
+		assert.True(t, block.Txs[0].IsCoinbase())
+		assert.Equal(t, block.Header.MerkleRoot.String(), block.Txs[0].TxID())
+		assert.Equal(t, block.Header.MerkleRoot.String(), block.MerkleRoot().String())
+		assert.True(t, block.IsMerkleRootValid())
+	})
+
+	t.Run("block from file", func(t *testing.T) {
+		b := readTestBlock(t)
+
+		block, err := bt.NewBlockFromBytes(b)
+		require.NoError(t, err)
+
+		assert.Equal(t, "000000000000000004157b868ef6d0f6eab38e3fd7d66543bebe7b11afafbcec", block.Hash().String())
+		assert.True(t, block.Header.IsProofOfWorkValid())
+		require.Len(t, block.Txs, 648)
+		assert.Equal(t, "b7c59d7fa17a74bbe0a05e5381f42b9ac7fe23b8a1ca40005a74802fe5b8bb5a", block.Txs[647].TxID())
+		assert.True(t, block.IsMerkleRootValid())
+		assert.Equal(t, b, block.Bytes())
+	})
+
+	t.Run("trailing bytes", func(t *testing.T) {
+		_, err := bt.NewBlockFromString(genesisBlock + "00")
+		require.ErrorIs(t, err, bt.ErrBlockTrailingBytes)
+	})
+
+	t.Run("truncated header", func(t *testing.T) {
+		_, err := bt.NewBlockFromString(genesisBlockHeader[:100])
+		require.Error(t, err)
+	})
+
+	t.Run("truncated txs", func(t *testing.T) {
+		_, err := bt.NewBlockFromString(genesisBlock[:len(genesisBlock)-10])
+		require.Error(t, err)
+	})
+}
+
+func TestBlock_Hash(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no header", func(t *testing.T) {
+		block := &bt.Block{Txs: bt.Txs{bt.NewTx()}}
+		assert.Nil(t, block.Hash())
+	})
+}
+
+func TestBlock_IsMerkleRootValid(t *testing.T) {
+	t.Parallel()
+
+	t.Run("tampered tx", func(t *testing.T) {
+		block, err := bt.NewBlockFromBytes(readTestBlock(t))
+		require.NoError(t, err)
+
+		block.Txs[10].LockTime++
+		assert.False(t, block.IsMerkleRootValid())
+	})
+
+	t.Run("removed tx", func(t *testing.T) {
+		block, err := bt.NewBlockFromBytes(readTestBlock(t))
+		require.NoError(t, err)
+
+		block.Txs = block.Txs[:len(block.Txs)-1]
+		assert.False(t, block.IsMerkleRootValid())
+	})
+
+	t.Run("no header", func(t *testing.T) {
+		block := &bt.Block{Txs: bt.Txs{bt.NewTx()}}
+		assert.False(t, block.IsMerkleRootValid())
+	})
+
+	t.Run("empty block has zero root", func(t *testing.T) {
+		block := &bt.Block{Header: &bt.BlockHeader{}}
+		assert.Equal(t, chainhash.Hash{}, *block.MerkleRoot())
+	})
+}
+
+func TestBlock_WriteTo(t *testing.T) {
+	t.Parallel()
+
+	block, err := bt.NewBlockFromString(genesisBlock)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	n, err := block.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	assert.Equal(t, genesisBlock, block.String())
+}
@@ -8,60 +8,57 @@ import (
 	base58 "github.com/bsv-blockchain/go-sdk/compat/base58"
 	bec "github.com/bsv-blockchain/go-sdk/primitives/ec"
 	crypto "github.com/bsv-blockchain/go-sdk/primitives/hash"
-)
 
-const (
-	hashP2PKH        = 0x00
-	hashTestNetP2PKH = 0x6f
-	hashP2SH         = 0x05
-	hashTestNetP2SH  = 0xc4
+	"github.com/bsv-blockchain/go-bt/v2/chaincfg"
 )
 
 // An Address struct contains the address string as well as the hash160 hex string of the public key.
 // The address string will be human-readable and specific to the network type, but the public key hash
 // is useful because it stays the same regardless of the network type (mainnet, testnet).
+//
+// Legacy P2SH addresses can be decoded, in which case ScriptHash holds the hash160 of the
+// redeem script and PublicKeyHash is empty. Paying to them is not supported, see IsP2SH.
 type Address struct {
 	AddressString string
 	PublicKeyHash string
+	ScriptHash    string
+	Network       Network
 }
 
-// NewAddressFromString takes a string address (P2PKH) and returns a pointer to an Address
-// which contains the address string as well as the public key hash string.
+// NewAddressFromString takes a string address (P2PKH or legacy P2SH) and returns a pointer to an
+// Address which contains the address string, the hash string and the network of the address.
 func NewAddressFromString(addr string) (*Address, error) {
-	pkh, err := addressToPubKeyHashStr(addr)
+	decoded, err := base58.Decode(addr)
 	if err != nil {
 		return nil, err
 	}
-	return &Address{
-		AddressString: addr,
-		PublicKeyHash: pkh,
-	}, nil
-}
-
-func addressToPubKeyHashStr(address string) (string, error) {
-	decoded, err := base58.Decode(address)
-	if err != nil {
-		return "", err
-	}
 
 	if len(decoded) != 25 {
-		return "", fmt.Errorf("%w for '%s'", ErrInvalidAddressLength, address)
+		return nil, fmt.Errorf("%w for '%s'", ErrInvalidAddressLength, addr)
 	}
 
-	switch decoded[0] {
-	case hashP2PKH: // Pubkey hash (P2PKH address)
-		return hex.EncodeToString(decoded[1 : len(decoded)-4]), nil
+	a := &Address{AddressString: addr}
+	hash := hex.EncodeToString(decoded[1 : len(decoded)-4])
+
+	// Networks sharing version bytes resolve to the first of them.
+	for _, net := range chaincfg.Networks() {
+		switch decoded[0] {
+		case net.Params().PubKeyHashAddrID:
+			a.PublicKeyHash, a.Network = hash, net
+			return a, nil
+		case net.Params().ScriptHashAddrID:
+			a.ScriptHash, a.Network = hash, net
+			return a, nil
+		}
+	}
 
-	case hashTestNetP2PKH: // Testnet pubkey hash (P2PKH address)
-		return hex.EncodeToString(decoded[1 : len(decoded)-4]), nil
+	return nil, fmt.Errorf("%w %s", ErrUnsupportedAddress, addr)
+}
 
-	case hashP2SH: // Script hash (P2SH address)
-		fallthrough
-	case hashTestNetP2SH: // Testnet script hash (P2SH address)
-		fallthrough
-	default:
-		return "", fmt.Errorf("%w %s", ErrUnsupportedAddress, address)
-	}
+// IsP2SH returns true if the address is a legacy P2SH address. P2SH was deactivated
+// by the genesis upgrade, so outputs paying to these addresses must not be created.
+func (a *Address) IsP2SH() bool {
+	return a.ScriptHash != ""
 }
 
 // NewAddressFromPublicKeyString takes a public key string and returns an Address struct pointer.
@@ -79,18 +76,28 @@ func NewAddressFromPublicKeyString(pubKey string, mainnet bool) (*Address, error
 // If mainnet parameter is true it will return a mainnet address (starting with a 1).
 // Otherwise, (mainnet is false) it will return a testnet address (starting with an m or n).
 func NewAddressFromPublicKeyHash(hash []byte, mainnet bool) (*Address, error) {
-	// regtest := 111
-	// mainnet: 0
+	if mainnet {
+		return NewAddressFromPublicKeyHashForNetwork(hash, Mainnet)
+	}
+	return NewAddressFromPublicKeyHashForNetwork(hash, Testnet)
+}
 
-	bb := make([]byte, 1, 1+len(hash))
-	if !mainnet {
-		bb[0] = 111
+// NewAddressFromPublicKeyHashForNetwork takes a public key hash in bytes and returns an
+// Address struct pointer for the given network.
+func NewAddressFromPublicKeyHashForNetwork(hash []byte, net Network) (*Address, error) {
+	params := net.Params()
+	if params == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAddress, net)
 	}
+
+	bb := make([]byte, 1, 1+len(hash))
+	bb[0] = params.PubKeyHashAddrID
 	bb = append(bb, hash...)
 
 	return &Address{
 		AddressString: Base58EncodeMissingChecksum(bb),
 		PublicKeyHash: hex.EncodeToString(hash),
+		Network:       net,
 	}, nil
 }
 
@@ -98,21 +105,7 @@ func NewAddressFromPublicKeyHash(hash []byte, mainnet bool) (*Address, error) {
 // If mainnet parameter is true it will return a mainnet address (starting with a 1).
 // Otherwise, (mainnet is false) it will return a testnet address (starting with an m or n).
 func NewAddressFromPublicKey(pubKey *bec.PublicKey, mainnet bool) (*Address, error) {
-	hash := crypto.Hash160(pubKey.Compressed())
-
-	// regtest := 111
-	// mainnet: 0
-
-	bb := make([]byte, 1, 1+len(hash))
-	if !mainnet {
-		bb[0] = 111
-	}
-	bb = append(bb, hash...)
-
-	return &Address{
-		AddressString: Base58EncodeMissingChecksum(bb),
-		PublicKeyHash: hex.EncodeToString(hash),
-	}, nil
+	return NewAddressFromPublicKeyHash(crypto.Hash160(pubKey.Compressed()), mainnet)
 }
 
 // Base58EncodeMissingChecksum appends a checksum to a byte sequence
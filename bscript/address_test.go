@@ -25,6 +25,8 @@ func TestNewAddressFromString(t *testing.T) {
 
 		assert.Equal(t, "8fe80c75c9560e8b56ed64ea3c26e18d2c52211b", addr.PublicKeyHash, addressMain)
 		assert.Equal(t, addressMain, addr.AddressString)
+		assert.Equal(t, bscript.Mainnet, addr.Network)
+		assert.False(t, addr.IsP2SH())
 	})
 
 	t.Run("testnet", func(t *testing.T) {
@@ -36,6 +38,25 @@ func TestNewAddressFromString(t *testing.T) {
 
 		assert.Equal(t, "8fe80c75c9560e8b56ed64ea3c26e18d2c52211b", addr.PublicKeyHash, addressTestnet)
 		assert.Equal(t, addressTestnet, addr.AddressString)
+		assert.Equal(t, bscript.Testnet, addr.Network)
+	})
+
+	t.Run("p2sh", func(t *testing.T) {
+		for address, network := range map[string]bscript.Network{
+			"3EovXzwwyMu5J9C9Wod2nSCxyFMCpw6BkA":  bscript.Mainnet,
+			"2N6N8bjsyapQRVvphBwEuQPCEBbZNZAxjx1": bscript.Testnet,
+		} {
+			addr, err := bscript.NewAddressFromString(address)
+			require.NoError(t, err)
+
+			assert.True(t, addr.IsP2SH())
+			assert.Equal(t, "8fe80c75c9560e8b56ed64ea3c26e18d2c52211b", addr.ScriptHash)
+			assert.Empty(t, addr.PublicKeyHash)
+			assert.Equal(t, network, addr.Network)
+
+			_, err = bscript.NewP2PKHFromAddress(address)
+			require.ErrorIs(t, err, bscript.ErrP2SHAddress)
+		}
 	})
 
 	t.Run("short address", func(t *testing.T) {
@@ -103,6 +124,28 @@ func TestNewAddressFromPublicKey(t *testing.T) {
 	assert.Equal(t, "114ZWApV4EEU8frr7zygqQcB1V2BodGZuS", addr.AddressString)
 }
 
+func TestNewAddressFromPublicKeyHashForNetwork(t *testing.T) {
+	t.Parallel()
+
+	hash, err := hex.DecodeString("8fe80c75c9560e8b56ed64ea3c26e18d2c52211b")
+	require.NoError(t, err)
+
+	tests := map[bscript.Network]string{
+		bscript.Mainnet: "1E7ucTTWRTahCyViPhxSMor2pj4VGQdFMr",
+		bscript.Testnet: "mtdruWYVEV1wz5yL7GvpBj4MgifCB7yhPd",
+		bscript.STN:     "mtdruWYVEV1wz5yL7GvpBj4MgifCB7yhPd",
+		bscript.Regtest: "mtdruWYVEV1wz5yL7GvpBj4MgifCB7yhPd",
+	}
+	for network, expAddress := range tests {
+		t.Run(network.String(), func(t *testing.T) {
+			addr, err := bscript.NewAddressFromPublicKeyHashForNetwork(hash, network)
+			require.NoError(t, err)
+			assert.Equal(t, expAddress, addr.AddressString)
+			assert.Equal(t, network, addr.Network)
+		})
+	}
+}
+
 func TestBase58EncodeMissingChecksum(t *testing.T) {
 	t.Parallel()
 
@@ -7,6 +7,8 @@ import (
 	"strconv"
 
 	crypto "github.com/bsv-blockchain/go-sdk/primitives/hash"
+
+	"github.com/bsv-blockchain/go-bt/v2/chaincfg"
 )
 
 // BIP276 proposes a scheme for encoding typed bitcoin related data in a user-friendly way
@@ -33,11 +35,11 @@ const CurrentVersion = 1
 
 // NetworkMainnet specifies that the data is only
 // valid for use on the main network.
-const NetworkMainnet = 1
+const NetworkMainnet = chaincfg.BIP276Mainnet
 
 // NetworkTestnet specifies that the data is only
 // valid for use on the test network.
-const NetworkTestnet = 2
+const NetworkTestnet = chaincfg.BIP276Testnet
 
 var validBIP276 = regexp.MustCompile(`^(.+?):(\d{2})(\d{2})([0-9A-Fa-f]+)([0-9A-Fa-f]{8})$`)
 
@@ -13,6 +13,7 @@ var (
 var (
 	ErrInvalidAddressLength = errors.New("invalid address length")
 	ErrUnsupportedAddress   = errors.New("address not supported")
+	ErrP2SHAddress          = errors.New("cannot pay to a P2SH address, P2SH was deactivated by the genesis upgrade")
 )
 
 // Sentinel errors raised by inscriptions.
@@ -20,6 +21,12 @@ var (
 	ErrP2PKHInscriptionNotFound = errors.New("no P2PKH inscription found")
 )
 
+// Sentinel errors raised by script templates.
+var (
+	ErrTemplateMismatch = errors.New("script does not match template")
+	ErrTemplateParams   = errors.New("invalid template params")
+)
+
 // Sentinel errors raised through encoding.
 var (
 	ErrEncodingBadChar         = errors.New("bad char")
@@ -36,5 +43,6 @@ var (
 	ErrInvalidOpCode     = errors.New("invalid opcode data")
 	ErrEmptyScript       = errors.New("script is empty")
 	ErrNotP2PKH          = errors.New("not a P2PKH")
+	ErrNotMultiSig       = errors.New("not a multisig")
 	ErrInvalidOpcodeType = errors.New("use AppendPushData for push data funcs")
 )
@@ -0,0 +1,193 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter/errs"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter/scriptflag"
+	"github.com/bsv-blockchain/go-bt/v2/chaincfg"
+)
+
+func TestChronicleMalleability(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		unlockingScript string
+		lockingScript   string
+		flags           scriptflag.Flag
+		expErrCode      errs.ErrorCode
+	}{
+		"minimal data": {
+			unlockingScript: "0x01 0x01",
+			lockingScript:   "1 EQUAL",
+			flags:           scriptflag.VerifyMinimalData,
+			expErrCode:      errs.ErrMinimalData,
+		},
+		"clean stack": {
+			unlockingScript: "1 1",
+			lockingScript:   "1 EQUAL",
+			flags:           scriptflag.Bip16 | scriptflag.VerifyCleanStack,
+			expErrCode:      errs.ErrCleanStack,
+		},
+		"sig push only": {
+			unlockingScript: "1 NOP",
+			lockingScript:   "1 EQUAL",
+			flags:           scriptflag.VerifySigPushOnly,
+			expErrCode:      errs.ErrNotPushOnly,
+		},
+		"minimal if": {
+			unlockingScript: "2",
+			lockingScript:   "IF 1 ELSE 0 ENDIF",
+			flags:           scriptflag.VerifyMinimalIf,
+			expErrCode:      errs.ErrMinimalIf,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			uscript, err := parseShortForm(test.unlockingScript)
+			require.NoError(t, err)
+			lscript, err := parseShortForm(test.lockingScript)
+			require.NoError(t, err)
+
+			execute := func(version uint32, flags scriptflag.Flag) error {
+				tx := createSpendingTx(uscript, lscript, 0)
+				tx.Version = version
+				return NewEngine().Execute(
+					WithTx(tx, 0, &bt.Output{LockingScript: lscript}),
+					WithFlags(scriptflag.UTXOAfterGenesis|test.flags|flags),
+				)
+			}
+
+			err = execute(1, scriptflag.EnableChronicle)
+			assert.True(t, errs.IsErrorCode(err, test.expErrCode), "version 1 tx: %v", err)
+
+			err = execute(2, 0)
+			assert.True(t, errs.IsErrorCode(err, test.expErrCode), "version 2 tx before Chronicle: %v", err)
+
+			require.NoError(t, execute(2, scriptflag.EnableChronicle))
+		})
+	}
+}
+
+func TestChronicleVer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("pushes the tx version", func(t *testing.T) {
+		uscript, err := parseShortForm("0x04 0x02000000")
+		require.NoError(t, err)
+		lscript, err := parseShortForm("VER EQUALVERIFY 0x04 0x02000000 VERIF 1 ELSE 0 ENDIF")
+		require.NoError(t, err)
+
+		tx := createSpendingTx(uscript, lscript, 0)
+		tx.Version = 2
+
+		require.NoError(t, NewEngine().Execute(
+			WithTx(tx, 0, &bt.Output{LockingScript: lscript}),
+			WithAfterGenesis(),
+			WithChronicle(),
+		))
+	})
+
+	t.Run("requires a tx", func(t *testing.T) {
+		uscript, err := parseShortForm("")
+		require.NoError(t, err)
+		lscript, err := parseShortForm("VER")
+		require.NoError(t, err)
+
+		err = NewEngine().Execute(
+			WithScripts(lscript, uscript),
+			WithAfterGenesis(),
+			WithChronicle(),
+		)
+		assert.True(t, errs.IsErrorCode(err, errs.ErrInvalidParams))
+	})
+}
+
+func TestChronicleOpcodeNames(t *testing.T) {
+	t.Parallel()
+
+	lscript, err := bscript.NewFromASM("OP_SUBSTR OP_LEFT OP_RIGHT OP_LSHIFTNUM OP_RSHIFTNUM")
+	require.NoError(t, err)
+
+	var p DefaultOpcodeParser
+	parsed, err := p.Parse(lscript)
+	require.NoError(t, err)
+
+	names := make([]string, len(parsed))
+	for i, op := range parsed {
+		names[i] = op.Name()
+	}
+	assert.Equal(t, []string{"OP_SUBSTR", "OP_LEFT", "OP_RIGHT", "OP_LSHIFTNUM", "OP_RSHIFTNUM"}, names)
+
+	asm, err := lscript.ToASM()
+	require.NoError(t, err)
+	assert.Equal(t, "OP_SUBSTR OP_LEFT OP_RIGHT OP_LSHIFTNUM OP_RSHIFTNUM", asm)
+}
+
+func TestWithNetwork(t *testing.T) {
+	t.Parallel()
+
+	params := &chaincfg.MainNetParams
+	tests := map[string]struct {
+		utxoHeight  uint32
+		blockHeight uint32
+		expFlags    scriptflag.Flag
+	}{
+		"before fork id": {
+			utxoHeight:  params.UAHFHeight - 10,
+			blockHeight: params.UAHFHeight - 1,
+		},
+		"fork id": {
+			utxoHeight:  params.GenesisHeight - 10,
+			blockHeight: params.UAHFHeight,
+			expFlags:    scriptflag.EnableSighashForkID,
+		},
+		"spending pre-genesis utxo after genesis": {
+			utxoHeight:  params.GenesisHeight - 1,
+			blockHeight: params.GenesisHeight,
+			expFlags:    scriptflag.EnableSighashForkID,
+		},
+		"genesis": {
+			utxoHeight:  params.GenesisHeight,
+			blockHeight: params.GenesisHeight,
+			expFlags:    scriptflag.EnableSighashForkID | scriptflag.UTXOAfterGenesis,
+		},
+		"chronicle": {
+			utxoHeight:  params.GenesisHeight,
+			blockHeight: params.ChronicleHeight,
+			expFlags:    scriptflag.EnableSighashForkID | scriptflag.UTXOAfterGenesis | scriptflag.EnableChronicle,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := &execOpts{}
+			WithNetwork(params, test.utxoHeight, test.blockHeight)(opts)
+			assert.Equal(t, test.expFlags, opts.flags)
+		})
+	}
+
+	t.Run("chronicle opcodes", func(t *testing.T) {
+		uscript, err := parseShortForm("2")
+		require.NoError(t, err)
+		lscript, err := parseShortForm("2MUL 4 EQUAL")
+		require.NoError(t, err)
+
+		execute := func(blockHeight uint32) error {
+			tx := createSpendingTx(uscript, lscript, 0)
+			return NewEngine().Execute(
+				WithTx(tx, 0, &bt.Output{LockingScript: lscript}),
+				WithNetwork(params, params.GenesisHeight, blockHeight),
+			)
+		}
+
+		require.NoError(t, execute(params.ChronicleHeight))
+		require.Error(t, execute(params.ChronicleHeight-1))
+	})
+}
@@ -1,6 +1,12 @@
 package interpreter
 
-import "math"
+import (
+	"fmt"
+	"math"
+
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter/errs"
+	"github.com/bsv-blockchain/go-bt/v2/chaincfg"
+)
 
 type config interface {
 	AfterGenesis() bool
@@ -10,8 +16,13 @@ type config interface {
 	MaxScriptElementSize() int
 	MaxScriptNumberLength() int
 	MaxPubKeysPerMultiSig() int
+	MaxStackMemoryUsage() int
 }
 
+// StackItemOverhead is the number of bytes each item on the stacks is counted
+// as using, on top of its length, when measuring stack memory usage.
+const StackItemOverhead = 32
+
 // Limits applied to transactions before genesis
 const (
 	MaxOpsBeforeGenesis                = 500
@@ -82,3 +93,155 @@ func (a *afterGenesisConfig) MaxPubKeysPerMultiSig() int {
 func (b *beforeGenesisConfig) MaxPubKeysPerMultiSig() int {
 	return MaxPubKeysPerMultiSigBeforeGenesis
 }
+
+func (a *afterGenesisConfig) MaxStackMemoryUsage() int {
+	return math.MaxInt
+}
+
+func (b *beforeGenesisConfig) MaxStackMemoryUsage() int {
+	return math.MaxInt
+}
+
+// Limits is a set of limits applied when executing scripts, such as the
+// consensus or policy limits of a node. A zero value for any limit leaves it
+// unset, in which case the limit of the genesis rules in effect applies.
+type Limits struct {
+	// MaxOps is the maximum number of non-push opcodes executed per script.
+	MaxOps int
+	// MaxStackSize is the maximum number of items on the data and alt stacks
+	// combined.
+	MaxStackSize int
+	// MaxScriptSize is the maximum size of a script in bytes.
+	MaxScriptSize int
+	// MaxScriptElementSize is the maximum size of a stack item in bytes.
+	MaxScriptElementSize int
+	// MaxScriptNumberLength is the maximum length of a number in bytes.
+	MaxScriptNumberLength int
+	// MaxPubKeysPerMultiSig is the maximum number of public keys of an
+	// OP_CHECKMULTISIG.
+	MaxPubKeysPerMultiSig int
+	// MaxStackMemoryUsage is the maximum number of bytes held on the data and
+	// alt stacks combined, with each item counted as its length plus
+	// StackItemOverhead.
+	MaxStackMemoryUsage int
+}
+
+// LimitsFromPolicy returns the Limits of the policy limits of a network, as
+// found in chaincfg.Params.
+func LimitsFromPolicy(p chaincfg.PolicyLimits) Limits {
+	return Limits{
+		MaxScriptSize:         p.MaxScriptSize,
+		MaxScriptNumberLength: p.MaxScriptNumLength,
+		MaxStackMemoryUsage:   p.MaxStackMemoryUsage,
+	}
+}
+
+// Names of the limits, used when describing them in errors.
+const (
+	limitMaxOps                = "max ops"
+	limitMaxStackSize          = "max stack size"
+	limitMaxScriptSize         = "max script size"
+	limitMaxScriptElementSize  = "max script element size"
+	limitMaxScriptNumberLength = "max script number length"
+	limitMaxPubKeysPerMultiSig = "max pubkeys per multisig"
+	limitMaxStackMemoryUsage   = "max stack memory usage"
+)
+
+// limitedConfig applies consensus and policy Limits on top of the limits of
+// the genesis rules in effect, recording which set imposes each limit so that
+// errors can say which limit was exceeded.
+type limitedConfig struct {
+	config
+	limits  map[string]int
+	sources map[string]string
+}
+
+func newLimitedConfig(base config, consensus, policy Limits) *limitedConfig {
+	c := &limitedConfig{
+		config: base,
+		limits: map[string]int{
+			limitMaxOps:                base.MaxOps(),
+			limitMaxStackSize:          base.MaxStackSize(),
+			limitMaxScriptSize:         base.MaxScriptSize(),
+			limitMaxScriptElementSize:  base.MaxScriptElementSize(),
+			limitMaxScriptNumberLength: base.MaxScriptNumberLength(),
+			limitMaxPubKeysPerMultiSig: base.MaxPubKeysPerMultiSig(),
+			limitMaxStackMemoryUsage:   base.MaxStackMemoryUsage(),
+		},
+		sources: make(map[string]string),
+	}
+
+	for _, set := range []struct {
+		name   string
+		limits Limits
+	}{{"consensus", consensus}, {"policy", policy}} {
+		for name, value := range map[string]int{
+			limitMaxOps:                set.limits.MaxOps,
+			limitMaxStackSize:          set.limits.MaxStackSize,
+			limitMaxScriptSize:         set.limits.MaxScriptSize,
+			limitMaxScriptElementSize:  set.limits.MaxScriptElementSize,
+			limitMaxScriptNumberLength: set.limits.MaxScriptNumberLength,
+			limitMaxPubKeysPerMultiSig: set.limits.MaxPubKeysPerMultiSig,
+			limitMaxStackMemoryUsage:   set.limits.MaxStackMemoryUsage,
+		} {
+			if value > 0 && value < c.limits[name] {
+				c.limits[name], c.sources[name] = value, set.name
+			}
+		}
+	}
+
+	return c
+}
+
+func (c *limitedConfig) MaxOps() int {
+	return c.limits[limitMaxOps]
+}
+
+func (c *limitedConfig) MaxStackSize() int {
+	return c.limits[limitMaxStackSize]
+}
+
+func (c *limitedConfig) MaxScriptSize() int {
+	return c.limits[limitMaxScriptSize]
+}
+
+func (c *limitedConfig) MaxScriptElementSize() int {
+	return c.limits[limitMaxScriptElementSize]
+}
+
+func (c *limitedConfig) MaxScriptNumberLength() int {
+	return c.limits[limitMaxScriptNumberLength]
+}
+
+func (c *limitedConfig) MaxPubKeysPerMultiSig() int {
+	return c.limits[limitMaxPubKeysPerMultiSig]
+}
+
+func (c *limitedConfig) MaxStackMemoryUsage() int {
+	return c.limits[limitMaxStackMemoryUsage]
+}
+
+// limitSource returns the name of the set of Limits imposing the named limit
+// of cfg. Limits not imposed by a set of Limits are those of the genesis
+// rules, which are consensus.
+func limitSource(cfg config, name string) string {
+	if c, ok := cfg.(*limitedConfig); ok && c.sources[name] != "" {
+		return c.sources[name]
+	}
+	return "consensus"
+}
+
+// describeLimit describes the named limit of cfg for use in errors, for
+// example "policy max script number length of 10000".
+func describeLimit(cfg config, name string, value int) string {
+	return fmt.Sprintf("%s %s of %d", limitSource(cfg, name), name, value)
+}
+
+// limitError returns an error of code c for exceeding the named limit of cfg,
+// its description formatted from desc and fmtArgs followed by "the" and the
+// description of the limit. The error records if the limit is a policy one.
+func limitError(cfg config, c errs.ErrorCode, name string, value int, desc string, fmtArgs ...interface{}) errs.Error {
+	e := errs.NewError(c, desc+" the %s", append(fmtArgs, describeLimit(cfg, name, value))...)
+	e.PolicyLimit = limitSource(cfg, name) == "policy"
+	return e
+}
@@ -15,7 +15,8 @@ type (
 )
 
 type debugOpts struct {
-	rewind bool
+	rewind      bool
+	rewindLimit int
 }
 
 // DefaultDebugger exposes attachment points via the way of functions, which
@@ -37,10 +38,14 @@ type DefaultDebugger interface {
 	AttachBeforeStackPop(fn ThreadStateFunc)
 	AttachAfterStackPop(fn StackFunc)
 
+	Rewinder
 	interpreter.Debugger
 }
 
 type debugger struct {
+	opts    *debugOpts
+	history history
+
 	beforeExecuteFns []ThreadStateFunc
 	afterExecuteFns  []ThreadStateFunc
 
@@ -83,6 +88,9 @@ func NewDebugger(oo ...DebuggerOptionFunc) DefaultDebugger {
 	}
 
 	return &debugger{
+		opts:    opts,
+		history: history{limit: opts.rewindLimit},
+
 		beforeExecuteFns: make([]ThreadStateFunc, 0),
 		afterExecuteFns:  make([]ThreadStateFunc, 0),
 
@@ -220,6 +228,9 @@ func (d *debugger) AttachAfterStackPop(fn StackFunc) {
 
 // BeforeExecute execute all before execute attachments.
 func (d *debugger) BeforeExecute(state *interpreter.State) {
+	if d.opts.rewind {
+		d.history.reset()
+	}
 	for _, fn := range d.beforeExecuteFns {
 		fn(state)
 	}
@@ -227,6 +238,9 @@ func (d *debugger) BeforeExecute(state *interpreter.State) {
 
 // AfterExecute execute all after execute attachments.
 func (d *debugger) AfterExecute(state *interpreter.State) {
+	if d.opts.rewind {
+		d.history.record(state)
+	}
 	for _, fn := range d.afterExecuteFns {
 		fn(state)
 	}
@@ -234,6 +248,9 @@ func (d *debugger) AfterExecute(state *interpreter.State) {
 
 // BeforeStep execute all before step attachments.
 func (d *debugger) BeforeStep(state *interpreter.State) {
+	if d.opts.rewind {
+		d.history.record(state)
+	}
 	for _, fn := range d.beforeStepFns {
 		fn(state)
 	}
@@ -4,9 +4,23 @@ package debug
 type DebuggerOptionFunc func(o *debugOpts)
 
 // WithRewind configure the debugger to enable rewind functionality. When
-// enabled, the debugger will save each stack frame from BeforeStep to memory.
+// enabled, the debugger will save each stack frame from BeforeStep to memory,
+// along with the final frame from AfterExecute, which can then be navigated
+// through the Rewinder functions.
 func WithRewind() DebuggerOptionFunc {
 	return func(o *debugOpts) {
 		o.rewind = true
 	}
 }
+
+// WithRewindLimit configure the debugger to enable rewind functionality, keeping
+// at most around maxBytes of stack frames in memory. The earliest frames are
+// discarded once the limit is reached, with the size of a frame estimated from
+// the items on its stacks and its scripts, which are held once for all frames
+// while they are unchanged.
+func WithRewindLimit(maxBytes int) DebuggerOptionFunc {
+	return func(o *debugOpts) {
+		o.rewind = true
+		o.rewindLimit = maxBytes
+	}
+}
@@ -0,0 +1,244 @@
+package debug
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter"
+)
+
+// Static errors for err113 linter compliance
+var (
+	ErrRewindDisabled  = errors.New("rewind is not enabled, see WithRewind")
+	ErrStepUnavailable = errors.New("step is not in the rewind history")
+)
+
+// Rewinder navigates the states recorded by a debugger configured WithRewind,
+// being the state before each step of an execution followed by the state once
+// it finished.
+//
+// Steps are numbered from 0 for the first step of the execution. When the
+// history is capped by WithRewindLimit, the earliest steps are discarded and
+// are no longer available, but the numbering of the remaining steps is kept.
+type Rewinder interface {
+	// Steps returns the number of steps recorded, including any discarded.
+	Steps() int
+	// Current returns the step the rewinder is at and its state, which after
+	// an execution is the last step recorded.
+	Current() (int, *interpreter.State, error)
+	// StepBack moves the rewinder to the previous step, returning its state.
+	StepBack() (*interpreter.State, error)
+	// StepForward moves the rewinder to the next step, returning its state.
+	StepForward() (*interpreter.State, error)
+	// JumpTo moves the rewinder to the given step, returning its state.
+	JumpTo(step int) (*interpreter.State, error)
+	// Diff returns the differences between the stacks of two steps.
+	Diff(from, to int) (*StackDiff, error)
+}
+
+// StackDiff are the differences between the stacks of two steps.
+type StackDiff struct {
+	From      int
+	To        int
+	DataStack ItemsDiff
+	AltStack  ItemsDiff
+}
+
+// ItemsDiff is the difference between a stack at two steps. As items are pushed
+// and popped from the top of a stack, it is described by the number of items at
+// the bottom of the stack which are unchanged, followed by the items above them
+// which were removed and added.
+type ItemsDiff struct {
+	// Unchanged is the number of items from the bottom of the stack which are
+	// equal at both steps.
+	Unchanged int
+	// Removed are the items above those unchanged at the from step.
+	Removed [][]byte
+	// Added are the items above those unchanged at the to step.
+	Added [][]byte
+}
+
+// Changed returns true if the stack differs between the steps.
+func (d ItemsDiff) Changed() bool {
+	return len(d.Removed) > 0 || len(d.Added) > 0
+}
+
+// history is the record of states kept for rewinding.
+type history struct {
+	states []*interpreter.State
+	sizes  []int
+	first  int // step of states[0]
+	cursor int
+	size   int
+	limit  int
+}
+
+func (h *history) reset() {
+	*h = history{limit: h.limit}
+}
+
+func (h *history) record(state *interpreter.State) {
+	size := stateSize(state)
+
+	// The scripts of an execution only change by a P2SH script being appended,
+	// so while their shape is unchanged the copy held by the previous state is
+	// shared in place of a new one.
+	if len(h.states) > 0 && sameShape(h.states[len(h.states)-1].Scripts, state.Scripts) {
+		state.Scripts = h.states[len(h.states)-1].Scripts
+	} else {
+		size += scriptsSize(state.Scripts)
+	}
+
+	h.states = append(h.states, state)
+	h.sizes = append(h.sizes, size)
+	h.size += size
+
+	// Discard the earliest states beyond the limit, always keeping the latest.
+	for h.limit > 0 && h.size > h.limit && len(h.states) > 1 {
+		size = h.sizes[0]
+		if sharesScripts(h.states[0], h.states[1]) {
+			// The scripts are still held by the next state, which now counts them.
+			scripts := scriptsSize(h.states[0].Scripts)
+			size -= scripts
+			h.sizes[1] += scripts
+		}
+		h.size -= size
+		h.states[0] = nil
+		h.states, h.sizes = h.states[1:], h.sizes[1:]
+		h.first++
+	}
+
+	h.cursor = h.first + len(h.states) - 1
+}
+
+func (h *history) state(step int) (*interpreter.State, error) {
+	if step < h.first || step >= h.first+len(h.states) {
+		return nil, fmt.Errorf("%w: %d", ErrStepUnavailable, step)
+	}
+	return h.states[step-h.first], nil
+}
+
+// condItemSize is the number of bytes each item of the condition stack is
+// counted as using.
+const condItemSize = 8
+
+// stateSize estimates the memory held by a state from the items of its stacks,
+// not including its scripts.
+func stateSize(state *interpreter.State) int {
+	size := len(state.CondStack) * condItemSize
+	for _, stack := range [][][]byte{state.DataStack, state.AltStack, state.ElseStack, state.SavedFirstStack} {
+		for _, item := range stack {
+			size += len(item) + interpreter.StackItemOverhead
+		}
+	}
+	return size
+}
+
+// scriptsSize estimates the memory held by the scripts of a state, each opcode
+// being counted as its data plus interpreter.StackItemOverhead.
+func scriptsSize(scripts []interpreter.ParsedScript) int {
+	var size int
+	for _, script := range scripts {
+		for _, op := range script {
+			size += len(op.Data) + interpreter.StackItemOverhead
+		}
+	}
+	return size
+}
+
+// sameShape returns true if the scripts have the same number of opcodes.
+func sameShape(a, b []interpreter.ParsedScript) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// sharesScripts returns true if the states hold the same copy of the scripts.
+func sharesScripts(a, b *interpreter.State) bool {
+	return len(a.Scripts) > 0 && len(b.Scripts) > 0 && &a.Scripts[0] == &b.Scripts[0]
+}
+
+// Steps returns the number of steps recorded, including any discarded.
+func (d *debugger) Steps() int {
+	return d.history.first + len(d.history.states)
+}
+
+// Current returns the step the rewinder is at and its state.
+func (d *debugger) Current() (int, *interpreter.State, error) {
+	state, err := d.rewindTo(d.history.cursor)
+	if err != nil {
+		return 0, nil, err
+	}
+	return d.history.cursor, state, nil
+}
+
+// StepBack moves the rewinder to the previous step, returning its state.
+func (d *debugger) StepBack() (*interpreter.State, error) {
+	return d.rewindTo(d.history.cursor - 1)
+}
+
+// StepForward moves the rewinder to the next step, returning its state.
+func (d *debugger) StepForward() (*interpreter.State, error) {
+	return d.rewindTo(d.history.cursor + 1)
+}
+
+// JumpTo moves the rewinder to the given step, returning its state.
+func (d *debugger) JumpTo(step int) (*interpreter.State, error) {
+	return d.rewindTo(step)
+}
+
+// Diff returns the differences between the stacks of two steps.
+func (d *debugger) Diff(from, to int) (*StackDiff, error) {
+	if !d.opts.rewind {
+		return nil, ErrRewindDisabled
+	}
+
+	fromState, err := d.history.state(from)
+	if err != nil {
+		return nil, err
+	}
+	toState, err := d.history.state(to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StackDiff{
+		From:      from,
+		To:        to,
+		DataStack: diffItems(fromState.DataStack, toState.DataStack),
+		AltStack:  diffItems(fromState.AltStack, toState.AltStack),
+	}, nil
+}
+
+func (d *debugger) rewindTo(step int) (*interpreter.State, error) {
+	if !d.opts.rewind {
+		return nil, ErrRewindDisabled
+	}
+
+	state, err := d.history.state(step)
+	if err != nil {
+		return nil, err
+	}
+	d.history.cursor = step
+
+	return state, nil
+}
+
+func diffItems(from, to [][]byte) ItemsDiff {
+	var unchanged int
+	for unchanged < len(from) && unchanged < len(to) && string(from[unchanged]) == string(to[unchanged]) {
+		unchanged++
+	}
+
+	return ItemsDiff{
+		Unchanged: unchanged,
+		Removed:   from[unchanged:],
+		Added:     to[unchanged:],
+	}
+}
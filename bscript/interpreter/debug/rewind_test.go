@@ -0,0 +1,162 @@
+package debug_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter/debug"
+)
+
+func executeWithDebugger(t *testing.T, debugger debug.DefaultDebugger) {
+	t.Helper()
+
+	lockingScript, err := bscript.NewFromASM("OP_ADD OP_5 OP_EQUAL")
+	require.NoError(t, err)
+	unlockingScript, err := bscript.NewFromASM("OP_2 OP_3")
+	require.NoError(t, err)
+
+	require.NoError(t, interpreter.NewEngine().Execute(
+		interpreter.WithScripts(lockingScript, unlockingScript),
+		interpreter.WithAfterGenesis(),
+		interpreter.WithDebugger(debugger),
+	))
+}
+
+func TestDebugger_Rewind(t *testing.T) {
+	t.Parallel()
+
+	t.Run("step back and forward", func(t *testing.T) {
+		debugger := debug.NewDebugger(debug.WithRewind())
+		executeWithDebugger(t, debugger)
+
+		// One state before each of the 5 steps, and the final state.
+		require.Equal(t, 6, debugger.Steps())
+
+		step, state, err := debugger.Current()
+		require.NoError(t, err)
+		assert.Equal(t, 5, step)
+		assert.Equal(t, [][]byte{{1}}, state.DataStack)
+
+		state, err = debugger.StepBack()
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{{5}, {5}}, state.DataStack)
+
+		state, err = debugger.JumpTo(2)
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{{2}, {3}}, state.DataStack)
+		assert.Equal(t, "OP_ADD", state.Opcode().Name())
+
+		state, err = debugger.StepForward()
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{{5}}, state.DataStack)
+
+		_, err = debugger.JumpTo(6)
+		require.ErrorIs(t, err, debug.ErrStepUnavailable)
+		_, err = debugger.JumpTo(0)
+		require.NoError(t, err)
+		_, err = debugger.StepBack()
+		require.ErrorIs(t, err, debug.ErrStepUnavailable)
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		debugger := debug.NewDebugger(debug.WithRewind())
+		executeWithDebugger(t, debugger)
+
+		diff, err := debugger.Diff(2, 4)
+		require.NoError(t, err)
+		assert.Equal(t, debug.ItemsDiff{
+			Removed: [][]byte{{2}, {3}},
+			Added:   [][]byte{{5}, {5}},
+		}, diff.DataStack)
+		assert.False(t, diff.AltStack.Changed())
+
+		diff, err = debugger.Diff(1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 1, diff.DataStack.Unchanged)
+		assert.Empty(t, diff.DataStack.Removed)
+		assert.Equal(t, [][]byte{{3}}, diff.DataStack.Added)
+	})
+
+	t.Run("limit discards earliest steps", func(t *testing.T) {
+		// The 5 opcodes of the scripts, held once, and 3 single byte items.
+		debugger := debug.NewDebugger(debug.WithRewindLimit(
+			5*interpreter.StackItemOverhead + 3*(1+interpreter.StackItemOverhead),
+		))
+		executeWithDebugger(t, debugger)
+
+		assert.Equal(t, 6, debugger.Steps())
+
+		_, err := debugger.JumpTo(3)
+		require.ErrorIs(t, err, debug.ErrStepUnavailable)
+
+		state, err := debugger.JumpTo(4)
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{{5}, {5}}, state.DataStack)
+	})
+
+	t.Run("scripts are counted once", func(t *testing.T) {
+		debugger := debug.NewDebugger(debug.WithRewind())
+		executeWithDebugger(t, debugger)
+
+		first, err := debugger.JumpTo(0)
+		require.NoError(t, err)
+		last, err := debugger.JumpTo(5)
+		require.NoError(t, err)
+		assert.Same(t, &first.Scripts[0][0], &last.Scripts[0][0])
+
+		// Without room for the scripts, only the latest step is kept.
+		debugger = debug.NewDebugger(debug.WithRewindLimit(4 * interpreter.StackItemOverhead))
+		executeWithDebugger(t, debugger)
+
+		_, err = debugger.JumpTo(4)
+		require.ErrorIs(t, err, debug.ErrStepUnavailable)
+		_, err = debugger.JumpTo(5)
+		require.NoError(t, err)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		debugger := debug.NewDebugger()
+		executeWithDebugger(t, debugger)
+
+		assert.Equal(t, 0, debugger.Steps())
+		_, err := debugger.StepBack()
+		require.ErrorIs(t, err, debug.ErrRewindDisabled)
+		_, err = debugger.Diff(0, 1)
+		require.ErrorIs(t, err, debug.ErrRewindDisabled)
+	})
+
+	t.Run("resume session from rewound state", func(t *testing.T) {
+		lockingScript, err := bscript.NewFromASM("OP_ADD OP_5 OP_EQUAL")
+		require.NoError(t, err)
+		unlockingScript, err := bscript.NewFromASM("OP_2 OP_3")
+		require.NoError(t, err)
+
+		debugger := debug.NewDebugger(debug.WithRewind())
+		s, err := interpreter.NewSession(
+			interpreter.WithScripts(lockingScript, unlockingScript),
+			interpreter.WithAfterGenesis(),
+			interpreter.WithDebugger(debugger),
+		)
+		require.NoError(t, err)
+
+		_, err = s.RunUntil(interpreter.AtOpcode(bscript.OpEQUAL))
+		require.NoError(t, err)
+
+		state, err := debugger.JumpTo(1)
+		require.NoError(t, err)
+		s.SetState(state)
+		assert.Equal(t, [][]byte{{2}}, s.DataStack())
+
+		s.SetDataStack([][]byte{{1}, {4}})
+		s.SetState(state)
+		assert.Equal(t, [][]byte{{2}}, s.DataStack())
+
+		done, err := s.RunUntil()
+		require.NoError(t, err)
+		assert.True(t, done)
+	})
+}
@@ -0,0 +1,247 @@
+package debug
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter"
+)
+
+// TraceEntry records the execution of a single opcode.
+//
+// The stacks are those once the opcode executed, or if it failed, those at the
+// point it failed.
+type TraceEntry struct {
+	// Step is the index of the entry within the trace.
+	Step int
+	// Opcode is the name of the opcode, for example OP_CHECKSIG.
+	Opcode string
+	// ScriptIdx is the index of the script, being 0 for the unlocking script
+	// and 1 for the locking script.
+	ScriptIdx int
+	// OpcodeIdx is the index of the opcode within the script.
+	OpcodeIdx int
+	// DataStack are the items of the data stack, with the top of the stack last.
+	DataStack [][]byte
+	// AltStack are the items of the alt stack, with the top of the stack last.
+	AltStack [][]byte
+	// CondStack is the state of each conditional block being executed.
+	CondStack []int
+	// NumOps is the number of non-push operations executed in the script so far.
+	NumOps int
+	// Error is set if the opcode failed.
+	Error string
+}
+
+type traceEntryJSON struct {
+	Step      int      `json:"step"`
+	Opcode    string   `json:"opcode"`
+	ScriptIdx int      `json:"scriptIdx"`
+	OpcodeIdx int      `json:"opcodeIdx"`
+	DataStack []string `json:"dataStack"`
+	AltStack  []string `json:"altStack"`
+	CondStack []int    `json:"condStack"`
+	NumOps    int      `json:"numOps"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// MarshalJSON will serialize a trace entry to json, with stack items encoded
+// as hex.
+func (e TraceEntry) MarshalJSON() ([]byte, error) {
+	condStack := e.CondStack
+	if condStack == nil {
+		condStack = []int{}
+	}
+
+	return json.Marshal(traceEntryJSON{
+		Step:      e.Step,
+		Opcode:    e.Opcode,
+		ScriptIdx: e.ScriptIdx,
+		OpcodeIdx: e.OpcodeIdx,
+		DataStack: encodeItems(e.DataStack),
+		AltStack:  encodeItems(e.AltStack),
+		CondStack: condStack,
+		NumOps:    e.NumOps,
+		Error:     e.Error,
+	})
+}
+
+// UnmarshalJSON will unmarshal a trace entry that has been marshaled with this
+// library.
+func (e *TraceEntry) UnmarshalJSON(b []byte) error {
+	var ej traceEntryJSON
+	if err := json.Unmarshal(b, &ej); err != nil {
+		return err
+	}
+
+	dataStack, err := decodeItems(ej.DataStack)
+	if err != nil {
+		return err
+	}
+	altStack, err := decodeItems(ej.AltStack)
+	if err != nil {
+		return err
+	}
+
+	*e = TraceEntry{
+		Step:      ej.Step,
+		Opcode:    ej.Opcode,
+		ScriptIdx: ej.ScriptIdx,
+		OpcodeIdx: ej.OpcodeIdx,
+		DataStack: dataStack,
+		AltStack:  altStack,
+		CondStack: ej.CondStack,
+		NumOps:    ej.NumOps,
+		Error:     ej.Error,
+	}
+
+	return nil
+}
+
+// Trace is the record of an execution, one entry per opcode executed.
+type Trace struct {
+	Entries []TraceEntry `json:"entries"`
+	// Error is the outcome of the execution, being empty if it succeeded.
+	Error string `json:"error,omitempty"`
+}
+
+// WriteJSON writes the trace to w as a single json object.
+func (t *Trace) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(t)
+}
+
+// WriteNDJSON writes the entries of the trace to w as newline delimited json,
+// one entry per line. The outcome of the execution is not written, other than
+// the error of the opcode which failed, if any.
+func (t *Trace) WriteNDJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, e := range t.Entries {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadNDJSON reads a trace written by Trace.WriteNDJSON from r.
+func ReadNDJSON(r io.Reader) (*Trace, error) {
+	trace := &Trace{}
+	dec := json.NewDecoder(bufio.NewReader(r))
+	for {
+		var e TraceEntry
+		if err := dec.Decode(&e); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		trace.Entries = append(trace.Entries, e)
+		if e.Error != "" {
+			trace.Error = e.Error
+		}
+	}
+	return trace, nil
+}
+
+// Tracer is a DefaultDebugger which records a TraceEntry for each opcode
+// executed. The trace is reset at the start of each execution, and a Tracer
+// must not be shared by concurrent executions.
+//
+// Tracer example:
+//
+//	tracer := debug.NewTracer()
+//	if err := interpreter.NewEngine().Execute(
+//	    interpreter.WithTx(tx, inputIdx, previousOutput),
+//	    interpreter.WithForkID(),
+//	    interpreter.WithAfterGenesis(),
+//	    interpreter.WithDebugger(tracer),
+//	); err != nil {
+//	    _ = tracer.Trace().WriteNDJSON(os.Stderr)
+//	}
+type Tracer struct {
+	DefaultDebugger
+
+	trace   Trace
+	pending *TraceEntry
+}
+
+// NewTracer returns a Tracer, configured by the same options as NewDebugger.
+// Further functions can be attached to it as with any DefaultDebugger.
+func NewTracer(oo ...DebuggerOptionFunc) *Tracer {
+	t := &Tracer{DefaultDebugger: NewDebugger(oo...)}
+
+	t.AttachBeforeExecute(t.reset)
+	t.AttachBeforeExecuteOpcode(t.begin)
+	t.AttachAfterExecuteOpcode(t.end)
+	// An opcode returning early moves onto the next script without the
+	// AfterExecuteOpcode attachments being called.
+	t.AttachBeforeScriptChange(t.end)
+	t.AttachAfterError(t.fail)
+
+	return t
+}
+
+// Trace returns the trace of the last execution.
+func (t *Tracer) Trace() *Trace {
+	return &t.trace
+}
+
+func (t *Tracer) reset(_ *interpreter.State) {
+	t.trace = Trace{}
+	t.pending = nil
+}
+
+func (t *Tracer) begin(state *interpreter.State) {
+	t.pending = &TraceEntry{
+		Step:      len(t.trace.Entries),
+		Opcode:    state.Opcode().Name(),
+		ScriptIdx: state.ScriptIdx,
+		OpcodeIdx: state.OpcodeIdx,
+	}
+}
+
+func (t *Tracer) end(state *interpreter.State) {
+	if t.pending == nil {
+		return
+	}
+
+	e := t.pending
+	e.DataStack = state.DataStack
+	e.AltStack = state.AltStack
+	e.CondStack = state.CondStack
+	e.NumOps = state.NumOps
+
+	t.trace.Entries = append(t.trace.Entries, *e)
+	t.pending = nil
+}
+
+func (t *Tracer) fail(state *interpreter.State, err error) {
+	if t.pending != nil {
+		t.pending.Error = err.Error()
+		t.end(state)
+	}
+	t.trace.Error = err.Error()
+}
+
+func encodeItems(items [][]byte) []string {
+	ss := make([]string, len(items))
+	for i, item := range items {
+		ss[i] = hex.EncodeToString(item)
+	}
+	return ss
+}
+
+func decodeItems(ss []string) ([][]byte, error) {
+	items := make([][]byte, len(ss))
+	for i, s := range ss {
+		item, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = item
+	}
+	return items, nil
+}
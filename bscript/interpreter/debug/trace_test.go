@@ -0,0 +1,126 @@
+package debug_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter/debug"
+)
+
+func TestTracer(t *testing.T) {
+	t.Parallel()
+
+	execute := func(t *testing.T, tracer *debug.Tracer, unlockingASM, lockingASM string) error {
+		t.Helper()
+
+		lockingScript, err := bscript.NewFromASM(lockingASM)
+		require.NoError(t, err)
+		unlockingScript, err := bscript.NewFromASM(unlockingASM)
+		require.NoError(t, err)
+
+		return interpreter.NewEngine().Execute(
+			interpreter.WithScripts(lockingScript, unlockingScript),
+			interpreter.WithAfterGenesis(),
+			interpreter.WithDebugger(tracer),
+		)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		tracer := debug.NewTracer()
+		require.NoError(t, execute(t, tracer, "OP_2 OP_3", "OP_TOALTSTACK OP_1 OP_IF OP_FROMALTSTACK OP_ADD OP_ENDIF OP_5 OP_EQUAL"))
+
+		trace := tracer.Trace()
+		assert.Empty(t, trace.Error)
+		require.Len(t, trace.Entries, 10)
+
+		assert.Equal(t, debug.TraceEntry{
+			Step:      2,
+			Opcode:    "OP_TOALTSTACK",
+			ScriptIdx: 1,
+			OpcodeIdx: 0,
+			DataStack: [][]byte{{2}},
+			AltStack:  [][]byte{{3}},
+			CondStack: []int{},
+			NumOps:    1,
+		}, trace.Entries[2])
+
+		assert.Equal(t, "OP_IF", trace.Entries[4].Opcode)
+		assert.Equal(t, []int{1}, trace.Entries[4].CondStack)
+		assert.Equal(t, 4, trace.Entries[6].NumOps)
+		assert.Equal(t, "OP_EQUAL", trace.Entries[9].Opcode)
+		assert.Equal(t, [][]byte{{1}}, trace.Entries[9].DataStack)
+	})
+
+	t.Run("opcode failure", func(t *testing.T) {
+		tracer := debug.NewTracer()
+		err := execute(t, tracer, "OP_2", "OP_ADD")
+		require.Error(t, err)
+
+		trace := tracer.Trace()
+		assert.Equal(t, err.Error(), trace.Error)
+		require.Len(t, trace.Entries, 2)
+		assert.Equal(t, "OP_ADD", trace.Entries[1].Opcode)
+		assert.Equal(t, err.Error(), trace.Entries[1].Error)
+	})
+
+	t.Run("failed outcome", func(t *testing.T) {
+		tracer := debug.NewTracer()
+		err := execute(t, tracer, "OP_2 OP_3", "OP_ADD OP_6 OP_EQUAL")
+		require.Error(t, err)
+
+		trace := tracer.Trace()
+		assert.Equal(t, err.Error(), trace.Error)
+		require.Len(t, trace.Entries, 5)
+		for _, e := range trace.Entries {
+			assert.Empty(t, e.Error)
+		}
+	})
+
+	t.Run("early return", func(t *testing.T) {
+		tracer := debug.NewTracer()
+		require.NoError(t, execute(t, tracer, "OP_1", "OP_RETURN OP_2"))
+
+		trace := tracer.Trace()
+		require.Len(t, trace.Entries, 2)
+		assert.Equal(t, "OP_RETURN", trace.Entries[1].Opcode)
+		assert.Equal(t, [][]byte{{1}}, trace.Entries[1].DataStack)
+	})
+
+	t.Run("reset between executions", func(t *testing.T) {
+		tracer := debug.NewTracer()
+		require.Error(t, execute(t, tracer, "OP_2", "OP_ADD"))
+		require.NoError(t, execute(t, tracer, "OP_1", "OP_NOP"))
+
+		trace := tracer.Trace()
+		assert.Empty(t, trace.Error)
+		assert.Len(t, trace.Entries, 2)
+	})
+
+	t.Run("json and ndjson", func(t *testing.T) {
+		tracer := debug.NewTracer()
+		require.Error(t, execute(t, tracer, "OP_2 68656c6c6f OP_TOALTSTACK", "OP_ADD"))
+		trace := tracer.Trace()
+
+		var buf bytes.Buffer
+		require.NoError(t, trace.WriteJSON(&buf))
+		assert.Contains(t, buf.String(), `"altStack":["68656c6c6f"]`)
+
+		var fromJSON debug.Trace
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &fromJSON))
+		assert.Equal(t, trace, &fromJSON)
+
+		buf.Reset()
+		require.NoError(t, trace.WriteNDJSON(&buf))
+		assert.Equal(t, len(trace.Entries), bytes.Count(buf.Bytes(), []byte("\n")))
+
+		fromNDJSON, err := debug.ReadNDJSON(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, trace, fromNDJSON)
+	})
+}
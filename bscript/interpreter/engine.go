@@ -5,9 +5,12 @@
 
 package interpreter
 
+import "context"
+
 // Engine is the virtual machine that executes scripts.
 type Engine interface {
 	Execute(opts ...ExecutionOptionFunc) error
+	ExecuteContext(ctx context.Context, opts ...ExecutionOptionFunc) error
 }
 
 type engine struct{}
@@ -43,6 +46,32 @@ func NewEngine() Engine {
 //	    // handle err
 //	}
 func (e *engine) Execute(oo ...ExecutionOptionFunc) error {
+	return e.ExecuteContext(context.Background(), oo...)
+}
+
+// ExecuteContext is Execute, checking between opcodes whether the context is
+// done. If it is canceled, an errs.Error with code ErrCanceled is returned, and
+// if its deadline passes, one with code ErrDeadlineExceeded.
+//
+// Untrusted scripts should be executed with a context deadline or WithDeadline,
+// along with WithMaxOpcodes and WithMaxStackAllocation, so that a script looping
+// over large pushes cannot exhaust the cpu or memory of the host.
+//
+// ExecuteContext example:
+//
+//	ctx, cancel := context.WithTimeout(ctx, time.Second)
+//	defer cancel()
+//
+//	if err := engine.ExecuteContext(ctx,
+//	    interpreter.WithTx(tx, inputIdx, previousOutput),
+//	    interpreter.WithAfterGenesis(),
+//	    interpreter.WithForkID(),
+//	    interpreter.WithMaxOpcodes(1_000_000),
+//	    interpreter.WithMaxStackAllocation(100_000_000),
+//	); err != nil {
+//	    // handle err
+//	}
+func (e *engine) ExecuteContext(ctx context.Context, oo ...ExecutionOptionFunc) error {
 	opts := &execOpts{}
 	for _, o := range oo {
 		o(opts)
@@ -53,7 +82,7 @@ func (e *engine) Execute(oo ...ExecutionOptionFunc) error {
 		return err
 	}
 
-	if err := t.execute(); err != nil {
+	if err := t.execute(ctx); err != nil {
 		t.afterError(err)
 		return err
 	}
@@ -5,8 +5,11 @@
 package interpreter
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -1003,3 +1006,181 @@ func TestExecute(t *testing.T) {
 		})
 	}
 }
+
+func TestEngine_WithLimits(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		unlockingScript string
+		lockingScript   string
+		consensus       Limits
+		policy          Limits
+		expErrCode      errs.ErrorCode
+		expErrDesc      string
+	}{
+		"within limits": {
+			unlockingScript: "0x04 0x01020304",
+			lockingScript:   "1 ADD DROP 1",
+			policy:          Limits{MaxScriptNumberLength: 4},
+		},
+		"policy script number length": {
+			unlockingScript: "0x05 0x0102030405",
+			lockingScript:   "1 ADD DROP 1",
+			consensus:       Limits{MaxScriptNumberLength: 100},
+			policy:          Limits{MaxScriptNumberLength: 4},
+			expErrCode:      errs.ErrNumberTooBig,
+			expErrDesc:      "numeric value of 5 bytes exceeds the policy max script number length of 4",
+		},
+		"consensus limit lower than policy": {
+			unlockingScript: "1",
+			lockingScript:   "NOP NOP NOP",
+			consensus:       Limits{MaxOps: 2},
+			policy:          Limits{MaxOps: 10},
+			expErrCode:      errs.ErrTooManyOperations,
+			expErrDesc:      "exceeded the consensus max ops of 2",
+		},
+		"stack memory usage": {
+			unlockingScript: "0x05 0x0102030405",
+			lockingScript:   "DUP DUP DROP DROP",
+			policy:          Limits{MaxStackMemoryUsage: 2 * (5 + StackItemOverhead)},
+			expErrCode:      errs.ErrStackMemoryUsage,
+			expErrDesc:      "stack memory usage 111 exceeds the policy max stack memory usage of 74",
+		},
+		"script size": {
+			unlockingScript: "1",
+			lockingScript:   "1 1 DROP",
+			policy:          Limits{MaxScriptSize: 2},
+			expErrCode:      errs.ErrScriptTooBig,
+			expErrDesc:      "locking script size 3 exceeds the policy max script size of 2",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			uscript, err := parseShortForm(test.unlockingScript)
+			require.NoError(t, err)
+			lscript, err := parseShortForm(test.lockingScript)
+			require.NoError(t, err)
+
+			err = NewEngine().Execute(
+				WithScripts(lscript, uscript),
+				WithAfterGenesis(),
+				WithLimits(test.consensus, test.policy),
+			)
+			if test.expErrDesc == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			var e errs.Error
+			require.ErrorAs(t, err, &e)
+			require.Equal(t, test.expErrCode, e.ErrorCode)
+			require.Equal(t, test.expErrDesc, e.Description)
+		})
+	}
+}
+
+func TestEngine_ExecuteContext(t *testing.T) {
+	t.Parallel()
+
+	uscript, err := parseShortForm("0x05 0x0102030405")
+	require.NoError(t, err)
+	lscript, err := parseShortForm("DUP CAT DUP CAT DROP 1")
+	require.NoError(t, err)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	tests := map[string]struct {
+		ctx        context.Context
+		opts       []ExecutionOptionFunc
+		expErrCode errs.ErrorCode
+	}{
+		"within budget": {
+			ctx: context.Background(),
+			opts: []ExecutionOptionFunc{
+				WithMaxOpcodes(7),
+				WithMaxStackAllocation(36),
+				WithDeadline(time.Now().Add(time.Hour)),
+			},
+		},
+		"canceled": {
+			ctx:        canceled,
+			expErrCode: errs.ErrCanceled,
+		},
+		"context deadline exceeded": {
+			ctx:        expired,
+			expErrCode: errs.ErrDeadlineExceeded,
+		},
+		"deadline exceeded": {
+			ctx:        context.Background(),
+			opts:       []ExecutionOptionFunc{WithDeadline(time.Now().Add(-time.Second))},
+			expErrCode: errs.ErrDeadlineExceeded,
+		},
+		"opcode budget exceeded": {
+			ctx:        context.Background(),
+			opts:       []ExecutionOptionFunc{WithMaxOpcodes(6)},
+			expErrCode: errs.ErrOpcodeBudgetExceeded,
+		},
+		"allocation budget exceeded": {
+			ctx:        context.Background(),
+			opts:       []ExecutionOptionFunc{WithMaxStackAllocation(35)},
+			expErrCode: errs.ErrAllocationBudgetExceeded,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := append([]ExecutionOptionFunc{
+				WithScripts(lscript, uscript),
+				WithAfterGenesis(),
+			}, test.opts...)
+
+			err := NewEngine().ExecuteContext(test.ctx, opts...)
+			if test.expErrCode == errs.ErrInternal {
+				require.NoError(t, err)
+				return
+			}
+			require.True(t, errs.IsErrorCode(err, test.expErrCode), "expected %s, got %v", test.expErrCode, err)
+		})
+	}
+}
+
+func TestEngine_MaxStackAllocation(t *testing.T) {
+	t.Parallel()
+
+	// Two small items and a 1000 byte one, to be rearranged or concatenated.
+	uscript := &bscript.Script{}
+	require.NoError(t, uscript.AppendOpcodes(bscript.Op1, bscript.Op2))
+	require.NoError(t, uscript.AppendPushData(bytes.Repeat([]byte{0x01}, 1000)))
+
+	newLockingScript := func(ops ...byte) *bscript.Script {
+		s := &bscript.Script{}
+		for range 20 {
+			require.NoError(t, s.AppendOpcodes(ops...))
+		}
+		require.NoError(t, s.AppendOpcodes(bscript.Op2DROP, bscript.OpDROP, bscript.Op1))
+		return s
+	}
+
+	execute := func(lscript *bscript.Script) error {
+		return NewEngine().Execute(
+			WithScripts(lscript, uscript),
+			WithAfterGenesis(),
+			WithMaxStackAllocation(10000),
+		)
+	}
+
+	t.Run("rearranging items is not allocating", func(t *testing.T) {
+		require.NoError(t, execute(newLockingScript(
+			bscript.OpSWAP, bscript.OpROT, bscript.OpTOALTSTACK, bscript.OpFROMALTSTACK,
+		)))
+	})
+
+	t.Run("concatenating items is allocating", func(t *testing.T) {
+		err := execute(newLockingScript(bscript.Op1, bscript.OpCAT))
+		require.True(t, errs.IsErrorCode(err, errs.ErrAllocationBudgetExceeded), "got %v", err)
+	})
+}
@@ -325,6 +325,34 @@ const (
 	// set, but the ScriptEnableSighashForkID flag is not set.
 	ErrIllegalForkID
 
+	// ---------------------------------------------
+	// Failures related to exceeding execution budgets.
+	// ---------------------------------------------
+
+	// ErrCanceled is returned when the context of the execution is canceled.
+	ErrCanceled
+
+	// ErrDeadlineExceeded is returned when the deadline of the execution, or
+	// of its context, passes before the scripts finish executing.
+	ErrDeadlineExceeded
+
+	// ErrOpcodeBudgetExceeded is returned when more opcodes are executed than
+	// the maximum allowed for the execution.
+	ErrOpcodeBudgetExceeded
+
+	// ErrAllocationBudgetExceeded is returned when more bytes are allocated on
+	// the stack and altstack than the maximum allowed for the execution.
+	ErrAllocationBudgetExceeded
+
+	// -----------------------------------------------------------------
+	// Failures related to exceeding maximum allowed limits, appended so
+	// that the codes above keep their values.
+	// -----------------------------------------------------------------
+
+	// ErrStackMemoryUsage is returned when the number of bytes held by the
+	// stack and altstack combined is over the limit.
+	ErrStackMemoryUsage
+
 	// numErrorCodes is the maximum error code number used in tests.  This
 	// entry MUST be the last entry in the enum.
 	numErrorCodes
@@ -394,6 +422,11 @@ var errorCodeStrings = map[ErrorCode]string{
 	ErrNegativeLockTime:         "ErrNegativeLockTime",
 	ErrUnsatisfiedLockTime:      "ErrUnsatisfiedLockTime",
 	ErrIllegalForkID:            "ErrIllegalForkID",
+	ErrCanceled:                 "ErrCanceled",
+	ErrDeadlineExceeded:         "ErrDeadlineExceeded",
+	ErrOpcodeBudgetExceeded:     "ErrOpcodeBudgetExceeded",
+	ErrAllocationBudgetExceeded: "ErrAllocationBudgetExceeded",
+	ErrStackMemoryUsage:         "ErrStackMemoryUsage",
 }
 
 // String returns the ErrorCode as a human-readable name.
@@ -418,6 +451,10 @@ func (e ErrorCode) String() string {
 type Error struct {
 	ErrorCode   ErrorCode
 	Description string
+
+	// PolicyLimit is true if the error was raised by exceeding a limit set
+	// as policy, rather than one of consensus.
+	PolicyLimit bool
 }
 
 // Error satisfies the error interface and prints human-readable errors.
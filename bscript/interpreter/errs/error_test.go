@@ -78,6 +78,11 @@ func TestErrorCodeStringer(t *testing.T) {
 		{ErrNegativeLockTime, "ErrNegativeLockTime"},
 		{ErrUnsatisfiedLockTime, "ErrUnsatisfiedLockTime"},
 		{ErrIllegalForkID, "ErrIllegalForkID"},
+		{ErrCanceled, "ErrCanceled"},
+		{ErrDeadlineExceeded, "ErrDeadlineExceeded"},
+		{ErrOpcodeBudgetExceeded, "ErrOpcodeBudgetExceeded"},
+		{ErrAllocationBudgetExceeded, "ErrAllocationBudgetExceeded"},
+		{ErrStackMemoryUsage, "ErrStackMemoryUsage"},
 		{0xffff, "Unknown ErrorCode (65535)"},
 	}
 
@@ -126,3 +131,37 @@ func TestError(t *testing.T) {
 		}
 	}
 }
+
+// TestRejectReason tests the output of RejectReason and the ScriptError of an
+// ErrorCode.
+func TestRejectReason(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   RejectReason
+		want string
+	}{
+		{
+			RejectReason{Category: RejectMandatory, ErrorCode: ErrNullFail},
+			"mandatory-script-verify-flag-failed (Signature must be zero for failed CHECK(MULTI)SIG operation)",
+		},
+		{
+			RejectReason{Category: RejectNonMandatory, ErrorCode: ErrMinimalData},
+			"non-mandatory-script-verify-flag (Data push larger than necessary)",
+		},
+		{
+			RejectReason{Category: RejectMandatory, ErrorCode: ErrEmptyStack},
+			"mandatory-script-verify-flag-failed (Script evaluated without error but finished with a false/empty top stack element)",
+		},
+		{
+			RejectReason{Category: RejectNonMandatory, ErrorCode: ErrDeadlineExceeded},
+			"non-mandatory-script-verify-flag (unknown error)",
+		},
+	}
+
+	for i, test := range tests {
+		if result := test.in.String(); result != test.want {
+			t.Errorf("RejectReason #%d\n got: %s want: %s", i, result, test.want)
+		}
+	}
+}
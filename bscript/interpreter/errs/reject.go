@@ -0,0 +1,130 @@
+package errs
+
+import "fmt"
+
+// RejectCategory is the category of reason a node gives for rejecting a tx
+// with a failing script.
+type RejectCategory int
+
+// Categories of reject reasons.
+const (
+	// RejectMandatory is given when a script fails the consensus rules, so
+	// the tx is invalid.
+	RejectMandatory RejectCategory = iota
+
+	// RejectNonMandatory is given when a script fails only the policy rules
+	// of the node, so the tx is valid but non-standard.
+	RejectNonMandatory
+)
+
+// Reject codes sent by nodes in reject messages.
+const (
+	RejectCodeInvalid     = 0x10
+	RejectCodeNonStandard = 0x40
+)
+
+// String returns the prefix of the reject reason given by bitcoin-sv.
+func (c RejectCategory) String() string {
+	if c == RejectNonMandatory {
+		return "non-mandatory-script-verify-flag"
+	}
+	return "mandatory-script-verify-flag-failed"
+}
+
+// RejectCode returns the code nodes send in reject messages for the category.
+func (c RejectCategory) RejectCode() byte {
+	if c == RejectNonMandatory {
+		return RejectCodeNonStandard
+	}
+	return RejectCodeInvalid
+}
+
+// RejectReason is the reason a node gives for rejecting a tx with a failing
+// script.
+type RejectReason struct {
+	Category  RejectCategory
+	ErrorCode ErrorCode
+}
+
+// String returns the reject reason as given by bitcoin-sv, for example
+// "mandatory-script-verify-flag-failed (Signature must be zero for failed
+// CHECK(MULTI)SIG operation)".
+func (r RejectReason) String() string {
+	return fmt.Sprintf("%s (%s)", r.Category, r.ErrorCode.ScriptError())
+}
+
+// ScriptError returns the description bitcoin-sv gives the script error the
+// ErrorCode corresponds to. The node is less fine-grained with its errors than
+// the interpreter, so several ErrorCodes share a description. ErrorCodes with
+// no counterpart in the node return "unknown error".
+func (e ErrorCode) ScriptError() string {
+	if s := scriptErrorStrings[e]; s != "" {
+		return s
+	}
+	return "unknown error"
+}
+
+const (
+	scriptErrEvalFalse = "Script evaluated without error but finished with a false/empty top stack element"
+	scriptErrStackSize = "Stack size limit exceeded"
+	scriptErrBadOpcode = "Opcode missing or not understood"
+	scriptErrSigDER    = "Non-canonical DER signature"
+)
+
+// scriptErrorStrings map ErrorCodes to the descriptions of the script errors
+// of bitcoin-sv, see ScriptErrorString in script_error.cpp.
+var scriptErrorStrings = map[ErrorCode]string{
+	ErrOK:                       "No error",
+	ErrEarlyReturn:              "OP_RETURN was encountered",
+	ErrEmptyStack:               scriptErrEvalFalse,
+	ErrEvalFalse:                scriptErrEvalFalse,
+	ErrScriptTooBig:             "Script is too big",
+	ErrElementTooBig:            "Push value size limit exceeded",
+	ErrTooManyOperations:        "Operation limit exceeded",
+	ErrStackOverflow:            scriptErrStackSize,
+	ErrStackMemoryUsage:         scriptErrStackSize,
+	ErrInvalidPubKeyCount:       "Pubkey count negative or limit exceeded",
+	ErrInvalidSignatureCount:    "Signature count negative or greater than pubkey count",
+	ErrNumberTooBig:             "Script number overflow",
+	ErrNumberTooSmall:           "Given operand is not a number within the valid range [-2^31...2^31]",
+	ErrDivideByZero:             "Division by zero error",
+	ErrVerify:                   "Script failed an OP_VERIFY operation",
+	ErrEqualVerify:              "Script failed an OP_EQUALVERIFY operation",
+	ErrNumEqualVerify:           "Script failed an OP_NUMEQUALVERIFY operation",
+	ErrCheckSigVerify:           "Script failed an OP_CHECKSIGVERIFY operation",
+	ErrCheckMultiSigVerify:      "Script failed an OP_CHECKMULTISIGVERIFY operation",
+	ErrDisabledOpcode:           "Attempted to use a disabled opcode",
+	ErrReservedOpcode:           scriptErrBadOpcode,
+	ErrMalformedPush:            scriptErrBadOpcode,
+	ErrInvalidStackOperation:    "Operation not valid with the current stack size",
+	ErrUnbalancedConditional:    "Invalid OP_IF construction",
+	ErrInvalidInputLength:       "Invalid operand size",
+	ErrMinimalData:              "Data push larger than necessary",
+	ErrMinimalIf:                "OP_IF/NOTIF argument must be minimal",
+	ErrInvalidSigHashType:       "Signature hash type missing or not understood",
+	ErrSigTooShort:              scriptErrSigDER,
+	ErrSigTooLong:               scriptErrSigDER,
+	ErrSigInvalidSeqID:          scriptErrSigDER,
+	ErrSigInvalidDataLen:        scriptErrSigDER,
+	ErrSigMissingSTypeID:        scriptErrSigDER,
+	ErrSigMissingSLen:           scriptErrSigDER,
+	ErrSigInvalidSLen:           scriptErrSigDER,
+	ErrSigInvalidRIntID:         scriptErrSigDER,
+	ErrSigZeroRLen:              scriptErrSigDER,
+	ErrSigNegativeR:             scriptErrSigDER,
+	ErrSigTooMuchRPadding:       scriptErrSigDER,
+	ErrSigInvalidSIntID:         scriptErrSigDER,
+	ErrSigZeroSLen:              scriptErrSigDER,
+	ErrSigNegativeS:             scriptErrSigDER,
+	ErrSigTooMuchSPadding:       scriptErrSigDER,
+	ErrSigHighS:                 "Non-canonical signature: S value is unnecessarily high",
+	ErrNotPushOnly:              "Only non-push operators allowed in signatures",
+	ErrSigNullDummy:             "Dummy CHECKMULTISIG argument must be zero",
+	ErrPubKeyType:               "Public key is neither compressed or uncompressed",
+	ErrCleanStack:               "Extra items left on stack after execution",
+	ErrNullFail:                 "Signature must be zero for failed CHECK(MULTI)SIG operation",
+	ErrDiscourageUpgradableNOPs: "NOPx reserved for soft-fork upgrades",
+	ErrNegativeLockTime:         "Negative locktime",
+	ErrUnsatisfiedLockTime:      "Locktime requirement not satisfied",
+	ErrIllegalForkID:            "Illegal use of SIGHASH_FORKID",
+}
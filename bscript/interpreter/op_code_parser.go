@@ -84,6 +84,16 @@ func (o *ParsedOpcode) IsConditional() bool {
 	}
 }
 
+// CheckMinimalDataPush returns an errs.Error with code ErrMinimalData if the
+// op is a data push which does not use the smallest push operator possible.
+func (o *ParsedOpcode) CheckMinimalDataPush() error {
+	if o.op.val > bscript.OpPUSHDATA4 {
+		return nil
+	}
+
+	return o.enforceMinimumDataPush()
+}
+
 // enforceMinimumDataPush checks that the op is pushing only the needed amount of data.
 // Errs if not the case.
 func (o *ParsedOpcode) enforceMinimumDataPush() error {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"crypto/sha1" //nolint:gosec // OP_SHA1 support requires this
 	"crypto/sha256"
+	"encoding/binary"
 	"hash"
 	"math/big"
 
@@ -142,11 +143,11 @@ var opcodeArray = [256]opcode{
 
 	// Control opcodes.
 	bscript.OpNOP:                 {bscript.OpNOP, "OP_NOP", 1, opcodeNop},
-	bscript.OpVER:                 {bscript.OpVER, "OP_VER", 1, opcodeReserved},
+	bscript.OpVER:                 {bscript.OpVER, "OP_VER", 1, opcodeVer},
 	bscript.OpIF:                  {bscript.OpIF, "OP_IF", 1, opcodeIf},
 	bscript.OpNOTIF:               {bscript.OpNOTIF, "OP_NOTIF", 1, opcodeNotIf},
-	bscript.OpVERIF:               {bscript.OpVERIF, "OP_VERIF", 1, opcodeVerConditional},
-	bscript.OpVERNOTIF:            {bscript.OpVERNOTIF, "OP_VERNOTIF", 1, opcodeVerConditional},
+	bscript.OpVERIF:               {bscript.OpVERIF, "OP_VERIF", 1, opcodeVerIf},
+	bscript.OpVERNOTIF:            {bscript.OpVERNOTIF, "OP_VERNOTIF", 1, opcodeVerNotIf},
 	bscript.OpELSE:                {bscript.OpELSE, "OP_ELSE", 1, opcodeElse},
 	bscript.OpENDIF:               {bscript.OpENDIF, "OP_ENDIF", 1, opcodeEndif},
 	bscript.OpVERIFY:              {bscript.OpVERIFY, "OP_VERIFY", 1, opcodeVerify},
@@ -195,8 +196,8 @@ var opcodeArray = [256]opcode{
 	// Numeric related opcodes.
 	bscript.Op1ADD:               {bscript.Op1ADD, "OP_1ADD", 1, opcode1Add},
 	bscript.Op1SUB:               {bscript.Op1SUB, "OP_1SUB", 1, opcode1Sub},
-	bscript.Op2MUL:               {bscript.Op2MUL, "OP_2MUL", 1, opcodeDisabled},
-	bscript.Op2DIV:               {bscript.Op2DIV, "OP_2DIV", 1, opcodeDisabled},
+	bscript.Op2MUL:               {bscript.Op2MUL, "OP_2MUL", 1, opcode2Mul},
+	bscript.Op2DIV:               {bscript.Op2DIV, "OP_2DIV", 1, opcode2Div},
 	bscript.OpNEGATE:             {bscript.OpNEGATE, "OP_NEGATE", 1, opcodeNegate},
 	bscript.OpABS:                {bscript.OpABS, "OP_ABS", 1, opcodeAbs},
 	bscript.OpNOT:                {bscript.OpNOT, "OP_NOT", 1, opcodeNot},
@@ -233,13 +234,15 @@ var opcodeArray = [256]opcode{
 	bscript.OpCHECKMULTISIG:       {bscript.OpCHECKMULTISIG, "OP_CHECKMULTISIG", 1, opcodeCheckMultiSig},
 	bscript.OpCHECKMULTISIGVERIFY: {bscript.OpCHECKMULTISIGVERIFY, "OP_CHECKMULTISIGVERIFY", 1, opcodeCheckMultiSigVerify},
 
+	// Chronicle opcodes, which are OP_NOP4 to OP_NOP8 before Chronicle.
+	bscript.OpSUBSTR:    {bscript.OpSUBSTR, "OP_SUBSTR", 1, opcodeSubstr},
+	bscript.OpLEFT:      {bscript.OpLEFT, "OP_LEFT", 1, opcodeLeft},
+	bscript.OpRIGHT:     {bscript.OpRIGHT, "OP_RIGHT", 1, opcodeRight},
+	bscript.OpLSHIFTNUM: {bscript.OpLSHIFTNUM, "OP_LSHIFTNUM", 1, opcodeLShiftNum},
+	bscript.OpRSHIFTNUM: {bscript.OpRSHIFTNUM, "OP_RSHIFTNUM", 1, opcodeRShiftNum},
+
 	// Reserved opcodes.
 	bscript.OpNOP1:  {bscript.OpNOP1, "OP_NOP1", 1, opcodeNop},
-	bscript.OpNOP4:  {bscript.OpNOP4, "OP_NOP4", 1, opcodeNop},
-	bscript.OpNOP5:  {bscript.OpNOP5, "OP_NOP5", 1, opcodeNop},
-	bscript.OpNOP6:  {bscript.OpNOP6, "OP_NOP6", 1, opcodeNop},
-	bscript.OpNOP7:  {bscript.OpNOP7, "OP_NOP7", 1, opcodeNop},
-	bscript.OpNOP8:  {bscript.OpNOP8, "OP_NOP8", 1, opcodeNop},
 	bscript.OpNOP9:  {bscript.OpNOP9, "OP_NOP9", 1, opcodeNop},
 	bscript.OpNOP10: {bscript.OpNOP10, "OP_NOP10", 1, opcodeNop},
 
@@ -333,7 +336,8 @@ func opcodeDisabled(op *ParsedOpcode, _ *thread) error {
 	return errs.NewError(errs.ErrDisabledOpcode, "attempt to execute disabled opcode %s", op.Name())
 }
 
-// opcodeVerConditional is a common handler for the OP_VERIF and
+// opcodeVerConditional is a common handler for the OP_VERIF and OP_VERNOTIF
+// opcodes before Chronicle.
 func opcodeVerConditional(op *ParsedOpcode, t *thread) error {
 	if t.afterGenesis && !t.shouldExec(*op) {
 		return nil
@@ -341,6 +345,96 @@ func opcodeVerConditional(op *ParsedOpcode, t *thread) error {
 	return opcodeReserved(op, t)
 }
 
+// opcodeVer pushes the version of the tx to the data stack, as 4 little
+// endian bytes. Before Chronicle, it is a reserved opcode.
+//
+// Stack transformation: [...] -> [... version]
+func opcodeVer(op *ParsedOpcode, t *thread) error {
+	if !t.isChronicle() {
+		return opcodeReserved(op, t)
+	}
+
+	version, err := txVersionBytes(op, t)
+	if err != nil {
+		return err
+	}
+
+	t.dstack.PushByteArray(version)
+	return nil
+}
+
+// opcodeVerIf removes the top item of the data stack and compares it to the
+// version of the tx, as 4 little endian bytes. When they are equal, the first
+// branch will be executed, otherwise it acts as bscript.OpIF.
+//
+// <expression> verif [statements] [else [statements]] endif
+//
+// Data stack transformation: [... x] -> [...]
+// Conditional stack transformation: [...] -> [... OpCondValue]
+func opcodeVerIf(op *ParsedOpcode, t *thread) error {
+	if !t.isChronicle() {
+		return opcodeVerConditional(op, t)
+	}
+
+	return verConditional(op, t, true)
+}
+
+// opcodeVerNotIf removes the top item of the data stack and compares it to the
+// version of the tx, as 4 little endian bytes. When they are not equal, the
+// first branch will be executed, otherwise it acts as bscript.OpNOTIF.
+//
+// <expression> vernotif [statements] [else [statements]] endif
+//
+// Data stack transformation: [... x] -> [...]
+// Conditional stack transformation: [...] -> [... OpCondValue]
+func opcodeVerNotIf(op *ParsedOpcode, t *thread) error {
+	if !t.isChronicle() {
+		return opcodeVerConditional(op, t)
+	}
+
+	return verConditional(op, t, false)
+}
+
+// verConditional adds an entry to the conditional stack depending on whether
+// the top item of the data stack equals the tx version, and whether this is
+// on an executing branch.
+func verConditional(op *ParsedOpcode, t *thread, execIfEqual bool) error {
+	condVal := opCondFalse
+	if t.shouldExec(*op) {
+		if t.isBranchExecuting() {
+			b, err := t.dstack.PopByteArray()
+			if err != nil {
+				return err
+			}
+
+			version, err := txVersionBytes(op, t)
+			if err != nil {
+				return err
+			}
+
+			if bytes.Equal(b, version) == execIfEqual {
+				condVal = opCondTrue
+			}
+		} else {
+			condVal = opCondSkip
+		}
+	}
+
+	t.condStack = append(t.condStack, condVal)
+	t.elseStack.PushBool(false)
+	return nil
+}
+
+// txVersionBytes returns the version of the tx being executed as 4 little
+// endian bytes.
+func txVersionBytes(op *ParsedOpcode, t *thread) ([]byte, error) {
+	if t.tx == nil {
+		return nil, errs.NewError(errs.ErrInvalidParams, "tx must be supplied for %s", op.Name())
+	}
+
+	return binary.LittleEndian.AppendUint32(nil, t.tx.Version), nil
+}
+
 // opcodeReserved is a common handler for all reserved opcodes.  It returns an
 // appropriate error indicating the opcode is reserved.
 func opcodeReserved(op *ParsedOpcode, _ *thread) error {
@@ -774,7 +868,7 @@ func opcodeToAltStack(_ *ParsedOpcode, t *thread) error {
 		return err
 	}
 
-	t.astack.PushByteArray(so)
+	t.astack.push(so)
 
 	return nil
 }
@@ -790,7 +884,7 @@ func opcodeFromAltStack(_ *ParsedOpcode, t *thread) error {
 		return err
 	}
 
-	t.dstack.PushByteArray(so)
+	t.dstack.push(so)
 
 	return nil
 }
@@ -850,7 +944,7 @@ func opcodeIfDup(_ *ParsedOpcode, t *thread) error {
 
 	// Push copy of data iff it isn't zero
 	if asBool(so) {
-		t.dstack.PushByteArray(so)
+		t.dstack.push(so)
 	}
 
 	return nil
@@ -967,8 +1061,8 @@ func opcodeCat(_ *ParsedOpcode, t *thread) error {
 
 	c := bytes.Join([][]byte{a, b}, nil)
 	if len(c) > t.cfg.MaxScriptElementSize() {
-		return errs.NewError(errs.ErrElementTooBig,
-			"concatenated size %d exceeds max allowed size %d", len(c), t.cfg.MaxScriptElementSize())
+		return limitError(t.cfg, errs.ErrElementTooBig, limitMaxScriptElementSize, t.cfg.MaxScriptElementSize(),
+			"concatenated size %d exceeds", len(c))
 	}
 
 	t.dstack.PushByteArray(c)
@@ -1022,7 +1116,8 @@ func opcodeNum2bin(_ *ParsedOpcode, t *thread) error {
 	}
 
 	if n.GreaterThanInt(int64(t.cfg.MaxScriptElementSize())) {
-		return errs.NewError(errs.ErrNumberTooBig, "n is larger than the max of %d", t.cfg.MaxScriptElementSize())
+		return limitError(t.cfg, errs.ErrNumberTooBig, limitMaxScriptElementSize, t.cfg.MaxScriptElementSize(),
+			"n is larger than")
 	}
 
 	// encode a as a script num so that we we take the bytes it
@@ -1070,7 +1165,8 @@ func opcodeBin2num(_ *ParsedOpcode, t *thread) error {
 
 	b := minimallyEncode(a)
 	if len(b) > t.cfg.MaxScriptNumberLength() {
-		return errs.NewError(errs.ErrNumberTooBig, "script numbers are limited to %d bytes", t.cfg.MaxScriptNumberLength())
+		return limitError(t.cfg, errs.ErrNumberTooBig, limitMaxScriptNumberLength, t.cfg.MaxScriptNumberLength(),
+			"script number length %d exceeds", len(b))
 	}
 
 	t.dstack.PushByteArray(b)
@@ -1497,6 +1593,220 @@ func opcodeRShift(_ *ParsedOpcode, t *thread) error {
 	return nil
 }
 
+// opcode2Mul treats the top item on the data stack as an integer and replaces
+// it with its value multiplied by 2. Before Chronicle, it is a disabled opcode.
+//
+// Stack transformation: [... x1 x2] -> [... x1 x2*2]
+func opcode2Mul(op *ParsedOpcode, t *thread) error {
+	if !t.isChronicle() {
+		return opcodeDisabled(op, t)
+	}
+
+	m, err := t.dstack.PopInt()
+	if err != nil {
+		return err
+	}
+
+	m.val.Lsh(m.val, 1)
+	t.dstack.PushInt(m)
+	return nil
+}
+
+// opcode2Div treats the top item on the data stack as an integer and replaces
+// it with its value divided by 2, rounded towards zero. Before Chronicle, it
+// is a disabled opcode.
+//
+// Stack transformation: [... x1 x2] -> [... x1 x2/2]
+func opcode2Div(op *ParsedOpcode, t *thread) error {
+	if !t.isChronicle() {
+		return opcodeDisabled(op, t)
+	}
+
+	m, err := t.dstack.PopInt()
+	if err != nil {
+		return err
+	}
+
+	m.val.Quo(m.val, big.NewInt(2))
+	t.dstack.PushInt(m)
+	return nil
+}
+
+// opcodeSubstr replaces the top three items on the data stack with the size
+// bytes of x starting at begin. Before Chronicle, it is bscript.OpNOP4.
+//
+// Stack transformation: x begin size bscript.OpSUBSTR -> out
+func opcodeSubstr(op *ParsedOpcode, t *thread) error {
+	if !t.isChronicle() {
+		return opcodeNop(op, t)
+	}
+
+	size, err := t.dstack.PopInt()
+	if err != nil {
+		return err
+	}
+
+	begin, err := t.dstack.PopInt()
+	if err != nil {
+		return err
+	}
+
+	x, err := t.dstack.PopByteArray()
+	if err != nil {
+		return err
+	}
+
+	if begin.LessThanInt(0) || size.LessThanInt(0) {
+		return errs.NewError(errs.ErrNumberTooSmall, "begin and size must not be negative")
+	}
+	if begin.GreaterThanInt(int64(len(x))) || size.GreaterThanInt(int64(len(x))-begin.Int64()) {
+		return errs.NewError(errs.ErrNumberTooBig, "begin and size are out of range of array")
+	}
+
+	t.dstack.PushByteArray(x[begin.Int() : begin.Int()+size.Int()])
+	return nil
+}
+
+// opcodeLeft replaces the top two items on the data stack with the leftmost
+// size bytes of x. Before Chronicle, it is bscript.OpNOP5.
+//
+// Stack transformation: x size bscript.OpLEFT -> out
+func opcodeLeft(op *ParsedOpcode, t *thread) error {
+	if !t.isChronicle() {
+		return opcodeNop(op, t)
+	}
+
+	x, size, err := popSliceSize(t)
+	if err != nil {
+		return err
+	}
+
+	t.dstack.PushByteArray(x[:size])
+	return nil
+}
+
+// opcodeRight replaces the top two items on the data stack with the rightmost
+// size bytes of x. Before Chronicle, it is bscript.OpNOP6.
+//
+// Stack transformation: x size bscript.OpRIGHT -> out
+func opcodeRight(op *ParsedOpcode, t *thread) error {
+	if !t.isChronicle() {
+		return opcodeNop(op, t)
+	}
+
+	x, size, err := popSliceSize(t)
+	if err != nil {
+		return err
+	}
+
+	t.dstack.PushByteArray(x[len(x)-size:])
+	return nil
+}
+
+// popSliceSize pops a size and a byte array off the data stack, ensuring the
+// size is within the length of the array.
+func popSliceSize(t *thread) ([]byte, int, error) {
+	size, err := t.dstack.PopInt()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	x, err := t.dstack.PopByteArray()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if size.LessThanInt(0) {
+		return nil, 0, errs.NewError(errs.ErrNumberTooSmall, "size is negative")
+	}
+	if size.GreaterThanInt(int64(len(x))) {
+		return nil, 0, errs.NewError(errs.ErrNumberTooBig, "size is larger than length of array")
+	}
+
+	return x, size.Int(), nil
+}
+
+// opcodeLShiftNum treats the top two items on the data stack as integers and
+// replaces them with a shifted left by b bits, preserving the sign of a.
+// Before Chronicle, it is bscript.OpNOP7.
+//
+// Stack transformation: a b bscript.OpLSHIFTNUM -> out
+func opcodeLShiftNum(op *ParsedOpcode, t *thread) error {
+	if !t.isChronicle() {
+		return opcodeNop(op, t)
+	}
+
+	a, n, err := popShiftNum(t)
+	if err != nil {
+		return err
+	}
+
+	if !a.IsZero() {
+		if n.GreaterThanInt(int64(t.cfg.MaxScriptNumberLength()) * 8) {
+			return limitError(t.cfg, errs.ErrNumberTooBig, limitMaxScriptNumberLength, t.cfg.MaxScriptNumberLength(),
+				"shift of %d exceeds", n.Int64())
+		}
+		a.val.Lsh(a.val, uint(n.Int64()))
+		// The encoded length, including room for the sign bit.
+		if l := (a.val.BitLen() + 8) / 8; l > t.cfg.MaxScriptNumberLength() {
+			return limitError(t.cfg, errs.ErrNumberTooBig, limitMaxScriptNumberLength, t.cfg.MaxScriptNumberLength(),
+				"result length %d exceeds", l)
+		}
+	}
+
+	t.dstack.PushInt(a)
+	return nil
+}
+
+// opcodeRShiftNum treats the top two items on the data stack as integers and
+// replaces them with a shifted right by b bits, preserving the sign of a. The
+// result is rounded towards zero. Before Chronicle, it is bscript.OpNOP8.
+//
+// Stack transformation: a b bscript.OpRSHIFTNUM -> out
+func opcodeRShiftNum(op *ParsedOpcode, t *thread) error {
+	if !t.isChronicle() {
+		return opcodeNop(op, t)
+	}
+
+	a, n, err := popShiftNum(t)
+	if err != nil {
+		return err
+	}
+
+	if n.GreaterThanInt(int64(a.val.BitLen())) {
+		a.Set(0)
+	} else {
+		neg := a.val.Sign() < 0
+		a.val.Rsh(a.val.Abs(a.val), uint(n.Int64()))
+		if neg {
+			a.val.Neg(a.val)
+		}
+	}
+
+	t.dstack.PushInt(a)
+	return nil
+}
+
+// popShiftNum pops the number of bits to shift by, and the number to be
+// shifted, off the data stack.
+func popShiftNum(t *thread) (*scriptNumber, *scriptNumber, error) {
+	n, err := t.dstack.PopInt()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	a, err := t.dstack.PopInt()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if n.LessThanInt(0) {
+		return nil, nil, errs.NewError(errs.ErrNumberTooSmall, "n less than 0")
+	}
+
+	return a, n, nil
+}
+
 // opcodeBoolAnd treats the top two items on the data stack as integers.  When
 // both of them are not zero, they are replaced with a 1, otherwise a 0.
 //
@@ -1989,10 +2299,7 @@ func opcodeCheckSig(_ *ParsedOpcode, t *thread) error {
 		return err
 	}
 
-	txCopy := t.tx.ShallowClone()
-	txCopy.Inputs[t.inputIdx].PreviousTxScript = up
-
-	hashBytes, err = txCopy.CalcInputSignatureHash(uint32(t.inputIdx), shf)
+	hashBytes, err = t.signatureHash(up, shf)
 	if err != nil {
 		t.dstack.PushBool(false)
 		return err
@@ -2008,7 +2315,7 @@ func opcodeCheckSig(_ *ParsedOpcode, t *thread) error {
 	var ok bool
 	var signature *bec.Signature
 
-	if externalVerifySignatureFn != nil {
+	if externalVerifySignatureFn != nil && t.sigVerifier == nil {
 		if sigBytesDer == nil {
 			// signature is not in DER format, so we must parse it and set the bytes
 			signature, err = bec.ParseSignature(sigBytes)
@@ -2018,7 +2325,9 @@ func opcodeCheckSig(_ *ParsedOpcode, t *thread) error {
 			}
 			sigBytesDer = signature.Serialize()
 		}
-		ok = externalVerifySignatureFn(hashBytes, sigBytesDer, pkBytes)
+		ok = t.verifyCachedSignature(hashBytes, pkBytes, sigBytes, func() bool {
+			return externalVerifySignatureFn(hashBytes, sigBytesDer, pkBytes)
+		})
 	} else {
 		var pubKey *bec.PublicKey
 		pubKey, err = bec.ParsePubKey(pkBytes)
@@ -2037,7 +2346,9 @@ func opcodeCheckSig(_ *ParsedOpcode, t *thread) error {
 			return nil //nolint:nilerr // only need a false push in this case
 		}
 
-		ok = signature.Verify(hashBytes, pubKey)
+		ok = t.verifyCachedSignature(hashBytes, pkBytes, sigBytes, func() bool {
+			return t.signatureVerifier().Verify(hashBytes, signature, pubKey)
+		})
 	}
 
 	if !ok && t.hasFlag(scriptflag.VerifyNullFail) && len(sigBytes) > 0 {
@@ -2100,15 +2411,12 @@ func opcodeCheckMultiSig(_ *ParsedOpcode, t *thread) error {
 		return errs.NewError(errs.ErrInvalidPubKeyCount, "number of pubkeys %d is negative", numPubKeys)
 	}
 	if numPubKeys > t.cfg.MaxPubKeysPerMultiSig() {
-		return errs.NewError(
-			errs.ErrInvalidPubKeyCount,
-			"too many pubkeys: %d > %d",
-			numPubKeys, t.cfg.MaxPubKeysPerMultiSig(),
-		)
+		return limitError(t.cfg, errs.ErrInvalidPubKeyCount, limitMaxPubKeysPerMultiSig, t.cfg.MaxPubKeysPerMultiSig(),
+			"number of pubkeys %d exceeds", numPubKeys)
 	}
 	t.numOps += numPubKeys
 	if t.numOps > t.cfg.MaxOps() {
-		return errs.NewError(errs.ErrTooManyOperations, "exceeded max operation limit of %d", t.cfg.MaxOps())
+		return limitError(t.cfg, errs.ErrTooManyOperations, limitMaxOps, t.cfg.MaxOps(), "exceeded")
 	}
 
 	pubKeys := make([][]byte, 0, numPubKeys)
@@ -2252,16 +2560,15 @@ func opcodeCheckMultiSig(_ *ParsedOpcode, t *thread) error {
 		}
 
 		// Generate the signature hash based on the signature hash type.
-		txCopy := t.tx.ShallowClone()
-		txCopy.Inputs[t.inputIdx].PreviousTxScript = up
-
-		signatureHash, err := txCopy.CalcInputSignatureHash(uint32(t.inputIdx), shf)
+		signatureHash, err := t.signatureHash(up, shf)
 		if err != nil {
 			t.dstack.PushBool(false)
 			return nil //nolint:nilerr // only need a false push in this case
 		}
 
-		if ok := parsedSig.Verify(signatureHash, parsedPubKey); ok {
+		if t.verifyCachedSignature(signatureHash, pubKey, signature, func() bool {
+			return t.signatureVerifier().Verify(signatureHash, parsedSig, parsedPubKey)
+		}) {
 			// PubKey verified, move on to the next signature.
 			signatureIdx++
 			numSignatures--
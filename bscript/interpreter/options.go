@@ -1,9 +1,12 @@
 package interpreter
 
 import (
+	"time"
+
 	"github.com/bsv-blockchain/go-bt/v2"
 	"github.com/bsv-blockchain/go-bt/v2/bscript"
 	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter/scriptflag"
+	"github.com/bsv-blockchain/go-bt/v2/chaincfg"
 )
 
 // ExecutionOptionFunc for setting execution options.
@@ -47,6 +50,103 @@ func WithP2SH() ExecutionOptionFunc {
 	}
 }
 
+// WithChronicle configure the execution to operate with the Chronicle rules.
+func WithChronicle() ExecutionOptionFunc {
+	return func(p *execOpts) {
+		p.flags.AddFlag(scriptflag.EnableChronicle)
+	}
+}
+
+// WithNetwork configure the execution with the rules of the network in effect for
+// a tx mined, or to be mined, at blockHeight spending a utxo mined at utxoHeight.
+// Whether the utxo is after genesis is decided by utxoHeight, whereas the fork id
+// and Chronicle rules are decided by blockHeight.
+func WithNetwork(params *chaincfg.Params, utxoHeight, blockHeight uint32) ExecutionOptionFunc {
+	return func(p *execOpts) {
+		if params.IsGenesisActive(utxoHeight) {
+			p.flags.AddFlag(scriptflag.UTXOAfterGenesis)
+		}
+		if params.IsUAHFActive(blockHeight) {
+			p.flags.AddFlag(scriptflag.EnableSighashForkID)
+		}
+		if params.IsChronicleActive(blockHeight) {
+			p.flags.AddFlag(scriptflag.EnableChronicle)
+		}
+	}
+}
+
+// WithLimits configure the execution with the consensus and policy limits of a
+// node, such as those set by its maxscriptnumlengthpolicy and
+// maxstackmemoryusagepolicy settings. The lowest of each limit across both sets
+// and the genesis rules in effect applies, and an error from exceeding a limit
+// names it and whether it is a consensus or policy limit.
+func WithLimits(consensus, policy Limits) ExecutionOptionFunc {
+	return func(p *execOpts) {
+		p.limits = &execLimits{consensus: consensus, policy: policy}
+	}
+}
+
+// WithMaxOpcodes configure the maximum number of opcodes executed across all
+// scripts, including data pushes and opcodes in branches not taken. Exceeding
+// it returns an errs.Error with code ErrOpcodeBudgetExceeded.
+func WithMaxOpcodes(n int) ExecutionOptionFunc {
+	return func(p *execOpts) {
+		p.budget.maxOpcodes = n
+	}
+}
+
+// WithDeadline configure the time by which the execution must finish. Passing
+// it returns an errs.Error with code ErrDeadlineExceeded.
+func WithDeadline(d time.Time) ExecutionOptionFunc {
+	return func(p *execOpts) {
+		p.budget.deadline = d
+	}
+}
+
+// WithMaxStackAllocation configure the maximum number of bytes allocated on the
+// stack and altstack over the whole execution, regardless of how many are
+// popped. Only newly created data is counted, being the data pushed by the
+// scripts and the results of opcodes such as OP_CAT, while items which are
+// duplicated, rearranged or moved between the stacks are not. Exceeding it
+// returns an errs.Error with code ErrAllocationBudgetExceeded.
+func WithMaxStackAllocation(n int) ExecutionOptionFunc {
+	return func(p *execOpts) {
+		p.budget.maxStackAllocation = n
+	}
+}
+
+// WithSigCache configure the execution to look up signatures in, and add valid
+// signatures to, the provided SigCache. A cache shared between executions saves
+// verifying the same signature more than once.
+func WithSigCache(cache *SigCache) ExecutionOptionFunc {
+	return func(p *execOpts) {
+		p.sigCache = cache
+	}
+}
+
+// WithSignatureVerifier configure the execution to verify signatures with the
+// provided SignatureVerifier in place of the DefaultSignatureVerifier. It takes
+// precedence over a function set by InjectExternalVerifySignatureFn.
+//
+// The SigCache of the execution is neither consulted nor added to, so that a
+// verifier which skips verification cannot plant signatures in a cache shared
+// with other executions.
+func WithSignatureVerifier(v SignatureVerifier) ExecutionOptionFunc {
+	return func(p *execOpts) {
+		p.sigVerifier = v
+	}
+}
+
+// WithSigHashCache configure the execution to use the pre-computed hashes of
+// the provided bt.SigHashCache when calculating signature hashes. Sharing the
+// cache of a tx between the executions of each of its inputs saves hashing
+// the whole tx for every input. The tx must not be modified while it is in use.
+func WithSigHashCache(cache *bt.SigHashCache) ExecutionOptionFunc {
+	return func(p *execOpts) {
+		p.sigHashCache = cache
+	}
+}
+
 // WithFlags configure the execution with the provided flags.
 func WithFlags(flags scriptflag.Flag) ExecutionOptionFunc {
 	return func(p *execOpts) {
@@ -29,7 +29,9 @@ func init() { //nolint:gochecknoinits // this is in a test, still should be refa
 	// Initialize the opcode name to value map using the contents of the
 	// opcode array.  Also add entries for "OP_FALSE", "OP_TRUE", and
 	// "OP_NOP2" since they are aliases for "OP_0", "OP_1",
-	// and "OP_CHECKLOCKTIMEVERIFY" respectively.
+	// and "OP_CHECKLOCKTIMEVERIFY" respectively, and for "OP_NOP4" to
+	// "OP_NOP8" which are the pre Chronicle names of "OP_SUBSTR" to
+	// "OP_RSHIFTNUM".
 	for _, op := range &opcodeArray {
 		opcodeByName[op.Name()] = op.val
 	}
@@ -38,6 +40,11 @@ func init() { //nolint:gochecknoinits // this is in a test, still should be refa
 	opcodeByName["OP_CHECKLOCKTIMEVERIFY"] = bscript.OpCHECKLOCKTIMEVERIFY
 	opcodeByName["OP_CHECKSEQUENCEVERIFY"] = bscript.OpCHECKSEQUENCEVERIFY
 	opcodeByName["OP_RESERVED"] = bscript.OpRESERVED
+	opcodeByName["OP_NOP4"] = bscript.OpNOP4
+	opcodeByName["OP_NOP5"] = bscript.OpNOP5
+	opcodeByName["OP_NOP6"] = bscript.OpNOP6
+	opcodeByName["OP_NOP7"] = bscript.OpNOP7
+	opcodeByName["OP_NOP8"] = bscript.OpNOP8
 }
 
 // Test-specific errors
@@ -215,6 +222,8 @@ func parseScriptFlags(flagStr string) (scriptflag.Flag, error) {
 			flags |= scriptflag.VerifyMinimalIf
 		case "SIGHASH_FORKID":
 			flags |= scriptflag.EnableSighashForkID
+		case "CHRONICLE":
+			flags |= scriptflag.EnableChronicle
 		default:
 			return flags, fmt.Errorf("%w: %s", errInvalidFlag, flag)
 		}
@@ -359,7 +368,20 @@ func createSpendingTx(sigScript, pkScript *bscript.Script, outputValue int64) *b
 // TestScripts ensures all the tests in script_tests.json execute with the
 // expected results as defined in the test data.
 func TestScripts(t *testing.T) {
-	file, err := os.ReadFile("data/script_tests.json")
+	testScripts(t, "data/script_tests.json")
+}
+
+// TestChronicleScripts ensures all the tests in script_tests_chronicle.json
+// execute with the expected results as defined in the test data. Unlike
+// script_tests.json, these tests are written for this library rather than
+// imported from the node.
+func TestChronicleScripts(t *testing.T) {
+	testScripts(t, "data/script_tests_chronicle.json")
+}
+
+// testScripts executes the reference script tests in the file at path.
+func testScripts(t *testing.T, path string) {
+	file, err := os.ReadFile(path) //nolint:gosec // test data path
 	if err != nil {
 		t.Fatalf("TestScripts: %v\n", err)
 	}
@@ -489,6 +511,11 @@ func TestScripts(t *testing.T) {
 			t.Errorf("%s: want error codes %v, got err: %v (%T)", name, allowedErrorCodes, err, err)
 			continue
 		}
+
+		// Every failure has a reject reason naming the script error.
+		if reason, ok := RejectReasonFor(err, flags); !ok || reason.ErrorCode.ScriptError() == "unknown error" {
+			t.Errorf("%s: has reject reason %q for %v", name, reason, err)
+		}
 	}
 }
 
@@ -509,6 +536,68 @@ type txIOKey struct {
 	idx uint32
 }
 
+// txInvalidScriptErrors are the script errors the vectors of tx_invalid.json
+// are rejected with, keyed by their index in the file.
+var txInvalidScriptErrors = map[int]string{
+	6:   "Script failed an OP_VERIFY operation",
+	10:  "Script failed an OP_CHECKSIGVERIFY operation",
+	12:  "Script failed an OP_CHECKSIGVERIFY operation",
+	16:  "Script failed an OP_CHECKSIGVERIFY operation",
+	18:  "Opcode missing or not understood",
+	23:  "Script evaluated without error but finished with a false/empty top stack element",
+	27:  "Script evaluated without error but finished with a false/empty top stack element",
+	29:  "Script evaluated without error but finished with a false/empty top stack element",
+	41:  "Script evaluated without error but finished with a false/empty top stack element",
+	44:  "Script evaluated without error but finished with a false/empty top stack element",
+	47:  "Operation not valid with the current stack size",
+	51:  "Dummy CHECKMULTISIG argument must be zero",
+	53:  "Dummy CHECKMULTISIG argument must be zero",
+	55:  "Dummy CHECKMULTISIG argument must be zero",
+	57:  "Operation not valid with the current stack size",
+	59:  "Operation not valid with the current stack size",
+	62:  "Script failed an OP_CHECKSIGVERIFY operation",
+	64:  "Script failed an OP_CHECKSIGVERIFY operation",
+	67:  "Locktime requirement not satisfied",
+	68:  "Locktime requirement not satisfied",
+	70:  "Locktime requirement not satisfied",
+	71:  "Locktime requirement not satisfied",
+	73:  "Operation not valid with the current stack size",
+	74:  "Operation not valid with the current stack size",
+	76:  "Negative locktime",
+	78:  "Negative locktime",
+	79:  "Negative locktime",
+	81:  "Locktime requirement not satisfied",
+	82:  "Locktime requirement not satisfied",
+	84:  "Locktime requirement not satisfied",
+	86:  "Locktime requirement not satisfied",
+	87:  "Locktime requirement not satisfied",
+	88:  "Locktime requirement not satisfied",
+	89:  "Locktime requirement not satisfied",
+	90:  "Locktime requirement not satisfied",
+	92:  "Locktime requirement not satisfied",
+	94:  "Locktime requirement not satisfied",
+	96:  "Script number overflow",
+	98:  "Locktime requirement not satisfied",
+	100: "Locktime requirement not satisfied",
+	102: "Non-canonical DER signature",
+	105: "Locktime requirement not satisfied",
+	106: "Locktime requirement not satisfied",
+	108: "Locktime requirement not satisfied",
+	109: "Locktime requirement not satisfied",
+	111: "Operation not valid with the current stack size",
+	113: "Negative locktime",
+	115: "Negative locktime",
+	117: "Locktime requirement not satisfied",
+	118: "Locktime requirement not satisfied",
+	119: "Locktime requirement not satisfied",
+	120: "Locktime requirement not satisfied",
+	122: "Script number overflow",
+	124: "Locktime requirement not satisfied",
+	126: "Locktime requirement not satisfied",
+	128: "Locktime requirement not satisfied",
+	129: "Locktime requirement not satisfied",
+}
+
 // TestTxInvalidTests ensures all the tests in tx_invalid.json fail as expected.
 func TestTxInvalidTests(t *testing.T) {
 	file, err := os.ReadFile("data/tx_invalid.json")
@@ -647,6 +736,22 @@ testloop:
 				WithFlags(flags),
 			)
 			if err != nil {
+				// The reject reason must be mandatory if, and only if,
+				// the input also fails without the non-mandatory flags.
+				reason, ok := RejectReasonFor(err, flags)
+				if !ok || reason.ErrorCode.ScriptError() != txInvalidScriptErrors[i] {
+					t.Errorf("test (%d:%v) has reject reason %q for %v, want script error %q",
+						i, test, reason, err, txInvalidScriptErrors[i])
+					continue testloop
+				}
+				mandatoryErr := NewEngine().Execute(
+					WithTx(tx, k, prevOut),
+					WithFlags(flags&(MandatoryFlags|consensusFlags)),
+				)
+				if want := mandatoryErr != nil; want != (reason.Category == errs.RejectMandatory) {
+					t.Errorf("test (%d:%v) has reject reason %q for %v, mandatory err %v",
+						i, test, reason, err, mandatoryErr)
+				}
 				continue testloop
 			}
 		}
@@ -799,3 +904,12 @@ testloop:
 		}
 	}
 }
+
+// consensusFlags are the flags of the reference tests which are consensus
+// rules for the utxos they spend, besides MandatoryFlags.
+const consensusFlags = scriptflag.Bip16 |
+	scriptflag.VerifyCheckLockTimeVerify |
+	scriptflag.VerifyCheckSequenceVerify |
+	scriptflag.UTXOAfterGenesis |
+	scriptflag.VerifyBip143SigHash |
+	scriptflag.EnableChronicle
@@ -0,0 +1,95 @@
+package interpreter
+
+import (
+	"errors"
+
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter/errs"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter/scriptflag"
+)
+
+// MandatoryFlags are the script flags whose rules nodes enforce as consensus,
+// in addition to those of the genesis rules in effect. A script failing the
+// rules of any other flag fails only the policy of the node.
+const MandatoryFlags = scriptflag.EnableSighashForkID |
+	scriptflag.VerifyStrictEncoding |
+	scriptflag.VerifyLowS |
+	scriptflag.VerifyNullFail
+
+// policyFlags are the flags with rules which only raise the given ErrorCodes.
+// The signature encoding errors are also raised by VerifyStrictEncoding.
+var policyFlags = map[errs.ErrorCode]scriptflag.Flag{
+	errs.ErrMinimalData:              scriptflag.VerifyMinimalData,
+	errs.ErrMinimalIf:                scriptflag.VerifyMinimalIf,
+	errs.ErrCleanStack:               scriptflag.VerifyCleanStack,
+	errs.ErrDiscourageUpgradableNOPs: scriptflag.DiscourageUpgradableNops,
+	errs.ErrSigNullDummy:             scriptflag.StrictMultiSig,
+	errs.ErrNotPushOnly:              scriptflag.VerifySigPushOnly,
+	errs.ErrSigTooShort:              scriptflag.VerifyDERSignatures,
+	errs.ErrSigTooLong:               scriptflag.VerifyDERSignatures,
+	errs.ErrSigInvalidSeqID:          scriptflag.VerifyDERSignatures,
+	errs.ErrSigInvalidDataLen:        scriptflag.VerifyDERSignatures,
+	errs.ErrSigMissingSTypeID:        scriptflag.VerifyDERSignatures,
+	errs.ErrSigMissingSLen:           scriptflag.VerifyDERSignatures,
+	errs.ErrSigInvalidSLen:           scriptflag.VerifyDERSignatures,
+	errs.ErrSigInvalidRIntID:         scriptflag.VerifyDERSignatures,
+	errs.ErrSigZeroRLen:              scriptflag.VerifyDERSignatures,
+	errs.ErrSigNegativeR:             scriptflag.VerifyDERSignatures,
+	errs.ErrSigTooMuchRPadding:       scriptflag.VerifyDERSignatures,
+	errs.ErrSigInvalidSIntID:         scriptflag.VerifyDERSignatures,
+	errs.ErrSigZeroSLen:              scriptflag.VerifyDERSignatures,
+	errs.ErrSigNegativeS:             scriptflag.VerifyDERSignatures,
+	errs.ErrSigTooMuchSPadding:       scriptflag.VerifyDERSignatures,
+}
+
+// RejectReasonFor returns the reason a node gives for rejecting a tx with an
+// input failing execution with err, having been executed with flags.
+//
+// The reason is non-mandatory if the error was raised by a rule of a flag
+// outside of MandatoryFlags, or by exceeding a policy limit set WithLimits.
+// Otherwise, it is mandatory.
+//
+// False is returned if err is nil, is not an errs.Error, or is not an error of
+// the scripts, such as ErrInvalidParams or ErrScriptUnfinished. Nor is a reason
+// given for exceeding the budget of the execution, such as ErrCanceled or
+// ErrOpcodeBudgetExceeded, as these are local limits rather than rules of the
+// node.
+//
+// RejectReasonFor example:
+//
+//	if err := engine.Execute(opts...); err != nil {
+//	    if reason, ok := interpreter.RejectReasonFor(err, flags); ok {
+//	        // reject with reason.Category.RejectCode() and reason.String()
+//	    }
+//	}
+func RejectReasonFor(err error, flags scriptflag.Flag) (errs.RejectReason, bool) {
+	var e errs.Error
+	if err == nil || !errors.As(err, &e) {
+		return errs.RejectReason{}, false
+	}
+
+	switch e.ErrorCode {
+	case errs.ErrInternal, errs.ErrOK, errs.ErrInvalidFlags, errs.ErrInvalidIndex,
+		errs.ErrUnsupportedAddress, errs.ErrNotMultisigScript, errs.ErrTooManyRequiredSigs,
+		errs.ErrTooMuchNullData, errs.ErrInvalidParams, errs.ErrScriptUnfinished, errs.ErrInvalidProgramCounter,
+		errs.ErrCanceled, errs.ErrDeadlineExceeded, errs.ErrOpcodeBudgetExceeded, errs.ErrAllocationBudgetExceeded:
+		return errs.RejectReason{}, false
+	}
+
+	// The fork id flag implies strict encoding, see thread.apply.
+	if flags.HasFlag(scriptflag.EnableSighashForkID) {
+		flags.AddFlag(scriptflag.VerifyStrictEncoding)
+	}
+
+	category := errs.RejectMandatory
+	if f, ok := policyFlags[e.ErrorCode]; ok && flags.HasFlag(f) && !MandatoryFlags.HasFlag(f) {
+		category = errs.RejectNonMandatory
+		if f == scriptflag.VerifyDERSignatures && flags.HasFlag(scriptflag.VerifyStrictEncoding) {
+			category = errs.RejectMandatory
+		}
+	}
+	if e.PolicyLimit {
+		category = errs.RejectNonMandatory
+	}
+
+	return errs.RejectReason{Category: category, ErrorCode: e.ErrorCode}, true
+}
@@ -0,0 +1,164 @@
+package interpreter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter/errs"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter/scriptflag"
+)
+
+func TestRejectReasonFor(t *testing.T) {
+	t.Parallel()
+
+	execute := func(t *testing.T, unlockingASM, lockingASM string, flags scriptflag.Flag, oo ...interpreter.ExecutionOptionFunc) error {
+		t.Helper()
+
+		lockingScript, err := bscript.NewFromASM(lockingASM)
+		require.NoError(t, err)
+		unlockingScript, err := bscript.NewFromASM(unlockingASM)
+		require.NoError(t, err)
+
+		return interpreter.NewEngine().Execute(append([]interpreter.ExecutionOptionFunc{
+			interpreter.WithScripts(lockingScript, unlockingScript),
+			interpreter.WithFlags(flags),
+		}, oo...)...)
+	}
+
+	tests := map[string]struct {
+		unlockingASM string
+		lockingASM   string
+		flags        scriptflag.Flag
+		opts         []interpreter.ExecutionOptionFunc
+		expReason    string
+		expCode      byte
+	}{
+		"consensus rule": {
+			unlockingASM: "OP_1",
+			lockingASM:   "OP_0 OP_VERIFY",
+			flags:        scriptflag.UTXOAfterGenesis,
+			expReason:    "mandatory-script-verify-flag-failed (Script failed an OP_VERIFY operation)",
+			expCode:      errs.RejectCodeInvalid,
+		},
+		"policy flag": {
+			unlockingASM: "OP_1 OP_1",
+			lockingASM:   "OP_NOP",
+			flags:        scriptflag.UTXOAfterGenesis | scriptflag.VerifyCleanStack | scriptflag.Bip16,
+			expReason:    "non-mandatory-script-verify-flag (Extra items left on stack after execution)",
+			expCode:      errs.RejectCodeNonStandard,
+		},
+		"policy limit": {
+			unlockingASM: "0000000001",
+			lockingASM:   "OP_1 OP_ADD",
+			flags:        scriptflag.UTXOAfterGenesis,
+			opts: []interpreter.ExecutionOptionFunc{
+				interpreter.WithLimits(interpreter.Limits{}, interpreter.Limits{MaxScriptNumberLength: 4}),
+			},
+			expReason: "non-mandatory-script-verify-flag (Script number overflow)",
+			expCode:   errs.RejectCodeNonStandard,
+		},
+		"consensus limit": {
+			unlockingASM: "0000000001",
+			lockingASM:   "OP_1 OP_ADD",
+			flags:        scriptflag.UTXOAfterGenesis,
+			opts: []interpreter.ExecutionOptionFunc{
+				interpreter.WithLimits(interpreter.Limits{MaxScriptNumberLength: 4}, interpreter.Limits{}),
+			},
+			expReason: "mandatory-script-verify-flag-failed (Script number overflow)",
+			expCode:   errs.RejectCodeInvalid,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := execute(t, test.unlockingASM, test.lockingASM, test.flags, test.opts...)
+			require.Error(t, err)
+
+			reason, ok := interpreter.RejectReasonFor(err, test.flags)
+			require.True(t, ok)
+			assert.Equal(t, test.expReason, reason.String())
+			assert.Equal(t, test.expCode, reason.Category.RejectCode())
+		})
+	}
+
+	t.Run("limit source is taken from the error", func(t *testing.T) {
+		err := errs.NewError(errs.ErrNumberTooBig, "exceeds the policy max script number length of 4")
+
+		reason, ok := interpreter.RejectReasonFor(err, 0)
+		require.True(t, ok)
+		assert.Equal(t, errs.RejectMandatory, reason.Category)
+
+		err.PolicyLimit = true
+		reason, ok = interpreter.RejectReasonFor(err, 0)
+		require.True(t, ok)
+		assert.Equal(t, errs.RejectNonMandatory, reason.Category)
+	})
+
+	t.Run("signature encoding is mandatory with strict encoding", func(t *testing.T) {
+		err := errs.NewError(errs.ErrSigTooShort, "malformed signature: too short")
+
+		reason, ok := interpreter.RejectReasonFor(err, scriptflag.VerifyDERSignatures)
+		require.True(t, ok)
+		assert.Equal(t, errs.RejectNonMandatory, reason.Category)
+
+		reason, ok = interpreter.RejectReasonFor(err, scriptflag.VerifyDERSignatures|scriptflag.EnableSighashForkID)
+		require.True(t, ok)
+		assert.Equal(t, errs.RejectMandatory, reason.Category)
+		assert.Equal(t, "mandatory-script-verify-flag-failed (Non-canonical DER signature)", reason.String())
+	})
+
+	t.Run("null fail", func(t *testing.T) {
+		tx := newVerifyTestTx(t, 1)
+		tx.Inputs[0].PreviousTxSatoshis++
+
+		results, err := interpreter.Verify(tx)
+		require.Error(t, err)
+
+		reason, ok := results[0].RejectReason()
+		require.True(t, ok)
+		assert.Equal(t,
+			"mandatory-script-verify-flag-failed (Signature must be zero for failed CHECK(MULTI)SIG operation)",
+			reason.String(),
+		)
+	})
+
+	t.Run("no reason", func(t *testing.T) {
+		_, ok := interpreter.RejectReasonFor(nil, 0)
+		assert.False(t, ok)
+
+		_, ok = interpreter.RejectReasonFor(errors.New("some error"), 0)
+		assert.False(t, ok)
+
+		_, ok = interpreter.RejectReasonFor(errs.NewError(errs.ErrInvalidParams, "no tx"), 0)
+		assert.False(t, ok)
+
+		_, ok = interpreter.RejectReasonFor(errs.NewError(errs.ErrScriptUnfinished, "not finished"), 0)
+		assert.False(t, ok)
+
+		_, ok = interpreter.RejectReasonFor(errs.NewError(errs.ErrInvalidProgramCounter, "past input scripts"), 0)
+		assert.False(t, ok)
+	})
+
+	t.Run("budget", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		lockingScript, err := bscript.NewFromASM("OP_1")
+		require.NoError(t, err)
+		err = interpreter.NewEngine().ExecuteContext(ctx,
+			interpreter.WithScripts(lockingScript, &bscript.Script{}),
+		)
+		require.Error(t, err)
+
+		require.True(t, errs.IsErrorCode(err, errs.ErrCanceled))
+
+		_, ok := interpreter.RejectReasonFor(err, 0)
+		assert.False(t, ok)
+	})
+}
@@ -78,6 +78,13 @@ const (
 	// VerifyMinimalIf defines the enforcement of any conditional statement using the
 	// minimum required data.
 	VerifyMinimalIf
+
+	// EnableChronicle defines that the Chronicle rules are in effect. For utxos
+	// created after genesis, this restores OP_VER, OP_VERIF, OP_VERNOTIF, OP_2MUL
+	// and OP_2DIV, enables OP_SUBSTR, OP_LEFT, OP_RIGHT, OP_LSHIFTNUM and
+	// OP_RSHIFTNUM in place of OP_NOP4 to OP_NOP8, and relaxes the malleability
+	// rules for txs with a version greater than 1.
+	EnableChronicle
 )
 
 // HasFlag returns whether the Flags has the passed flag set.
@@ -100,3 +107,8 @@ func (s Flag) HasAny(flags ...Flag) bool {
 func (s *Flag) AddFlag(flag Flag) {
 	*s |= flag
 }
+
+// RemoveFlag removes the passed flag from Flags
+func (s *Flag) RemoveFlag(flag Flag) {
+	*s &^= flag
+}
@@ -0,0 +1,214 @@
+package interpreter
+
+import (
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+)
+
+// Breakpoint reports whether execution should pause before the opcode at
+// opcodeIdx of the script at scriptIdx, being 0 for the unlocking script and
+// 1 for the locking script.
+type Breakpoint func(scriptIdx, opcodeIdx int, op ParsedOpcode) bool
+
+// AtOpcodeIndex returns a Breakpoint which pauses before the opcode at
+// opcodeIdx of the script at scriptIdx.
+func AtOpcodeIndex(scriptIdx, opcodeIdx int) Breakpoint {
+	return func(s, o int, _ ParsedOpcode) bool {
+		return s == scriptIdx && o == opcodeIdx
+	}
+}
+
+// AtOpcode returns a Breakpoint which pauses before any opcode with the given
+// value, for example bscript.OpCHECKSIG.
+func AtOpcode(value byte) Breakpoint {
+	return func(_, _ int, op ParsedOpcode) bool {
+		return op.Value() == value
+	}
+}
+
+// Session executes scripts one opcode at a time, allowing the stacks to be
+// inspected and modified between opcodes. It is intended for building script
+// debuggers, and is configured with the same options as Engine.Execute.
+//
+// Once every opcode has executed, the outcome of the scripts is checked in the
+// same way as Execute, and any error is returned by the call which finished
+// the session and by Err thereafter.
+//
+// Session example:
+//
+//	s, err := interpreter.NewSession(
+//	    interpreter.WithTx(tx, inputIdx, previousOutput),
+//	    interpreter.WithAfterGenesis(),
+//	    interpreter.WithForkID(),
+//	)
+//	if err != nil {
+//	    // handle err
+//	}
+//
+//	done, err := s.RunUntil(interpreter.AtOpcode(bscript.OpCHECKSIG))
+//	fmt.Println(s.DataStack())
+//	for !done && err == nil {
+//	    done, err = s.Step()
+//	}
+type Session struct {
+	t    *thread
+	done bool
+	err  error
+}
+
+// NewSession returns a Session for the scripts configured by the options,
+// paused before their first opcode.
+func NewSession(oo ...ExecutionOptionFunc) (*Session, error) {
+	opts := &execOpts{}
+	for _, o := range oo {
+		o(opts)
+	}
+
+	t, err := createThread(opts)
+	if err != nil {
+		return nil, err
+	}
+	t.beforeExecute()
+
+	return &Session{t: t}, nil
+}
+
+// Step executes the next opcode, returning true once the session is finished.
+// Calling Step on a finished session returns its outcome again.
+func (s *Session) Step() (bool, error) {
+	if s.done {
+		return true, s.err
+	}
+
+	s.t.beforeStep()
+	done, err := s.t.Step()
+	if err != nil {
+		return s.finish(err)
+	}
+	s.t.afterStep()
+
+	if done {
+		return s.finish(nil)
+	}
+
+	return false, nil
+}
+
+// StepOver executes the next opcode, and if it opens a conditional block with
+// OP_IF or OP_NOTIF, continues until the matching OP_ENDIF has executed.
+func (s *Session) StepOver() (bool, error) {
+	op, ok := s.Opcode()
+	if !ok || (op.Value() != bscript.OpIF && op.Value() != bscript.OpNOTIF) {
+		return s.Step()
+	}
+
+	depth := len(s.t.condStack)
+	for {
+		done, err := s.Step()
+		if done || err != nil || len(s.t.condStack) <= depth {
+			return done, err
+		}
+	}
+}
+
+// RunUntil executes at least one opcode, then continues until the next opcode
+// matches any of the breakpoints or the session is finished. Without any
+// breakpoints, it runs the session to completion.
+func (s *Session) RunUntil(breakpoints ...Breakpoint) (bool, error) {
+	for {
+		done, err := s.Step()
+		if done || err != nil {
+			return done, err
+		}
+
+		op, _ := s.Opcode()
+		for _, bp := range breakpoints {
+			if bp(s.t.scriptIdx, s.t.scriptOff, op) {
+				return false, nil
+			}
+		}
+	}
+}
+
+// Done returns true if the session is finished.
+func (s *Session) Done() bool {
+	return s.done
+}
+
+// Err returns the outcome of a finished session, being nil if the scripts
+// executed successfully.
+func (s *Session) Err() error {
+	return s.err
+}
+
+// Position returns the index of the script, and of the opcode within it, which
+// is to be executed next.
+func (s *Session) Position() (scriptIdx, opcodeIdx int) {
+	return s.t.scriptIdx, s.t.scriptOff
+}
+
+// Opcode returns the opcode which is to be executed next, or false if the
+// session is finished.
+func (s *Session) Opcode() (ParsedOpcode, bool) {
+	if s.done || s.t.validPC() != nil {
+		return ParsedOpcode{}, false
+	}
+	return s.t.scripts[s.t.scriptIdx][s.t.scriptOff], true
+}
+
+// State returns a snapshot of the state of the session.
+func (s *Session) State() *State {
+	return s.t.State()
+}
+
+// SetState restores the session to a snapshot of its state, such as one
+// recorded by a debugger, so that execution resumes from it.
+func (s *Session) SetState(state *State) {
+	s.t.SetState(state)
+	s.done, s.err = false, nil
+}
+
+// DataStack returns the items of the data stack, with the top of the stack last.
+func (s *Session) DataStack() [][]byte {
+	return copyStack(getStack(&s.t.dstack))
+}
+
+// AltStack returns the items of the alt stack, with the top of the stack last.
+func (s *Session) AltStack() [][]byte {
+	return copyStack(getStack(&s.t.astack))
+}
+
+// SetDataStack replaces the items of the data stack, with the top of the stack
+// last.
+func (s *Session) SetDataStack(data [][]byte) {
+	setStack(&s.t.dstack, copyStack(data))
+}
+
+// SetAltStack replaces the items of the alt stack, with the top of the stack
+// last.
+func (s *Session) SetAltStack(data [][]byte) {
+	setStack(&s.t.astack, copyStack(data))
+}
+
+// finish ends the session, checking the outcome of the scripts if they
+// executed without error.
+func (s *Session) finish(err error) (bool, error) {
+	s.t.afterExecute()
+	if err == nil {
+		err = s.t.CheckErrorCondition(true)
+	}
+	if err != nil {
+		s.t.afterError(err)
+	}
+
+	s.done, s.err = true, err
+	return true, err
+}
+
+func copyStack(data [][]byte) [][]byte {
+	cp := make([][]byte, len(data))
+	for i, d := range data {
+		cp[i] = make([]byte, len(d))
+		copy(cp[i], d)
+	}
+	return cp
+}
@@ -0,0 +1,140 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter/errs"
+)
+
+func newTestSession(t *testing.T, unlockingScript, lockingScript string) *Session {
+	t.Helper()
+
+	uscript, err := parseShortForm(unlockingScript)
+	require.NoError(t, err)
+	lscript, err := parseShortForm(lockingScript)
+	require.NoError(t, err)
+
+	s, err := NewSession(WithScripts(lscript, uscript), WithAfterGenesis())
+	require.NoError(t, err)
+
+	return s
+}
+
+func TestSession_Step(t *testing.T) {
+	t.Parallel()
+
+	s := newTestSession(t, "2 3", "ADD 5 EQUAL")
+
+	scriptIdx, opcodeIdx := s.Position()
+	assert.Equal(t, 0, scriptIdx)
+	assert.Equal(t, 0, opcodeIdx)
+
+	for _, expStack := range [][][]byte{
+		{{2}},
+		{{2}, {3}},
+		{{5}},
+		{{5}, {5}},
+	} {
+		done, err := s.Step()
+		require.NoError(t, err)
+		assert.False(t, done)
+		assert.Equal(t, expStack, s.DataStack())
+	}
+
+	op, ok := s.Opcode()
+	require.True(t, ok)
+	assert.Equal(t, bscript.OpEQUAL, op.Value())
+
+	done, err := s.Step()
+	require.NoError(t, err)
+	assert.True(t, done)
+	assert.True(t, s.Done())
+	require.NoError(t, s.Err())
+
+	_, ok = s.Opcode()
+	assert.False(t, ok)
+}
+
+func TestSession_StepOver(t *testing.T) {
+	t.Parallel()
+
+	s := newTestSession(t, "1", "IF 2 3 ADD ELSE 0 ENDIF 5 EQUAL")
+
+	_, err := s.Step()
+	require.NoError(t, err)
+
+	done, err := s.StepOver()
+	require.NoError(t, err)
+	assert.False(t, done)
+
+	scriptIdx, opcodeIdx := s.Position()
+	assert.Equal(t, 1, scriptIdx)
+	assert.Equal(t, 7, opcodeIdx)
+	assert.Equal(t, [][]byte{{5}}, s.DataStack())
+
+	done, err = s.StepOver()
+	require.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, [][]byte{{5}, {5}}, s.DataStack())
+}
+
+func TestSession_RunUntil(t *testing.T) {
+	t.Parallel()
+
+	t.Run("opcode value", func(t *testing.T) {
+		s := newTestSession(t, "2 3", "ADD 5 EQUAL")
+
+		done, err := s.RunUntil(AtOpcode(bscript.OpEQUAL))
+		require.NoError(t, err)
+		assert.False(t, done)
+		assert.Equal(t, [][]byte{{5}, {5}}, s.DataStack())
+	})
+
+	t.Run("opcode index", func(t *testing.T) {
+		s := newTestSession(t, "2 3", "ADD 5 EQUAL")
+
+		done, err := s.RunUntil(AtOpcodeIndex(1, 1))
+		require.NoError(t, err)
+		assert.False(t, done)
+		assert.Equal(t, [][]byte{{5}}, s.DataStack())
+	})
+
+	t.Run("to completion", func(t *testing.T) {
+		s := newTestSession(t, "2 3", "ADD 6 EQUAL")
+
+		done, err := s.RunUntil()
+		assert.True(t, done)
+		assert.True(t, errs.IsErrorCode(err, errs.ErrEvalFalse))
+		assert.True(t, errs.IsErrorCode(s.Err(), errs.ErrEvalFalse))
+
+		done, err = s.Step()
+		assert.True(t, done)
+		assert.True(t, errs.IsErrorCode(err, errs.ErrEvalFalse))
+	})
+}
+
+func TestSession_SetStack(t *testing.T) {
+	t.Parallel()
+
+	s := newTestSession(t, "2 3", "ADD 6 EQUAL")
+
+	_, err := s.RunUntil(AtOpcode(bscript.OpADD))
+	require.NoError(t, err)
+
+	s.SetDataStack([][]byte{{3}, {3}})
+	s.SetAltStack([][]byte{{1}})
+	assert.Equal(t, [][]byte{{1}}, s.AltStack())
+
+	// Modifying the returned stack does not modify the session.
+	stack := s.DataStack()
+	stack[0][0] = 9
+	assert.Equal(t, [][]byte{{3}, {3}}, s.DataStack())
+
+	done, err := s.RunUntil()
+	require.NoError(t, err)
+	assert.True(t, done)
+}
@@ -0,0 +1,86 @@
+package interpreter
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+)
+
+// SigCache is a cache of valid signatures, keyed by the signature hash, public
+// key and signature, which is safe for concurrent use. Sharing a SigCache
+// between executions, such as when txs accepted to the mempool are validated
+// again once mined, saves verifying the same signature twice.
+//
+// Only signatures which verified are added. Once full, an entry chosen at
+// random is evicted for each added.
+type SigCache struct {
+	mtx        sync.RWMutex
+	entries    map[[sha256.Size]byte]struct{}
+	maxEntries int
+}
+
+// NewSigCache returns a SigCache which holds up to maxEntries signatures.
+func NewSigCache(maxEntries int) *SigCache {
+	return &SigCache{
+		entries:    make(map[[sha256.Size]byte]struct{}, maxEntries),
+		maxEntries: maxEntries,
+	}
+}
+
+// Exists returns true if the signature is cached as valid for the signature
+// hash and public key.
+func (c *SigCache) Exists(sigHash, pubKey, sig []byte) bool {
+	key := sigCacheKey(sigHash, pubKey, sig)
+
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	_, ok := c.entries[key]
+	return ok
+}
+
+// Add caches the signature as valid for the signature hash and public key.
+func (c *SigCache) Add(sigHash, pubKey, sig []byte) {
+	if c.maxEntries <= 0 {
+		return
+	}
+	key := sigCacheKey(sigHash, pubKey, sig)
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+	if len(c.entries) >= c.maxEntries {
+		// Map iteration order is random, so the first key is as good as any.
+		for k := range c.entries {
+			delete(c.entries, k)
+			break
+		}
+	}
+	c.entries[key] = struct{}{}
+}
+
+// Len returns the number of signatures cached.
+func (c *SigCache) Len() int {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return len(c.entries)
+}
+
+// sigCacheKey hashes the signature hash, public key and signature, prefixing
+// the variable length public key with its length so that the fields cannot be
+// shifted from one into another.
+func sigCacheKey(sigHash, pubKey, sig []byte) [sha256.Size]byte {
+	h := sha256.New()
+	_, _ = h.Write(sigHash)
+	_, _ = h.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(pubKey))))
+	_, _ = h.Write(pubKey)
+	_, _ = h.Write(sig)
+
+	var key [sha256.Size]byte
+	h.Sum(key[:0])
+	return key
+}
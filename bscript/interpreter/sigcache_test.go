@@ -0,0 +1,66 @@
+package interpreter_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter"
+)
+
+func TestSigCache(t *testing.T) {
+	t.Parallel()
+
+	sigHash := bytes.Repeat([]byte{1}, 32)
+	pubKey := bytes.Repeat([]byte{2}, 33)
+	sig := bytes.Repeat([]byte{3}, 70)
+
+	t.Run("add and exists", func(t *testing.T) {
+		cache := interpreter.NewSigCache(10)
+		assert.False(t, cache.Exists(sigHash, pubKey, sig))
+
+		cache.Add(sigHash, pubKey, sig)
+		cache.Add(sigHash, pubKey, sig)
+		assert.True(t, cache.Exists(sigHash, pubKey, sig))
+		assert.Equal(t, 1, cache.Len())
+
+		assert.False(t, cache.Exists(sigHash, sig, pubKey))
+		assert.False(t, cache.Exists(sigHash, append(pubKey, sig[0]), sig[1:]))
+	})
+
+	t.Run("evicts when full", func(t *testing.T) {
+		cache := interpreter.NewSigCache(2)
+		for i := range 5 {
+			cache.Add(sigHash, pubKey, []byte{byte(i)})
+		}
+		assert.Equal(t, 2, cache.Len())
+		assert.True(t, cache.Exists(sigHash, pubKey, []byte{4}))
+	})
+
+	t.Run("zero size", func(t *testing.T) {
+		cache := interpreter.NewSigCache(0)
+		cache.Add(sigHash, pubKey, sig)
+		assert.Equal(t, 0, cache.Len())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		cache := interpreter.NewSigCache(100)
+
+		var wg sync.WaitGroup
+		for i := range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range 20 {
+					s := []byte{byte(i), byte(j)}
+					cache.Add(sigHash, pubKey, s)
+					assert.True(t, cache.Exists(sigHash, pubKey, s))
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 100, cache.Len())
+	})
+}
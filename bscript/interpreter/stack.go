@@ -6,6 +6,7 @@ package interpreter
 
 import (
 	"encoding/hex"
+	"math/big"
 
 	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter/errs"
 )
@@ -38,7 +39,10 @@ func fromBool(v bool) []byte {
 // stack.
 type stack struct {
 	stk               [][]byte
+	memUsage          int
+	allocated         int
 	maxNumLength      int
+	cfg               config
 	afterGenesis      bool
 	verifyMinimalData bool
 	debug             Debugger
@@ -48,6 +52,7 @@ type stack struct {
 func newStack(cfg config, verifyMinimalData bool) stack {
 	return stack{
 		maxNumLength:      cfg.MaxScriptNumberLength(),
+		cfg:               cfg,
 		afterGenesis:      cfg.AfterGenesis(),
 		verifyMinimalData: verifyMinimalData,
 		debug:             &nopDebugger{},
@@ -60,13 +65,23 @@ func (s *stack) Depth() int32 {
 	return int32(len(s.stk))
 }
 
-// PushByteArray adds the given back array to the top of the stack.
+// PushByteArray adds the given back array to the top of the stack, counting it
+// as newly allocated data.
 //
 // Stack transformation: [... x1 x2] -> [... x1 x2 data]
 func (s *stack) PushByteArray(so []byte) {
+	s.push(so)
+	s.allocated += len(so)
+}
+
+// push adds an item already held by the stacks to the top of the stack, such
+// as one being rearranged or moved between stacks, so it is not counted as
+// newly allocated data.
+func (s *stack) push(so []byte) {
 	defer s.afterStackPush(so)
 	s.beforeStackPush(so)
 	s.stk = append(s.stk, so)
+	s.memUsage += len(so) + StackItemOverhead
 }
 
 // PushInt converts the provided scriptNumber to a suitable byte array then pushes
@@ -109,7 +124,7 @@ func (s *stack) PopInt() (*scriptNumber, error) {
 		return nil, err
 	}
 
-	return makeScriptNumber(so, s.maxNumLength, s.verifyMinimalData, s.afterGenesis)
+	return s.makeScriptNumber(so)
 }
 
 // PopBool pops the value off the top of the stack, converts it into a bool, and
@@ -144,7 +159,7 @@ func (s *stack) PeekInt(idx int32) (*scriptNumber, error) {
 		return nil, err
 	}
 
-	return makeScriptNumber(so, s.maxNumLength, s.verifyMinimalData, s.afterGenesis)
+	return s.makeScriptNumber(so)
 }
 
 // PeekBool returns the Nth item on the stack as a bool without removing it.
@@ -171,6 +186,7 @@ func (s *stack) nipN(idx int32) ([]byte, error) {
 	}
 
 	so := s.stk[sz-idx-1]
+	s.memUsage -= len(so) + StackItemOverhead
 	if idx == 0 { //nolint:staticcheck // ignore for now
 		s.stk = s.stk[:sz-1]
 	} else if idx == sz-1 {
@@ -209,9 +225,9 @@ func (s *stack) Tuck() error {
 	if err != nil {
 		return err
 	}
-	s.PushByteArray(so2) // stack [... x2]
-	s.PushByteArray(so1) // stack [... x2 x1]
-	s.PushByteArray(so2) // stack [... x2 x1 x2]
+	s.push(so2) // stack [... x2]
+	s.push(so1) // stack [... x2 x1]
+	s.push(so2) // stack [... x2 x1 x2]
 
 	return nil
 }
@@ -252,7 +268,7 @@ func (s *stack) DupN(n int32) error {
 		if err != nil {
 			return err
 		}
-		s.PushByteArray(so)
+		s.push(so)
 	}
 	return nil
 }
@@ -276,7 +292,7 @@ func (s *stack) RotN(n int32) error {
 			return err
 		}
 
-		s.PushByteArray(so)
+		s.push(so)
 	}
 	return nil
 }
@@ -299,7 +315,7 @@ func (s *stack) SwapN(n int32) error {
 			return err
 		}
 
-		s.PushByteArray(so)
+		s.push(so)
 	}
 	return nil
 }
@@ -321,7 +337,7 @@ func (s *stack) OverN(n int32) error {
 		if err != nil {
 			return err
 		}
-		s.PushByteArray(so)
+		s.push(so)
 	}
 
 	return nil
@@ -338,7 +354,7 @@ func (s *stack) PickN(n int32) error {
 	if err != nil {
 		return err
 	}
-	s.PushByteArray(so)
+	s.push(so)
 
 	return nil
 }
@@ -355,11 +371,24 @@ func (s *stack) RollN(n int32) error {
 		return err
 	}
 
-	s.PushByteArray(so)
+	s.push(so)
 
 	return nil
 }
 
+// makeScriptNumber interprets so as a script number within the limits of the
+// stack, naming the limit in the error if it is too long.
+func (s *stack) makeScriptNumber(so []byte) (*scriptNumber, error) {
+	if len(so) > s.maxNumLength {
+		return &scriptNumber{val: big.NewInt(0)}, limitError(
+			s.cfg, errs.ErrNumberTooBig, limitMaxScriptNumberLength, s.maxNumLength,
+			"numeric value of %d bytes exceeds", len(so),
+		)
+	}
+
+	return makeScriptNumber(so, s.maxNumLength, s.verifyMinimalData, s.afterGenesis)
+}
+
 // String returns the stack in a readable format.
 func (s *stack) String() string {
 	var result string
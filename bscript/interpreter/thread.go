@@ -1,7 +1,10 @@
 package interpreter
 
 import (
+	"context"
+	"errors"
 	"math/big"
+	"time"
 
 	bec "github.com/bsv-blockchain/go-sdk/primitives/ec"
 
@@ -15,6 +18,16 @@ import (
 // halfOrder is used to tame ECDSA malleability (see BIP0062).
 var halfOrder = new(big.Int).Rsh(bec.S256().N, 1)
 
+// malleabilityFlags are the flags which are relaxed by Chronicle for txs with
+// a version greater than 1.
+const malleabilityFlags = scriptflag.VerifyMinimalData |
+	scriptflag.VerifyLowS |
+	scriptflag.VerifyNullFail |
+	scriptflag.StrictMultiSig |
+	scriptflag.VerifyMinimalIf |
+	scriptflag.VerifyCleanStack |
+	scriptflag.VerifySigPushOnly
+
 type thread struct {
 	dstack stack // data stack
 	astack stack // alt stack
@@ -39,7 +52,14 @@ type thread struct {
 	inputIdx   int
 	prevOutput *bt.Output
 
-	numOps int
+	numOps     int
+	numOpcodes int
+
+	budget execBudget
+
+	sigCache     *SigCache
+	sigHashCache *bt.SigHashCache
+	sigVerifier  SignatureVerifier
 
 	flags scriptflag.Flag
 	bip16 bool // treat execution as pay-to-script-hash
@@ -79,6 +99,23 @@ type execOpts struct {
 	flags           scriptflag.Flag
 	debugger        Debugger
 	state           *State
+	limits          *execLimits
+	budget          execBudget
+	sigCache        *SigCache
+	sigHashCache    *bt.SigHashCache
+	sigVerifier     SignatureVerifier
+}
+
+type execLimits struct {
+	consensus, policy Limits
+}
+
+// execBudget bounds the resources used by an execution. A zero value for any
+// field leaves it unbounded.
+type execBudget struct {
+	maxOpcodes         int
+	deadline           time.Time
+	maxStackAllocation int
 }
 
 func (o execOpts) validate() error {
@@ -126,6 +163,12 @@ func (o execOpts) validate() error {
 	return nil
 }
 
+// isChronicle returns whether the Chronicle rules apply to the execution. They
+// only apply to utxos created after genesis.
+func (t *thread) isChronicle() bool {
+	return t.afterGenesis && t.hasFlag(scriptflag.EnableChronicle)
+}
+
 // hasFlag returns whether the script engine instance has the passed flag set.
 func (t *thread) hasFlag(flag scriptflag.Flag) bool {
 	return t.flags.HasFlag(flag)
@@ -152,14 +195,14 @@ func (t *thread) isBranchExecuting() bool {
 // tested in this case.
 func (t *thread) executeOpcode(pop ParsedOpcode) error {
 	if len(pop.Data) > t.cfg.MaxScriptElementSize() {
-		return errs.NewError(errs.ErrElementTooBig,
-			"element size %d exceeds max allowed size %d", len(pop.Data), t.cfg.MaxScriptElementSize())
+		return limitError(t.cfg, errs.ErrElementTooBig, limitMaxScriptElementSize, t.cfg.MaxScriptElementSize(),
+			"element size %d exceeds", len(pop.Data))
 	}
 
 	exec := t.shouldExec(pop)
 
 	// Disabled opcodes are fail on program counter.
-	if pop.IsDisabled() && (!t.afterGenesis || exec) {
+	if pop.IsDisabled() && !t.isChronicle() && (!t.afterGenesis || exec) {
 		return errs.NewError(errs.ErrDisabledOpcode, "attempt to execute disabled opcode %s", pop.Name())
 	}
 
@@ -173,14 +216,14 @@ func (t *thread) executeOpcode(pop ParsedOpcode) error {
 	if pop.op.val > bscript.Op16 {
 		t.numOps++
 		if t.numOps > t.cfg.MaxOps() {
-			return errs.NewError(errs.ErrTooManyOperations, "exceeded max operation limit of %d", t.cfg.MaxOps())
+			return limitError(t.cfg, errs.ErrTooManyOperations, limitMaxOps, t.cfg.MaxOps(), "exceeded")
 		}
 
 	}
 
 	if len(pop.Data) > t.cfg.MaxScriptElementSize() {
-		return errs.NewError(errs.ErrElementTooBig,
-			"element size %d exceeds max allowed size %d", len(pop.Data), t.cfg.MaxScriptElementSize())
+		return limitError(t.cfg, errs.ErrElementTooBig, limitMaxScriptElementSize, t.cfg.MaxScriptElementSize(),
+			"element size %d exceeds", len(pop.Data))
 	}
 
 	// Nothing left to do when this is not a conditional opcode, and it is
@@ -260,6 +303,10 @@ func (t *thread) apply(opts *execOpts) error {
 	}
 
 	t.tx = opts.tx
+	t.budget = opts.budget
+	t.sigCache = opts.sigCache
+	t.sigHashCache = opts.sigHashCache
+	t.sigVerifier = opts.sigVerifier
 	t.flags = opts.flags
 	t.inputIdx = opts.inputIdx
 	t.prevOutput = opts.previousTxOut
@@ -282,6 +329,15 @@ func (t *thread) apply(opts *execOpts) error {
 		t.afterGenesis = true
 		t.cfg = &afterGenesisConfig{}
 	}
+	if opts.limits != nil {
+		t.cfg = newLimitedConfig(t.cfg, opts.limits.consensus, opts.limits.policy)
+	}
+
+	// Chronicle relaxes the malleability rules for txs which opt in by using a
+	// version greater than 1.
+	if t.isChronicle() && t.tx != nil && t.tx.Version > 1 {
+		t.flags.RemoveFlag(malleabilityFlags)
+	}
 
 	uscript := opts.unlockingScript
 	lscript := opts.lockingScript
@@ -299,20 +355,12 @@ func (t *thread) apply(opts *execOpts) error {
 	}
 
 	if len(*uscript) > t.cfg.MaxScriptSize() {
-		return errs.NewError(
-			errs.ErrScriptTooBig,
-			"unlocking script size %d is larger than the max allowed size %d",
-			len(*uscript),
-			t.cfg.MaxScriptSize(),
-		)
+		return limitError(t.cfg, errs.ErrScriptTooBig, limitMaxScriptSize, t.cfg.MaxScriptSize(),
+			"unlocking script size %d exceeds", len(*uscript))
 	}
 	if len(*lscript) > t.cfg.MaxScriptSize() {
-		return errs.NewError(
-			errs.ErrScriptTooBig,
-			"locking script size %d is larger than the max allowed size %d",
-			len(*uscript),
-			t.cfg.MaxScriptSize(),
-		)
+		return limitError(t.cfg, errs.ErrScriptTooBig, limitMaxScriptSize, t.cfg.MaxScriptSize(),
+			"locking script size %d exceeds", len(*lscript))
 	}
 
 	// The engine stores the scripts in parsed form using a slice.  This
@@ -353,9 +401,16 @@ func (t *thread) apply(opts *execOpts) error {
 	t.dstack = newStack(t.cfg, t.hasFlag(scriptflag.VerifyMinimalData))
 	t.astack = newStack(t.cfg, t.hasFlag(scriptflag.VerifyMinimalData))
 
+	// Only write to the input when it differs, so that inputs of an already
+	// extended tx can be executed concurrently without racing.
 	if t.tx != nil {
-		t.tx.InputIdx(t.inputIdx).PreviousTxScript = t.prevOutput.LockingScript
-		t.tx.InputIdx(t.inputIdx).PreviousTxSatoshis = t.prevOutput.Satoshis
+		input := t.tx.InputIdx(t.inputIdx)
+		if input.PreviousTxScript != t.prevOutput.LockingScript {
+			input.PreviousTxScript = t.prevOutput.LockingScript
+		}
+		if input.PreviousTxSatoshis != t.prevOutput.Satoshis {
+			input.PreviousTxSatoshis = t.prevOutput.Satoshis
+		}
 	}
 
 	t.state = t
@@ -376,11 +431,18 @@ func (t *thread) apply(opts *execOpts) error {
 	return nil
 }
 
-func (t *thread) execute() error {
+func (t *thread) execute(ctx context.Context) error {
 	if err := func() error {
 		defer t.afterExecute()
 		t.beforeExecute()
 		for {
+			if err := ctx.Err(); err != nil {
+				if errors.Is(err, context.DeadlineExceeded) {
+					return errs.NewError(errs.ErrDeadlineExceeded, "context deadline exceeded")
+				}
+				return errs.NewError(errs.ErrCanceled, "execution canceled: %s", err)
+			}
+
 			t.beforeStep()
 
 			done, err := t.Step()
@@ -400,6 +462,28 @@ func (t *thread) execute() error {
 	return t.CheckErrorCondition(true)
 }
 
+// checkBudget returns an error if the execution has exceeded its budget.
+func (t *thread) checkBudget() error {
+	t.numOpcodes++
+	if t.budget.maxOpcodes > 0 && t.numOpcodes > t.budget.maxOpcodes {
+		return errs.NewError(errs.ErrOpcodeBudgetExceeded,
+			"executed more than the max of %d opcodes", t.budget.maxOpcodes)
+	}
+
+	if t.budget.maxStackAllocation > 0 {
+		if allocated := t.dstack.allocated + t.astack.allocated; allocated > t.budget.maxStackAllocation {
+			return errs.NewError(errs.ErrAllocationBudgetExceeded,
+				"allocated %d bytes on the stacks, exceeding the max of %d", allocated, t.budget.maxStackAllocation)
+		}
+	}
+
+	if !t.budget.deadline.IsZero() && time.Now().After(t.budget.deadline) {
+		return errs.NewError(errs.ErrDeadlineExceeded, "execution deadline of %s exceeded", t.budget.deadline)
+	}
+
+	return nil
+}
+
 // Step will execute the next instruction and move the program counter to the
 // next opcode in the script, or the next script if the current has ended.  Step
 // will return true in the case that the last opcode was successfully executed.
@@ -434,8 +518,18 @@ func (t *thread) Step() (bool, error) {
 	// must not exceed the maximum number of stack elements allowed.
 	combinedStackSize := t.dstack.Depth() + t.astack.Depth()
 	if combinedStackSize > int32(t.cfg.MaxStackSize()) {
-		return false, errs.NewError(errs.ErrStackOverflow,
-			"combined stack size %d > max allowed %d", combinedStackSize, t.cfg.MaxStackSize())
+		return false, limitError(t.cfg, errs.ErrStackOverflow, limitMaxStackSize, t.cfg.MaxStackSize(),
+			"combined stack size %d exceeds", combinedStackSize)
+	}
+
+	// As must the number of bytes held by them.
+	if memUsage := t.dstack.memUsage + t.astack.memUsage; memUsage > t.cfg.MaxStackMemoryUsage() {
+		return false, limitError(t.cfg, errs.ErrStackMemoryUsage, limitMaxStackMemoryUsage, t.cfg.MaxStackMemoryUsage(),
+			"stack memory usage %d exceeds", memUsage)
+	}
+
+	if err := t.checkBudget(); err != nil {
+		return false, err
 	}
 
 	if t.scriptOff < len(t.scripts[t.scriptIdx]) {
@@ -514,6 +608,47 @@ func (t *thread) subScript() ParsedScript {
 	return t.scripts[t.scriptIdx][skip:]
 }
 
+// signatureHash returns the hash signed by a signature with the given sighash
+// flag for the input being executed, with scriptCode as its script code. The
+// intermediate hashes of the tx are calculated on first use, unless supplied
+// WithSigHashCache.
+func (t *thread) signatureHash(scriptCode *bscript.Script, shf sighash.Flag) ([]byte, error) {
+	if t.sigHashCache == nil {
+		t.sigHashCache = t.tx.NewSigHashCache()
+	}
+
+	return t.tx.CalcInputSignatureHashWithCache(uint32(t.inputIdx), shf, scriptCode, t.sigHashCache)
+}
+
+// signatureVerifier returns the SignatureVerifier of the execution.
+func (t *thread) signatureVerifier() SignatureVerifier {
+	if t.sigVerifier == nil {
+		return DefaultSignatureVerifier{}
+	}
+	return t.sigVerifier
+}
+
+// verifyCachedSignature returns true if the signature is in the SigCache of the
+// execution, otherwise it verifies the signature and caches it if valid.
+//
+// The SigCache is bypassed when the execution has its own SignatureVerifier, as
+// the signatures it accepts need not be valid and must not be trusted by other
+// executions sharing the cache.
+func (t *thread) verifyCachedSignature(sigHash, pubKey, sig []byte, verify func() bool) bool {
+	if t.sigCache == nil || t.sigVerifier != nil {
+		return verify()
+	}
+	if t.sigCache.Exists(sigHash, pubKey, sig) {
+		return true
+	}
+
+	ok := verify()
+	if ok {
+		t.sigCache.Add(sigHash, pubKey, sig)
+	}
+	return ok
+}
+
 // checkHashTypeEncoding returns whether the passed hashtype adheres to
 // the strict encoding requirements if enabled.
 func (t *thread) checkHashTypeEncoding(shf sighash.Flag) error {
@@ -764,7 +899,7 @@ func setStack(stack *stack, data [][]byte) {
 	_ = stack.DropN(stack.Depth())
 
 	for i := range data {
-		stack.PushByteArray(data[i])
+		stack.push(data[i])
 	}
 }
 
@@ -0,0 +1,34 @@
+package interpreter
+
+import (
+	bec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+)
+
+// SignatureVerifier verifies the signatures checked by OP_CHECKSIG,
+// OP_CHECKSIGVERIFY, OP_CHECKMULTISIG and OP_CHECKMULTISIGVERIFY. It is called
+// once the signature and public key have been parsed and have passed the
+// encoding checks required by the flags of the execution, so a verifier which
+// skips the verification still requires well-formed signatures.
+//
+// A SignatureVerifier may be called concurrently by separate executions.
+type SignatureVerifier interface {
+	Verify(sigHash []byte, sig *bec.Signature, pubKey *bec.PublicKey) bool
+}
+
+// SignatureVerifierFunc is an adapter allowing a function to be used as a
+// SignatureVerifier.
+type SignatureVerifierFunc func(sigHash []byte, sig *bec.Signature, pubKey *bec.PublicKey) bool
+
+// Verify calls f(sigHash, sig, pubKey).
+func (f SignatureVerifierFunc) Verify(sigHash []byte, sig *bec.Signature, pubKey *bec.PublicKey) bool {
+	return f(sigHash, sig, pubKey)
+}
+
+// DefaultSignatureVerifier verifies signatures with the ECDSA implementation of
+// the go-sdk, and is used unless another is supplied WithSignatureVerifier.
+type DefaultSignatureVerifier struct{}
+
+// Verify returns true if sig is a valid signature of sigHash by pubKey.
+func (DefaultSignatureVerifier) Verify(sigHash []byte, sig *bec.Signature, pubKey *bec.PublicKey) bool {
+	return sig.Verify(sigHash, pubKey)
+}
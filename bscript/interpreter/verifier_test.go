@@ -0,0 +1,142 @@
+package interpreter_test
+
+import (
+	"bytes"
+	"testing"
+
+	bec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter/errs"
+	"github.com/bsv-blockchain/go-bt/v2/sighash"
+)
+
+func TestEngine_WithSignatureVerifier(t *testing.T) {
+	t.Parallel()
+
+	acceptAll := interpreter.SignatureVerifierFunc(func([]byte, *bec.Signature, *bec.PublicKey) bool {
+		return true
+	})
+
+	execute := func(tx *bt.Tx, lockingScript *bscript.Script, satoshis uint64, oo ...interpreter.ExecutionOptionFunc) error {
+		return interpreter.NewEngine().Execute(append([]interpreter.ExecutionOptionFunc{
+			interpreter.WithTx(tx, 0, &bt.Output{LockingScript: lockingScript, Satoshis: satoshis}),
+			interpreter.WithForkID(),
+			interpreter.WithAfterGenesis(),
+		}, oo...)...)
+	}
+
+	t.Run("checksig receives the sighash and keys", func(t *testing.T) {
+		tx := newVerifyTestTx(t, 1)
+		in := tx.Inputs[0]
+
+		expectedSigHash, err := tx.CalcInputSignatureHash(0, sighash.AllForkID)
+		require.NoError(t, err)
+
+		var calls int
+		err = execute(tx, in.PreviousTxScript, in.PreviousTxSatoshis,
+			interpreter.WithSignatureVerifier(interpreter.SignatureVerifierFunc(
+				func(sigHash []byte, sig *bec.Signature, pubKey *bec.PublicKey) bool {
+					calls++
+					assert.Equal(t, expectedSigHash, sigHash)
+					return interpreter.DefaultSignatureVerifier{}.Verify(sigHash, sig, pubKey)
+				},
+			)),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("checksig is decided by the verifier", func(t *testing.T) {
+		tx := newVerifyTestTx(t, 1)
+		in := tx.Inputs[0]
+
+		// Signing a different amount invalidates the signature.
+		in.PreviousTxSatoshis++
+		err := execute(tx, in.PreviousTxScript, in.PreviousTxSatoshis)
+		assert.True(t, errs.IsErrorCode(err, errs.ErrEvalFalse))
+
+		require.NoError(t, execute(tx, in.PreviousTxScript, in.PreviousTxSatoshis,
+			interpreter.WithSignatureVerifier(acceptAll),
+		))
+	})
+
+	t.Run("sig cache is bypassed", func(t *testing.T) {
+		tx := newVerifyTestTx(t, 1)
+		in := tx.Inputs[0]
+		in.PreviousTxSatoshis++
+		cache := interpreter.NewSigCache(10)
+
+		// The forged signature accepted by the verifier is not cached...
+		require.NoError(t, execute(tx, in.PreviousTxScript, in.PreviousTxSatoshis,
+			interpreter.WithSignatureVerifier(acceptAll),
+			interpreter.WithSigCache(cache),
+		))
+		assert.Zero(t, cache.Len())
+
+		// ...so it is still rejected by the default verifier.
+		err := execute(tx, in.PreviousTxScript, in.PreviousTxSatoshis, interpreter.WithSigCache(cache))
+		assert.True(t, errs.IsErrorCode(err, errs.ErrEvalFalse))
+
+		// Nor does the verifier see signatures cached by others as valid.
+		in.PreviousTxSatoshis--
+		require.NoError(t, execute(tx, in.PreviousTxScript, in.PreviousTxSatoshis, interpreter.WithSigCache(cache)))
+		assert.Equal(t, 1, cache.Len())
+
+		var calls int
+		_ = execute(tx, in.PreviousTxScript, in.PreviousTxSatoshis,
+			interpreter.WithSignatureVerifier(interpreter.SignatureVerifierFunc(
+				func([]byte, *bec.Signature, *bec.PublicKey) bool {
+					calls++
+					return false
+				},
+			)),
+			interpreter.WithSigCache(cache),
+		)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("checkmultisig is decided by the verifier", func(t *testing.T) {
+		pk, err := bec.NewPrivateKey()
+		require.NoError(t, err)
+		other, err := bec.NewPrivateKey()
+		require.NoError(t, err)
+
+		lockingScript, err := bscript.NewMultiSigFromPubKeys(1, []*bec.PublicKey{pk.PubKey(), other.PubKey()})
+		require.NoError(t, err)
+
+		tx := bt.NewTx()
+		require.NoError(t, tx.From(
+			"a4c76f8a7c05a91dcf5699b95b54e856298e50c1ceca9a8a5569c8532c500c11",
+			0, lockingScript.String(), 10000,
+		))
+		require.NoError(t, tx.AddP2PKHOutputFromAddress("mtestD3vRB7AoYWK2n6kLdZmAMLbLhDsLr", 9000))
+
+		// A well-formed signature of the wrong message.
+		sig, err := pk.Sign(bytes.Repeat([]byte{1}, 32))
+		require.NoError(t, err)
+		tx.Inputs[0].UnlockingScript, err = bscript.NewMultiSigUnlockingScript(
+			[][]byte{append(sig.Serialize(), byte(sighash.AllForkID))},
+		)
+		require.NoError(t, err)
+
+		err = execute(tx, lockingScript, 10000)
+		assert.True(t, errs.IsErrorCode(err, errs.ErrEvalFalse))
+
+		var pubKeys []*bec.PublicKey
+		require.NoError(t, execute(tx, lockingScript, 10000,
+			interpreter.WithSignatureVerifier(interpreter.SignatureVerifierFunc(
+				func(_ []byte, _ *bec.Signature, pubKey *bec.PublicKey) bool {
+					pubKeys = append(pubKeys, pubKey)
+					return true
+				},
+			)),
+		))
+		require.Len(t, pubKeys, 1)
+		assert.Contains(t, [][]byte{pk.PubKey().Compressed(), other.PubKey().Compressed()}, pubKeys[0].Compressed())
+	})
+}
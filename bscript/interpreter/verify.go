@@ -0,0 +1,200 @@
+package interpreter
+
+import (
+	"golang.org/x/sync/errgroup"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter/errs"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter/scriptflag"
+	"github.com/bsv-blockchain/go-bt/v2/chaincfg"
+)
+
+// InputResult is the outcome of verifying a single input of a tx.
+type InputResult struct {
+	// InputIdx is the index of the input within the tx.
+	InputIdx int
+	// Flags are the script flags the input was executed with.
+	Flags scriptflag.Flag
+	// Err is nil if the input is valid, otherwise the errs.Error returned by
+	// the engine.
+	Err error
+}
+
+// RejectReason returns the reason a node gives for rejecting the tx due to the
+// input, or false if the input is valid or failed for a reason the node does
+// not give, such as exceeding the budget of the execution. See RejectReasonFor.
+func (r InputResult) RejectReason() (errs.RejectReason, bool) {
+	return RejectReasonFor(r.Err, r.Flags)
+}
+
+// VerifyOptionFunc for setting verify options.
+type VerifyOptionFunc func(o *verifyOpts)
+
+type verifyOpts struct {
+	utxoHeights []uint32
+	params      *chaincfg.Params
+	blockHeight *uint32
+	flags       scriptflag.Flag
+	workers     int
+	sigCache    *SigCache
+}
+
+// WithUTXOHeights configure the block heights at which the utxo spent by each
+// input was mined, one height per input. These are compared to the genesis
+// height of the network to decide whether each input is evaluated with the
+// before or after genesis rules. When not supplied, every utxo is treated as
+// after genesis.
+func WithUTXOHeights(heights ...uint32) VerifyOptionFunc {
+	return func(o *verifyOpts) {
+		o.utxoHeights = heights
+	}
+}
+
+// WithVerifyNetwork configure the network whose activation heights apply, for
+// a tx mined, or to be mined, at blockHeight. The genesis height of the network
+// is compared to the utxo heights, and the Chronicle rules apply if they are
+// active at blockHeight. Defaults to chaincfg.MainNetParams, without the
+// Chronicle rules. Nil params are invalid.
+func WithVerifyNetwork(params *chaincfg.Params, blockHeight uint32) VerifyOptionFunc {
+	return func(o *verifyOpts) {
+		o.params = params
+		o.blockHeight = &blockHeight
+	}
+}
+
+// WithVerifyFlags configure additional flags to be applied on top of those
+// derived for each input.
+func WithVerifyFlags(flags scriptflag.Flag) VerifyOptionFunc {
+	return func(o *verifyOpts) {
+		o.flags.AddFlag(flags)
+	}
+}
+
+// WithWorkers configure the maximum number of inputs to be verified
+// concurrently. Values less than 2 verify the inputs one after another.
+func WithWorkers(n int) VerifyOptionFunc {
+	return func(o *verifyOpts) {
+		o.workers = n
+	}
+}
+
+// WithVerifySigCache configure a SigCache to be shared by the executions of
+// every input, see WithSigCache.
+func WithVerifySigCache(cache *SigCache) VerifyOptionFunc {
+	return func(o *verifyOpts) {
+		o.sigCache = cache
+	}
+}
+
+// Verify executes the scripts of every input of the extended tx against the
+// previous outputs they spend, returning a result per input.
+//
+// Each input is executed with the mandatory flags of the node, with the after
+// genesis rules applied if its utxo was mined at or after the genesis height,
+// and P2SH, CHECKLOCKTIMEVERIFY and CHECKSEQUENCEVERIFY applied if not, and with
+// the Chronicle rules if they are active at the block height. The
+// intermediate signature hashes of the tx are calculated once and shared by
+// every input.
+//
+// The returned error is nil if every input is valid. Otherwise, it is the error
+// of the lowest failing input, with the results detailing every input. If the
+// tx is not extended or the options are invalid, an errs.Error with code
+// ErrInvalidParams is returned, along with no results.
+//
+// Verify example:
+//
+//	results, err := interpreter.Verify(
+//	    tx,
+//	    interpreter.WithUTXOHeights(heights...),
+//	    interpreter.WithWorkers(runtime.NumCPU()),
+//	)
+//	if err != nil {
+//	    for _, r := range results {
+//	        // handle r.Err
+//	    }
+//	}
+func Verify(tx *bt.Tx, oo ...VerifyOptionFunc) ([]InputResult, error) {
+	opts := &verifyOpts{params: &chaincfg.MainNetParams}
+	for _, o := range oo {
+		o(opts)
+	}
+
+	if tx == nil || len(tx.Inputs) == 0 {
+		return nil, errs.NewError(errs.ErrInvalidParams, "tx has no inputs")
+	}
+	if !tx.IsExtended() {
+		return nil, errs.NewError(errs.ErrInvalidParams, "tx must be extended to be verified")
+	}
+	if opts.params == nil {
+		return nil, errs.NewError(errs.ErrInvalidParams, "network params must be supplied")
+	}
+	if opts.utxoHeights != nil && len(opts.utxoHeights) != len(tx.Inputs) {
+		return nil, errs.NewError(
+			errs.ErrInvalidParams,
+			"%d utxo heights supplied for %d inputs",
+			len(opts.utxoHeights),
+			len(tx.Inputs),
+		)
+	}
+
+	results := make([]InputResult, len(tx.Inputs))
+	for i := range tx.Inputs {
+		results[i] = InputResult{InputIdx: i, Flags: opts.inputFlags(i)}
+	}
+
+	e := NewEngine()
+	sigHashCache := tx.NewSigHashCache()
+	verifyInput := func(i int) {
+		in := tx.Inputs[i]
+		results[i].Err = e.Execute(
+			WithTx(tx, i, &bt.Output{LockingScript: in.PreviousTxScript, Satoshis: in.PreviousTxSatoshis}),
+			WithFlags(results[i].Flags),
+			WithSigHashCache(sigHashCache),
+			WithSigCache(opts.sigCache),
+		)
+	}
+
+	if opts.workers < 2 || len(tx.Inputs) < 2 {
+		for i := range tx.Inputs {
+			verifyInput(i)
+		}
+	} else {
+		var g errgroup.Group
+		g.SetLimit(opts.workers)
+		for i := range tx.Inputs {
+			g.Go(func() error {
+				verifyInput(i)
+				return nil
+			})
+		}
+		_ = g.Wait()
+	}
+
+	for _, r := range results {
+		if r.Err != nil {
+			return results, r.Err
+		}
+	}
+
+	return results, nil
+}
+
+// inputFlags returns the flags to execute the input at index i with.
+func (o *verifyOpts) inputFlags(i int) scriptflag.Flag {
+	flags := MandatoryFlags
+
+	if o.utxoHeights == nil || o.params.IsGenesisActive(o.utxoHeights[i]) {
+		flags.AddFlag(scriptflag.UTXOAfterGenesis)
+	} else {
+		flags.AddFlag(scriptflag.Bip16 |
+			scriptflag.VerifyCheckLockTimeVerify |
+			scriptflag.VerifyCheckSequenceVerify)
+	}
+	if o.blockHeight != nil && o.params.IsChronicleActive(*o.blockHeight) {
+		flags.AddFlag(scriptflag.EnableChronicle)
+	}
+
+	flags.AddFlag(o.flags)
+
+	return flags
+}
@@ -0,0 +1,173 @@
+package interpreter_test
+
+import (
+	"context"
+	"testing"
+
+	bec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter/errs"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter/scriptflag"
+	"github.com/bsv-blockchain/go-bt/v2/chaincfg"
+	"github.com/bsv-blockchain/go-bt/v2/unlocker"
+)
+
+// newVerifyTestTx returns an extended tx spending n P2PKH utxos, all signed.
+func newVerifyTestTx(t *testing.T, n int) *bt.Tx {
+	t.Helper()
+
+	pk, err := bec.PrivateKeyFromWif("cNGwGSc7KRrTmdLUZ54fiSXWbhLNDc2Eg5zNucgQxyQCzuQ5YRDq")
+	require.NoError(t, err)
+
+	tx := bt.NewTx()
+	for i := 0; i < n; i++ {
+		require.NoError(t, tx.From(
+			"93a35408b6068499e0d5abd799d3e827d9bfe70c9b75ebe209c91d2507232651",
+			uint32(i),
+			"76a914c0a3c167a28cabb9fbb495affa0761e6e74ac60d88ac",
+			100000000,
+		))
+	}
+	require.NoError(t, tx.PayToAddress("mfrPZq1Co8ggqPiW7Ga8ofGwJtzbRsZzEM", uint64(n)*99990000))
+	require.NoError(t, tx.FillAllInputs(context.Background(), &unlocker.Getter{PrivateKey: pk}))
+
+	return tx
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid tx", func(t *testing.T) {
+		tx := newVerifyTestTx(t, 3)
+
+		results, err := interpreter.Verify(tx)
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		for i, r := range results {
+			assert.Equal(t, i, r.InputIdx)
+			require.NoError(t, r.Err)
+			assert.True(t, r.Flags.HasFlag(scriptflag.UTXOAfterGenesis))
+			assert.True(t, r.Flags.HasFlag(scriptflag.EnableSighashForkID))
+			assert.False(t, r.Flags.HasFlag(scriptflag.Bip16))
+		}
+	})
+
+	t.Run("valid tx with workers", func(t *testing.T) {
+		tx := newVerifyTestTx(t, 8)
+
+		results, err := interpreter.Verify(tx, interpreter.WithWorkers(3))
+		require.NoError(t, err)
+		require.Len(t, results, 8)
+		for _, r := range results {
+			require.NoError(t, r.Err)
+		}
+	})
+
+	t.Run("invalid inputs are reported", func(t *testing.T) {
+		tx := newVerifyTestTx(t, 4)
+		tx.Inputs[1].PreviousTxSatoshis++
+		tx.Inputs[3].PreviousTxSatoshis++
+
+		for _, workers := range []int{0, 4} {
+			results, err := interpreter.Verify(tx, interpreter.WithWorkers(workers))
+			require.Error(t, err)
+			assert.True(t, errs.IsErrorCode(err, errs.ErrNullFail))
+			require.Len(t, results, 4)
+
+			require.NoError(t, results[0].Err)
+			assert.True(t, errs.IsErrorCode(results[1].Err, errs.ErrNullFail))
+			require.NoError(t, results[2].Err)
+			assert.True(t, errs.IsErrorCode(results[3].Err, errs.ErrNullFail))
+		}
+	})
+
+	t.Run("utxo heights derive flags", func(t *testing.T) {
+		tx := newVerifyTestTx(t, 2)
+
+		results, err := interpreter.Verify(
+			tx,
+			interpreter.WithUTXOHeights(chaincfg.MainNetParams.GenesisHeight-1, chaincfg.MainNetParams.GenesisHeight),
+		)
+		require.NoError(t, err)
+
+		assert.False(t, results[0].Flags.HasFlag(scriptflag.UTXOAfterGenesis))
+		assert.True(t, results[0].Flags.HasFlag(scriptflag.Bip16))
+		assert.True(t, results[0].Flags.HasFlag(scriptflag.VerifyCheckLockTimeVerify))
+		assert.True(t, results[1].Flags.HasFlag(scriptflag.UTXOAfterGenesis))
+		assert.False(t, results[1].Flags.HasFlag(scriptflag.Bip16))
+		assert.False(t, results[1].Flags.HasFlag(scriptflag.EnableChronicle))
+	})
+
+	t.Run("network derives flags", func(t *testing.T) {
+		params := &chaincfg.TestNetParams
+		tx := newVerifyTestTx(t, 2)
+
+		results, err := interpreter.Verify(
+			tx,
+			interpreter.WithUTXOHeights(params.GenesisHeight-1, params.GenesisHeight),
+			interpreter.WithVerifyNetwork(params, params.ChronicleHeight-1),
+		)
+		require.NoError(t, err)
+		assert.False(t, results[0].Flags.HasFlag(scriptflag.UTXOAfterGenesis))
+		assert.True(t, results[1].Flags.HasFlag(scriptflag.UTXOAfterGenesis))
+		assert.False(t, results[1].Flags.HasFlag(scriptflag.EnableChronicle))
+
+		results, err = interpreter.Verify(
+			tx,
+			interpreter.WithUTXOHeights(params.GenesisHeight, params.GenesisHeight),
+			interpreter.WithVerifyNetwork(params, params.ChronicleHeight),
+		)
+		require.NoError(t, err)
+		for _, r := range results {
+			assert.True(t, r.Flags.HasFlag(scriptflag.EnableChronicle))
+		}
+	})
+
+	t.Run("additional flags", func(t *testing.T) {
+		tx := newVerifyTestTx(t, 1)
+
+		results, err := interpreter.Verify(tx, interpreter.WithVerifyFlags(scriptflag.VerifyMinimalData))
+		require.NoError(t, err)
+		assert.True(t, results[0].Flags.HasFlag(scriptflag.VerifyMinimalData))
+	})
+
+	t.Run("sig cache", func(t *testing.T) {
+		tx := newVerifyTestTx(t, 3)
+		cache := interpreter.NewSigCache(10)
+
+		for range 2 {
+			_, err := interpreter.Verify(tx, interpreter.WithVerifySigCache(cache), interpreter.WithWorkers(3))
+			require.NoError(t, err)
+			assert.Equal(t, 3, cache.Len())
+		}
+
+		// A changed sighash is not found in the cache.
+		tx.Inputs[1].PreviousTxSatoshis++
+		results, err := interpreter.Verify(tx, interpreter.WithVerifySigCache(cache))
+		require.Error(t, err)
+		assert.True(t, errs.IsErrorCode(results[1].Err, errs.ErrNullFail))
+		assert.Equal(t, 3, cache.Len())
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		tx := newVerifyTestTx(t, 2)
+
+		_, err := interpreter.Verify(tx, interpreter.WithUTXOHeights(1))
+		assert.True(t, errs.IsErrorCode(err, errs.ErrInvalidParams))
+
+		_, err = interpreter.Verify(nil)
+		assert.True(t, errs.IsErrorCode(err, errs.ErrInvalidParams))
+
+		_, err = interpreter.Verify(tx, interpreter.WithVerifyNetwork(nil, 0))
+		assert.True(t, errs.IsErrorCode(err, errs.ErrInvalidParams))
+
+		tx.Inputs[0].PreviousTxScript = nil
+		results, err := interpreter.Verify(tx)
+		assert.True(t, errs.IsErrorCode(err, errs.ErrInvalidParams))
+		assert.Nil(t, results)
+	})
+}
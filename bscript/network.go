@@ -0,0 +1,18 @@
+package bscript
+
+import "github.com/bsv-blockchain/go-bt/v2/chaincfg"
+
+// Network identifies the network an address is for. The address version bytes
+// of each network are defined by its chaincfg.Params.
+type Network = chaincfg.Network
+
+// Networks supported for addresses.
+//
+// Testnet, STN and regtest share address version bytes, so an address decoded
+// from a string on any of those networks reports Testnet.
+const (
+	Mainnet = chaincfg.Mainnet
+	Testnet = chaincfg.Testnet
+	STN     = chaincfg.STN
+	Regtest = chaincfg.Regtest
+)
@@ -190,10 +190,15 @@ const (
 	OpNOP3                byte = 0xb2 // 178
 	OpCHECKSEQUENCEVERIFY byte = 0xb2 // 178
 	OpNOP4                byte = 0xb3 // 179
+	OpSUBSTR              byte = 0xb3 // 179
 	OpNOP5                byte = 0xb4 // 180
+	OpLEFT                byte = 0xb4 // 180
 	OpNOP6                byte = 0xb5 // 181
+	OpRIGHT               byte = 0xb5 // 181
 	OpNOP7                byte = 0xb6 // 182
+	OpLSHIFTNUM           byte = 0xb6 // 182
 	OpNOP8                byte = 0xb7 // 183
+	OpRSHIFTNUM           byte = 0xb7 // 183
 	OpNOP9                byte = 0xb8 // 184
 	OpNOP10               byte = 0xb9 // 185
 	OpUNKNOWN186          byte = 0xba // 186
@@ -458,6 +463,11 @@ var opCodeStrings = map[string]byte{
 	"OP_NOP6":                OpNOP6,
 	"OP_NOP7":                OpNOP7,
 	"OP_NOP8":                OpNOP8,
+	"OP_SUBSTR":              OpSUBSTR,
+	"OP_LEFT":                OpLEFT,
+	"OP_RIGHT":               OpRIGHT,
+	"OP_LSHIFTNUM":           OpLSHIFTNUM,
+	"OP_RSHIFTNUM":           OpRSHIFTNUM,
 	"OP_NOP9":                OpNOP9,
 	"OP_NOP10":               OpNOP10,
 	"OP_UNKNOWN186":          OpUNKNOWN186,
@@ -532,6 +542,9 @@ var opCodeStrings = map[string]byte{
 	"OP_INVALIDOPCODE":       OpINVALIDOPCODE,
 }
 
+// opCodeValues are the names used in ASM. 0xb3 - 0xb7 are named after their
+// Chronicle opcodes, including in scripts where they are still NOPs, so were
+// OP_NOP4 - OP_NOP8 in the ASM of earlier versions.
 var opCodeValues = map[byte]string{
 	OpFALSE:               "OP_FALSE",
 	OpDATA1:               "OP_DATA_1",
@@ -712,11 +725,11 @@ var opCodeValues = map[byte]string{
 	OpNOP1:                "OP_NOP1",
 	OpNOP2:                "OP_NOP2",
 	OpNOP3:                "OP_NOP3",
-	OpNOP4:                "OP_NOP4",
-	OpNOP5:                "OP_NOP5",
-	OpNOP6:                "OP_NOP6",
-	OpNOP7:                "OP_NOP7",
-	OpNOP8:                "OP_NOP8",
+	OpSUBSTR:              "OP_SUBSTR",
+	OpLEFT:                "OP_LEFT",
+	OpRIGHT:               "OP_RIGHT",
+	OpLSHIFTNUM:           "OP_LSHIFTNUM",
+	OpRSHIFTNUM:           "OP_RSHIFTNUM",
 	OpNOP9:                "OP_NOP9",
 	OpNOP10:               "OP_NOP10",
 	OpUNKNOWN186:          "OP_UNKNOWN186",
@@ -111,11 +111,16 @@ func NewP2PKHFromPubKeyHashStr(pubKeyHash string) (*Script, error) {
 
 // NewP2PKHFromAddress takes an address
 // and creates a P2PKH script from it.
+//
+// Legacy P2SH addresses are rejected with ErrP2SHAddress.
 func NewP2PKHFromAddress(addr string) (*Script, error) {
 	a, err := NewAddressFromString(addr)
 	if err != nil {
 		return nil, err
 	}
+	if a.IsP2SH() {
+		return nil, fmt.Errorf("%w: %s", ErrP2SHAddress, addr)
+	}
 
 	var publicKeyHashBytes []byte
 	if publicKeyHashBytes, err = hex.DecodeString(a.PublicKeyHash); err != nil {
@@ -132,6 +137,18 @@ func NewP2PKHFromAddress(addr string) (*Script, error) {
 	return s, nil
 }
 
+// NewMultiSigFromPubKeys takes a number of required signatures m and public keys
+// (serialised in compressed format) and creates an m-of-n bare multisig script
+// from them, being OP_m <pubkey>... OP_n OP_CHECKMULTISIG.
+func NewMultiSigFromPubKeys(m int, pubKeys []*bec.PublicKey) (*Script, error) {
+	keys := make([][]byte, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		keys[i] = pubKey.Compressed()
+	}
+
+	return MultiSigTemplate{}.Lock(&TemplateParams{RequiredSigs: m, PublicKeys: keys})
+}
+
 // NewP2PKHFromBip32ExtKey takes a *bip32.ExtendedKey and creates a P2PKH script from it,
 // using an internally random generated seed, returning the script and derivation path used.
 func NewP2PKHFromBip32ExtKey(privKey *bip32.ExtendedKey) (*Script, string, error) {
@@ -229,6 +246,9 @@ func (s *Script) Bytes() []byte {
 }
 
 // ToASM returns the string ASM opcodes of the script.
+//
+// 0xb3 - 0xb7 are written as OP_SUBSTR, OP_LEFT, OP_RIGHT, OP_LSHIFTNUM and
+// OP_RSHIFTNUM, whether or not the script is executed with the Chronicle rules.
 func (s *Script) ToASM() (string, error) {
 	if s == nil || len(*s) == 0 {
 		return "", nil
@@ -428,6 +448,17 @@ func isSmallIntOp(opcode byte) bool {
 	return opcode == OpZERO || (opcode >= OpONE && opcode <= Op16)
 }
 
+// ParseMultiSig returns the number of signatures required by a bare multisig
+// script, along with its public keys in the order they appear.
+func (s *Script) ParseMultiSig() (int, [][]byte, error) {
+	params, err := MultiSigTemplate{}.Params(s)
+	if err != nil {
+		return 0, nil, ErrNotMultiSig
+	}
+
+	return params.RequiredSigs, params.PublicKeys, nil
+}
+
 // PublicKeyHash returns a public key hash byte array if the script is a P2PKH script.
 func (s *Script) PublicKeyHash() ([]byte, error) {
 	if s == nil || len(*s) == 0 {
@@ -446,51 +477,49 @@ func (s *Script) PublicKeyHash() ([]byte, error) {
 	return parts[0], nil
 }
 
-// ScriptType returns the type of script this is as a string.
+// ScriptType returns the type of script this is as a string, being the type of
+// the template which matches it. See RegisterTemplate.
 func (s *Script) ScriptType() string {
 	if len(*s) == 0 {
 		return ScriptTypeEmpty
 	}
-	if s.IsP2PKH() {
-		return ScriptTypePubKeyHash
-	}
-	if s.IsP2PK() {
-		return ScriptTypePubKey
-	}
-	if s.IsMultiSigOut() {
-		return ScriptTypeMultiSig
-	}
-	if s.IsData() {
-		return ScriptTypeNullData
-	}
-	if s.IsP2PKHInscription() {
-		return ScriptTypePubKeyHashInscription
+	if t, ok := TemplateFor(s); ok {
+		return t.Type()
 	}
 	return ScriptTypeNonStandard
 }
 
-// Addresses will return all addresses found in the script, if any.
-// param mainnetBool if true, will return mainnet addresses, if false will return testnet addresses
-func (s *Script) Addresses(mainnetBool ...bool) ([]string, error) {
-	mainnet := true
-	if len(mainnetBool) > 0 {
-		mainnet = mainnetBool[0]
+// Addresses will return all addresses found in the script, if any, being those
+// of the public key hashes and public keys of the template which matches it. This
+// covers P2PKH, P2PK, bare multisig and P2PKH inscription scripts.
+// param net is the network to return addresses for, being mainnet if omitted.
+func (s *Script) Addresses(net ...Network) ([]string, error) {
+	network := Mainnet
+	if len(net) > 0 {
+		network = net[0]
 	}
 
 	addresses := make([]string, 0)
-	if s.IsP2PKH() {
-		pkh, err := s.PublicKeyHash()
-		if err != nil {
-			return nil, err
-		}
-		a, err := NewAddressFromPublicKeyHash(pkh, mainnet)
+	t, ok := TemplateFor(s)
+	if !ok {
+		return addresses, nil
+	}
+	params, err := t.Params(s)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := params.PublicKeyHashes
+	for _, pubKey := range params.PublicKeys {
+		hashes = append(hashes, crypto.Hash160(pubKey))
+	}
+	for _, pkh := range hashes {
+		a, err := NewAddressFromPublicKeyHashForNetwork(pkh, network)
 		if err != nil {
 			return nil, err
 		}
-		addresses = []string{a.AddressString}
+		addresses = append(addresses, a.AddressString)
 	}
-	// TODO: handle multisig, and other outputs
-	// https://github.com/bsv-blockchain/go-bt/issues/6
 	return addresses, nil
 }
 
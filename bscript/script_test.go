@@ -52,6 +52,60 @@ func TestNewP2PKHFromPubKey(t *testing.T) {
 	)
 }
 
+func TestNewMultiSigFromPubKeys(t *testing.T) {
+	t.Parallel()
+
+	var pubKeys []*bec.PublicKey
+	for _, k := range []string{
+		"023717efaec6761e457f55c8417815505b695209d0bbfed8c3265be425b373c2d6",
+		"03b8b40a84123121d260f5c109bc5a46ec819c2e4002e5ba08638783bfb4e01435",
+	} {
+		b, err := hex.DecodeString(k)
+		require.NoError(t, err)
+		pubKey, err := bec.ParsePubKey(b)
+		require.NoError(t, err)
+		pubKeys = append(pubKeys, pubKey)
+	}
+
+	t.Run("1 of 2", func(t *testing.T) {
+		s, err := bscript.NewMultiSigFromPubKeys(1, pubKeys)
+		require.NoError(t, err)
+		assert.Equal(t,
+			"5121023717efaec6761e457f55c8417815505b695209d0bbfed8c3265be425b373c2d6"+
+				"2103b8b40a84123121d260f5c109bc5a46ec819c2e4002e5ba08638783bfb4e0143552ae",
+			s.String(),
+		)
+		assert.True(t, s.IsMultiSigOut())
+
+		m, keys, err := s.ParseMultiSig()
+		require.NoError(t, err)
+		assert.Equal(t, 1, m)
+		assert.Equal(t, [][]byte{pubKeys[0].Compressed(), pubKeys[1].Compressed()}, keys)
+	})
+
+	t.Run("more sigs than keys", func(t *testing.T) {
+		_, err := bscript.NewMultiSigFromPubKeys(3, pubKeys)
+		require.ErrorIs(t, err, bscript.ErrTemplateParams)
+	})
+
+	t.Run("parse key count not matching keys", func(t *testing.T) {
+		// OP_3 <pubkey> OP_5 OP_CHECKMULTISIG
+		s, err := bscript.NewFromHexString("53" + "21" + hex.EncodeToString(pubKeys[0].Compressed()) + "55ae")
+		require.NoError(t, err)
+
+		_, _, err = s.ParseMultiSig()
+		require.ErrorIs(t, err, bscript.ErrNotMultiSig)
+	})
+
+	t.Run("parse non multisig", func(t *testing.T) {
+		s, err := bscript.NewP2PKHFromPubKeyEC(pubKeys[0])
+		require.NoError(t, err)
+
+		_, _, err = s.ParseMultiSig()
+		require.ErrorIs(t, err, bscript.ErrNotMultiSig)
+	})
+}
+
 func TestNewP2PKHFromBip32ExtKey(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,383 @@
+package bscript
+
+import (
+	"fmt"
+	"sync"
+
+	crypto "github.com/bsv-blockchain/go-sdk/primitives/hash"
+)
+
+// ScriptTemplate describes a kind of locking script, such as P2PKH. It can
+// recognise scripts of its kind, extract their parameters and build new ones.
+//
+// Templates are registered with RegisterTemplate, after which they drive
+// Script.ScriptType and Script.Addresses.
+type ScriptTemplate interface {
+	// Type returns the name of the template, as reported by Script.ScriptType.
+	Type() string
+	// Match returns true if the script is of the kind described by the template.
+	Match(s *Script) bool
+	// Params extracts the parameters of a matching script, returning
+	// ErrTemplateMismatch if it does not match.
+	Params(s *Script) (*TemplateParams, error)
+	// Lock builds a locking script from the parameters, returning
+	// ErrTemplateParams if they are not valid for the template.
+	Lock(p *TemplateParams) (*Script, error)
+}
+
+// TemplateParams are the parameters of a script template. Each template uses
+// only the fields relevant to it.
+type TemplateParams struct {
+	// PublicKeyHashes are the hash160s of the public keys the script pays to.
+	PublicKeyHashes [][]byte
+	// PublicKeys are the public keys the script pays to.
+	PublicKeys [][]byte
+	// RequiredSigs is the number of signatures required to unlock the script.
+	RequiredSigs int
+	// Hash is any other hash the script commits to.
+	Hash []byte
+	// Data are the data pushes of a data script, or the content type and
+	// content of an inscription.
+	Data [][]byte
+}
+
+var (
+	templatesMu sync.RWMutex
+	templates   []*registeredTemplate
+
+	builtinTemplates = []ScriptTemplate{
+		P2PKHTemplate{},
+		P2PKTemplate{},
+		MultiSigTemplate{},
+		NullDataTemplate{},
+		P2PKHInscriptionTemplate{},
+	}
+)
+
+// registeredTemplate is a registration of a template, identifying it for
+// removal as templates themselves need not be comparable.
+type registeredTemplate struct {
+	ScriptTemplate
+}
+
+// RegisterTemplate registers a script template. Templates are matched in the
+// reverse order of their registration, before the builtin templates, so that a
+// template can refine or override those registered before it.
+//
+// The returned function removes the registration again, such as in a test, and
+// does nothing once it has been removed:
+//
+//	t.Cleanup(bscript.RegisterTemplate(myTemplate{}))
+func RegisterTemplate(t ScriptTemplate) (unregister func()) {
+	templatesMu.Lock()
+	defer templatesMu.Unlock()
+
+	rt := &registeredTemplate{t}
+	templates = append(templates, rt)
+
+	return func() {
+		templatesMu.Lock()
+		defer templatesMu.Unlock()
+
+		for i := range templates {
+			if templates[i] == rt {
+				templates = append(templates[:i:i], templates[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// TemplateFor returns the template which matches the script, or false if none do.
+func TemplateFor(s *Script) (ScriptTemplate, bool) {
+	templatesMu.RLock()
+	defer templatesMu.RUnlock()
+
+	for i := len(templates) - 1; i >= 0; i-- {
+		if templates[i].Match(s) {
+			return templates[i].ScriptTemplate, true
+		}
+	}
+	for _, t := range builtinTemplates {
+		if t.Match(s) {
+			return t, true
+		}
+	}
+
+	return nil, false
+}
+
+// TemplateByType returns the template with the given type, or false if there
+// is none.
+func TemplateByType(scriptType string) (ScriptTemplate, bool) {
+	templatesMu.RLock()
+	defer templatesMu.RUnlock()
+
+	for i := len(templates) - 1; i >= 0; i-- {
+		if templates[i].Type() == scriptType {
+			return templates[i].ScriptTemplate, true
+		}
+	}
+	for _, t := range builtinTemplates {
+		if t.Type() == scriptType {
+			return t, true
+		}
+	}
+
+	return nil, false
+}
+
+// P2PKHTemplate is the template of a pay to public key hash script.
+type P2PKHTemplate struct{}
+
+// Type returns ScriptTypePubKeyHash.
+func (P2PKHTemplate) Type() string { return ScriptTypePubKeyHash }
+
+// Match returns true if the script is P2PKH.
+func (P2PKHTemplate) Match(s *Script) bool { return s.IsP2PKH() }
+
+// Params returns the public key hash of the script.
+func (t P2PKHTemplate) Params(s *Script) (*TemplateParams, error) {
+	if !t.Match(s) {
+		return nil, ErrTemplateMismatch
+	}
+
+	return &TemplateParams{PublicKeyHashes: [][]byte{(*s)[3:23]}}, nil
+}
+
+// Lock builds a P2PKH script from a single public key hash, or a single
+// public key.
+func (P2PKHTemplate) Lock(p *TemplateParams) (*Script, error) {
+	switch {
+	case len(p.PublicKeyHashes) == 1 && len(p.PublicKeyHashes[0]) == 20:
+		return NewP2PKHFromPubKeyHash(p.PublicKeyHashes[0])
+	case len(p.PublicKeyHashes) == 0 && len(p.PublicKeys) == 1:
+		return NewP2PKHFromPubKeyHash(crypto.Hash160(p.PublicKeys[0]))
+	default:
+		return nil, fmt.Errorf("%w: P2PKH requires one public key hash or public key", ErrTemplateParams)
+	}
+}
+
+// P2PKTemplate is the template of a pay to public key script.
+type P2PKTemplate struct{}
+
+// Type returns ScriptTypePubKey.
+func (P2PKTemplate) Type() string { return ScriptTypePubKey }
+
+// Match returns true if the script is P2PK.
+func (P2PKTemplate) Match(s *Script) bool { return s.IsP2PK() }
+
+// Params returns the public key of the script.
+func (t P2PKTemplate) Params(s *Script) (*TemplateParams, error) {
+	if !t.Match(s) {
+		return nil, ErrTemplateMismatch
+	}
+
+	parts, err := DecodeParts(*s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TemplateParams{PublicKeys: [][]byte{parts[0]}}, nil
+}
+
+// Lock builds a P2PK script from a single public key.
+func (P2PKTemplate) Lock(p *TemplateParams) (*Script, error) {
+	if len(p.PublicKeys) != 1 {
+		return nil, fmt.Errorf("%w: P2PK requires one public key", ErrTemplateParams)
+	}
+
+	s := &Script{}
+	if err := s.AppendPushData(p.PublicKeys[0]); err != nil {
+		return nil, err
+	}
+	_ = s.AppendOpcodes(OpCHECKSIG)
+
+	return s, nil
+}
+
+// MultiSigTemplate is the template of a bare multisig script.
+type MultiSigTemplate struct{}
+
+// Type returns ScriptTypeMultiSig.
+func (MultiSigTemplate) Type() string { return ScriptTypeMultiSig }
+
+// Match returns true if the script is bare multisig, with as many public keys
+// as its key count and between 1 and that many required signatures.
+func (MultiSigTemplate) Match(s *Script) bool {
+	_, _, ok := parseMultiSig(s)
+	return ok
+}
+
+// Params returns the public keys of the script and the number of signatures
+// required.
+func (MultiSigTemplate) Params(s *Script) (*TemplateParams, error) {
+	m, pubKeys, ok := parseMultiSig(s)
+	if !ok {
+		return nil, ErrTemplateMismatch
+	}
+
+	return &TemplateParams{
+		RequiredSigs: m,
+		PublicKeys:   pubKeys,
+	}, nil
+}
+
+// parseMultiSig returns the number of required signatures and the public keys
+// of a bare multisig script, or false if the script is not one or can never be
+// spent, as its key count does not match its keys or it requires no signatures
+// or more signatures than it has keys.
+func parseMultiSig(s *Script) (int, [][]byte, bool) {
+	if !s.IsMultiSigOut() {
+		return 0, nil, false
+	}
+
+	parts, err := DecodeParts(*s)
+	if err != nil {
+		return 0, nil, false
+	}
+
+	m := smallIntValue(parts[0][0])
+	n := smallIntValue(parts[len(parts)-2][0])
+	pubKeys := parts[1 : len(parts)-2]
+	if n != len(pubKeys) || m < 1 || m > n {
+		return 0, nil, false
+	}
+
+	return m, pubKeys, true
+}
+
+// Lock builds a bare multisig script requiring RequiredSigs signatures from
+// the public keys.
+func (MultiSigTemplate) Lock(p *TemplateParams) (*Script, error) {
+	n := len(p.PublicKeys)
+	if n == 0 || n > 16 || p.RequiredSigs < 1 || p.RequiredSigs > n {
+		return nil, fmt.Errorf(
+			"%w: multisig requires 1 to 16 public keys and between 1 and that many required signatures",
+			ErrTemplateParams,
+		)
+	}
+
+	s := &Script{}
+	_ = s.AppendOpcodes(smallIntOp(p.RequiredSigs))
+	if err := s.AppendPushDataArray(p.PublicKeys); err != nil {
+		return nil, err
+	}
+	_ = s.AppendOpcodes(smallIntOp(n), OpCHECKMULTISIG)
+
+	return s, nil
+}
+
+// NullDataTemplate is the template of a data script, being OP_RETURN or
+// OP_FALSE OP_RETURN followed by data pushes.
+type NullDataTemplate struct{}
+
+// Type returns ScriptTypeNullData.
+func (NullDataTemplate) Type() string { return ScriptTypeNullData }
+
+// Match returns true if the script is a data script.
+func (NullDataTemplate) Match(s *Script) bool { return s.IsData() }
+
+// Params returns the parts of the script following the OP_RETURN.
+func (t NullDataTemplate) Params(s *Script) (*TemplateParams, error) {
+	if !t.Match(s) {
+		return nil, ErrTemplateMismatch
+	}
+
+	data := (*s)[1:]
+	if (*s)[0] == OpFALSE {
+		data = (*s)[2:]
+	}
+
+	parts, err := DecodeParts(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TemplateParams{Data: parts}, nil
+}
+
+// Lock builds an OP_FALSE OP_RETURN script pushing the data.
+func (NullDataTemplate) Lock(p *TemplateParams) (*Script, error) {
+	s := &Script{OpFALSE, OpRETURN}
+	if err := s.AppendPushDataArray(p.Data); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// P2PKHInscriptionTemplate is the template of a P2PKH script followed by an
+// ordinals inscription.
+type P2PKHInscriptionTemplate struct{}
+
+// Type returns ScriptTypePubKeyHashInscription.
+func (P2PKHInscriptionTemplate) Type() string { return ScriptTypePubKeyHashInscription }
+
+// Match returns true if the script is a P2PKH inscription.
+func (P2PKHInscriptionTemplate) Match(s *Script) bool { return s.IsP2PKHInscription() }
+
+// Params returns the public key hash of the script, and the content type and
+// content of the inscription as Data.
+func (t P2PKHInscriptionTemplate) Params(s *Script) (*TemplateParams, error) {
+	if !t.Match(s) {
+		return nil, ErrTemplateMismatch
+	}
+
+	ia, err := s.ParseInscription()
+	if err != nil {
+		return nil, err
+	}
+
+	return &TemplateParams{
+		PublicKeyHashes: [][]byte{(*s)[3:23]},
+		Data:            [][]byte{[]byte(ia.ContentType), ia.Data},
+	}, nil
+}
+
+// Lock builds a P2PKH inscription from a single public key hash, and the
+// content type and content as Data.
+func (P2PKHInscriptionTemplate) Lock(p *TemplateParams) (*Script, error) {
+	if len(p.Data) != 2 {
+		return nil, fmt.Errorf("%w: inscription requires content type and content data", ErrTemplateParams)
+	}
+
+	s, err := P2PKHTemplate{}.Lock(p)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = s.AppendOpcodes(OpFALSE, OpIF)
+	if err = s.AppendPushDataString("ord"); err != nil {
+		return nil, err
+	}
+	_ = s.AppendOpcodes(Op1)
+	if err = s.AppendPushData(p.Data[0]); err != nil {
+		return nil, err
+	}
+	_ = s.AppendOpcodes(Op0)
+	if err = s.AppendPushData(p.Data[1]); err != nil {
+		return nil, err
+	}
+	_ = s.AppendOpcodes(OpENDIF)
+
+	return s, nil
+}
+
+// smallIntValue returns the value pushed by OP_0 or OP_1 to OP_16.
+func smallIntValue(op byte) int {
+	if op == OpZERO {
+		return 0
+	}
+
+	return int(op-OpONE) + 1
+}
+
+// smallIntOp returns the op pushing n, which must be between 0 and 16.
+func smallIntOp(n int) byte {
+	if n == 0 {
+		return OpZERO
+	}
+
+	return OpONE + byte(n-1)
+}
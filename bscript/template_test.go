@@ -0,0 +1,295 @@
+package bscript_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+)
+
+// sha256Puzzle is a custom template of OP_SHA256 <hash> OP_EQUAL.
+type sha256Puzzle struct{}
+
+func (sha256Puzzle) Type() string { return "sha256puzzle" }
+
+func (sha256Puzzle) Match(s *bscript.Script) bool {
+	b := []byte(*s)
+	return len(b) == 35 && b[0] == bscript.OpSHA256 && b[1] == bscript.OpDATA32 && b[34] == bscript.OpEQUAL
+}
+
+func (t sha256Puzzle) Params(s *bscript.Script) (*bscript.TemplateParams, error) {
+	if !t.Match(s) {
+		return nil, bscript.ErrTemplateMismatch
+	}
+	return &bscript.TemplateParams{Hash: (*s)[2:34]}, nil
+}
+
+func (sha256Puzzle) Lock(p *bscript.TemplateParams) (*bscript.Script, error) {
+	if len(p.Hash) != 32 {
+		return nil, bscript.ErrTemplateParams
+	}
+	s := &bscript.Script{bscript.OpSHA256}
+	if err := s.AppendPushData(p.Hash); err != nil {
+		return nil, err
+	}
+	_ = s.AppendOpcodes(bscript.OpEQUAL)
+	return s, nil
+}
+
+func TestScriptTemplates(t *testing.T) {
+	t.Parallel()
+
+	pubKey, err := hex.DecodeString("023717efaec6761e457f55c8417815505b695209d0bbfed8c3265be425b373c2d6")
+	require.NoError(t, err)
+	pubKey2, err := hex.DecodeString("03b8b40a84123121d260f5c109bc5a46ec819c2e4002e5ba08638783bfb4e01435")
+	require.NoError(t, err)
+	pkh, err := hex.DecodeString("55b61be43392125d127f1780fb038437cd67ef9c")
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		template  bscript.ScriptTemplate
+		params    *bscript.TemplateParams
+		expScript string
+	}{
+		"p2pkh": {
+			template:  bscript.P2PKHTemplate{},
+			params:    &bscript.TemplateParams{PublicKeyHashes: [][]byte{pkh}},
+			expScript: "76a91455b61be43392125d127f1780fb038437cd67ef9c88ac",
+		},
+		"p2pk": {
+			template:  bscript.P2PKTemplate{},
+			params:    &bscript.TemplateParams{PublicKeys: [][]byte{pubKey}},
+			expScript: "21023717efaec6761e457f55c8417815505b695209d0bbfed8c3265be425b373c2d6ac",
+		},
+		"multisig": {
+			template: bscript.MultiSigTemplate{},
+			params:   &bscript.TemplateParams{RequiredSigs: 1, PublicKeys: [][]byte{pubKey, pubKey2}},
+			expScript: "5121023717efaec6761e457f55c8417815505b695209d0bbfed8c3265be425b373c2d6" +
+				"2103b8b40a84123121d260f5c109bc5a46ec819c2e4002e5ba08638783bfb4e0143552ae",
+		},
+		"nulldata": {
+			template:  bscript.NullDataTemplate{},
+			params:    &bscript.TemplateParams{Data: [][]byte{[]byte("hello"), []byte("world")}},
+			expScript: "006a0568656c6c6f05776f726c64",
+		},
+		"p2pkh inscription": {
+			template: bscript.P2PKHInscriptionTemplate{},
+			params: &bscript.TemplateParams{
+				PublicKeyHashes: [][]byte{pkh},
+				Data:            [][]byte{[]byte("text/plain"), []byte("hi")},
+			},
+			expScript: "76a91455b61be43392125d127f1780fb038437cd67ef9c88ac" +
+				"0063036f7264510a746578742f706c61696e00026869" + "68",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := test.template.Lock(test.params)
+			require.NoError(t, err)
+			assert.Equal(t, test.expScript, s.String())
+
+			assert.True(t, test.template.Match(s))
+			assert.Equal(t, test.template.Type(), s.ScriptType())
+
+			tmpl, ok := bscript.TemplateFor(s)
+			require.True(t, ok)
+			assert.Equal(t, test.template, tmpl)
+
+			params, err := test.template.Params(s)
+			require.NoError(t, err)
+			assert.Equal(t, test.params, params)
+		})
+	}
+
+	t.Run("p2pkh from public key", func(t *testing.T) {
+		s, err := bscript.P2PKHTemplate{}.Lock(&bscript.TemplateParams{PublicKeys: [][]byte{pubKey2}})
+		require.NoError(t, err)
+		assert.Equal(t, "76a914c0a3c167a28cabb9fbb495affa0761e6e74ac60d88ac", s.String())
+	})
+
+	t.Run("params of mismatched script", func(t *testing.T) {
+		s, err := bscript.NewFromHexString("006a0568656c6c6f")
+		require.NoError(t, err)
+
+		for _, tmpl := range []bscript.ScriptTemplate{
+			bscript.P2PKHTemplate{},
+			bscript.P2PKTemplate{},
+			bscript.MultiSigTemplate{},
+			bscript.P2PKHInscriptionTemplate{},
+		} {
+			_, err = tmpl.Params(s)
+			require.ErrorIs(t, err, bscript.ErrTemplateMismatch)
+		}
+	})
+
+	t.Run("unspendable multisig", func(t *testing.T) {
+		tests := map[string]string{
+			"key count above keys": "53" + "21" + hex.EncodeToString(pubKey) + "55ae",
+			"key count below keys": "51" + "21" + hex.EncodeToString(pubKey) + "21" + hex.EncodeToString(pubKey2) + "51ae",
+			"more sigs than keys":  "53" + "21" + hex.EncodeToString(pubKey) + "21" + hex.EncodeToString(pubKey2) + "52ae",
+			"no sigs required":     "00" + "21" + hex.EncodeToString(pubKey) + "51ae",
+			"no keys":              "0000ae",
+		}
+		for name, script := range tests {
+			t.Run(name, func(t *testing.T) {
+				s, err := bscript.NewFromHexString(script)
+				require.NoError(t, err)
+
+				assert.False(t, bscript.MultiSigTemplate{}.Match(s))
+				assert.NotEqual(t, bscript.ScriptTypeMultiSig, s.ScriptType())
+				_, err = bscript.MultiSigTemplate{}.Params(s)
+				require.ErrorIs(t, err, bscript.ErrTemplateMismatch)
+				_, _, err = s.ParseMultiSig()
+				require.ErrorIs(t, err, bscript.ErrNotMultiSig)
+			})
+		}
+	})
+
+	t.Run("invalid lock params", func(t *testing.T) {
+		tests := map[string]struct {
+			template bscript.ScriptTemplate
+			params   *bscript.TemplateParams
+		}{
+			"p2pkh without hash": {
+				template: bscript.P2PKHTemplate{},
+				params:   &bscript.TemplateParams{},
+			},
+			"p2pk with two keys": {
+				template: bscript.P2PKTemplate{},
+				params:   &bscript.TemplateParams{PublicKeys: [][]byte{pubKey, pubKey2}},
+			},
+			"multisig requiring too many sigs": {
+				template: bscript.MultiSigTemplate{},
+				params:   &bscript.TemplateParams{RequiredSigs: 3, PublicKeys: [][]byte{pubKey, pubKey2}},
+			},
+			"multisig requiring no sigs": {
+				template: bscript.MultiSigTemplate{},
+				params:   &bscript.TemplateParams{PublicKeys: [][]byte{pubKey}},
+			},
+			"inscription without content": {
+				template: bscript.P2PKHInscriptionTemplate{},
+				params:   &bscript.TemplateParams{PublicKeyHashes: [][]byte{pkh}},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := test.template.Lock(test.params)
+				require.ErrorIs(t, err, bscript.ErrTemplateParams)
+			})
+		}
+	})
+}
+
+// TestRegisterTemplate is not parallel, as the registered template is seen by
+// every test until it is unregistered.
+func TestRegisterTemplate(t *testing.T) {
+	hash := sha256.Sum256([]byte("secret"))
+	s, err := sha256Puzzle{}.Lock(&bscript.TemplateParams{Hash: hash[:]})
+	require.NoError(t, err)
+
+	assert.Equal(t, bscript.ScriptTypeNonStandard, s.ScriptType())
+	_, ok := bscript.TemplateByType("sha256puzzle")
+	assert.False(t, ok)
+
+	unregister := bscript.RegisterTemplate(sha256Puzzle{})
+	t.Cleanup(unregister)
+
+	assert.Equal(t, "sha256puzzle", s.ScriptType())
+
+	tmpl, ok := bscript.TemplateByType("sha256puzzle")
+	require.True(t, ok)
+	params, err := tmpl.Params(s)
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(hash[:], params.Hash))
+
+	addresses, err := s.Addresses()
+	require.NoError(t, err)
+	assert.Empty(t, addresses)
+
+	tmpl, ok = bscript.TemplateByType(bscript.ScriptTypePubKeyHash)
+	require.True(t, ok)
+	assert.Equal(t, bscript.P2PKHTemplate{}, tmpl)
+
+	unregister()
+	assert.Equal(t, bscript.ScriptTypeNonStandard, s.ScriptType())
+	_, ok = bscript.TemplateByType("sha256puzzle")
+	assert.False(t, ok)
+}
+
+func TestScript_Addresses_Inscription(t *testing.T) {
+	t.Parallel()
+
+	s, err := bscript.P2PKHInscriptionTemplate{}.Lock(&bscript.TemplateParams{
+		PublicKeyHashes: [][]byte{{
+			0x8f, 0xe8, 0x0c, 0x75, 0xc9, 0x56, 0x0e, 0x8b, 0x56, 0xed,
+			0x64, 0xea, 0x3c, 0x26, 0xe1, 0x8d, 0x2c, 0x52, 0x21, 0x1b,
+		}},
+		Data: [][]byte{[]byte("text/plain"), []byte("hi")},
+	})
+	require.NoError(t, err)
+
+	addresses, err := s.Addresses()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1E7ucTTWRTahCyViPhxSMor2pj4VGQdFMr"}, addresses)
+
+	addresses, err = s.Addresses(bscript.Testnet)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mtdruWYVEV1wz5yL7GvpBj4MgifCB7yhPd"}, addresses)
+}
+
+func TestScript_Addresses(t *testing.T) {
+	t.Parallel()
+
+	pubKey, err := hex.DecodeString("023717efaec6761e457f55c8417815505b695209d0bbfed8c3265be425b373c2d6")
+	require.NoError(t, err)
+	pubKey2, err := hex.DecodeString("03b8b40a84123121d260f5c109bc5a46ec819c2e4002e5ba08638783bfb4e01435")
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		template     bscript.ScriptTemplate
+		params       *bscript.TemplateParams
+		expAddresses []string
+	}{
+		"p2pk": {
+			template:     bscript.P2PKTemplate{},
+			params:       &bscript.TemplateParams{PublicKeys: [][]byte{pubKey}},
+			expAddresses: []string{"1844ZLvzqZt6oz7qV5vkangma2zAucqFjj"},
+		},
+		"multisig": {
+			template:     bscript.MultiSigTemplate{},
+			params:       &bscript.TemplateParams{RequiredSigs: 1, PublicKeys: [][]byte{pubKey, pubKey2}},
+			expAddresses: []string{"1844ZLvzqZt6oz7qV5vkangma2zAucqFjj", "1JZaumCzrYoM8KRn4SPmUGXKAgx6BTh17U"},
+		},
+		"nulldata": {
+			template:     bscript.NullDataTemplate{},
+			params:       &bscript.TemplateParams{Data: [][]byte{[]byte("hello")}},
+			expAddresses: []string{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := test.template.Lock(test.params)
+			require.NoError(t, err)
+
+			addresses, err := s.Addresses()
+			require.NoError(t, err)
+			assert.Equal(t, test.expAddresses, addresses)
+		})
+	}
+
+	t.Run("p2pk on regtest", func(t *testing.T) {
+		s, err := bscript.P2PKTemplate{}.Lock(&bscript.TemplateParams{PublicKeys: [][]byte{pubKey}})
+		require.NoError(t, err)
+
+		addresses, err := s.Addresses(bscript.Regtest)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"mna1rQ1yebKMb6bTCeu8Qhu6S2asp3As2K"}, addresses)
+	})
+}
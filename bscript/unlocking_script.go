@@ -18,3 +18,16 @@ func NewP2PKHUnlockingScript(pubKey, sig []byte, sigHashFlag sighash.Flag) (*Scr
 
 	return s, err
 }
+
+// NewMultiSigUnlockingScript creates a new unlocking script which spends a
+// bare multisig locking script from signatures, each with its SIGHASH flag
+// appended, in the order of the public keys they are for.
+//
+// The script begins with OP_0 to satisfy the extra item popped by
+// OP_CHECKMULTISIG.
+func NewMultiSigUnlockingScript(sigs [][]byte) (*Script, error) {
+	s := &Script{OpZERO}
+	err := s.AppendPushDataArray(sigs)
+
+	return s, err
+}
@@ -21,3 +21,11 @@ func TestNewP2PKHUnlockingScript(t *testing.T) {
 		assert.Equal(t, "0f736f6d652d7369676e6174757265002102798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66", script.String())
 	})
 }
+
+func TestNewMultiSigUnlockingScript(t *testing.T) {
+	t.Run("unlock script with two signatures", func(t *testing.T) {
+		script, err := NewMultiSigUnlockingScript([][]byte{[]byte("sig-one"), []byte("sig-two")})
+		require.NoError(t, err)
+		assert.Equal(t, "00077369672d6f6e65077369672d74776f", script.String())
+	})
+}
@@ -0,0 +1,185 @@
+// Package chaincfg defines the parameters of the networks bitcoin (BSV) runs on,
+// being the address version bytes, the heights at which upgrades activated and
+// the default policy limits of nodes.
+//
+// The values mirror the chain params of the bitcoin-sv node.
+package chaincfg
+
+import "fmt"
+
+// Network identifies one of the networks bitcoin (BSV) runs on.
+type Network uint8
+
+// Networks with parameters defined by this package.
+const (
+	Mainnet Network = iota
+	Testnet
+	STN
+	Regtest
+)
+
+// BIP276 network ids, see bscript.EncodeBIP276.
+const (
+	BIP276Mainnet = 1
+	BIP276Testnet = 2
+)
+
+// String returns the name of the network.
+func (n Network) String() string {
+	if p := n.Params(); p != nil {
+		return p.Name
+	}
+	return fmt.Sprintf("Unknown Network (%d)", n)
+}
+
+// Params returns the parameters of the network, or nil if it is unknown.
+func (n Network) Params() *Params {
+	switch n {
+	case Mainnet:
+		return &MainNetParams
+	case Testnet:
+		return &TestNetParams
+	case STN:
+		return &STNParams
+	case Regtest:
+		return &RegressionNetParams
+	default:
+		return nil
+	}
+}
+
+// Networks returns all networks with parameters defined by this package.
+func Networks() []Network {
+	return []Network{Mainnet, Testnet, STN, Regtest}
+}
+
+// Params defines a network by its parameters.
+type Params struct {
+	// Name is the human-readable name of the network.
+	Name string
+	// Net is the network the params are for.
+	Net Network
+
+	// PubKeyHashAddrID is the version byte of P2PKH addresses.
+	PubKeyHashAddrID byte
+	// ScriptHashAddrID is the version byte of legacy P2SH addresses.
+	ScriptHashAddrID byte
+	// PrivateKeyID is the version byte of WIF encoded private keys.
+	PrivateKeyID byte
+	// BIP276Network is the network id used by BIP276 encoded scripts.
+	BIP276Network int
+
+	// UAHFHeight is the height of the first block on which SIGHASH_FORKID
+	// is required.
+	UAHFHeight uint32
+	// GenesisHeight is the height of the first block on which the genesis
+	// upgrade rules apply.
+	GenesisHeight uint32
+	// ChronicleHeight is the height of the first block on which the
+	// Chronicle upgrade rules apply.
+	ChronicleHeight uint32
+
+	// Policy are the default policy limits of nodes on the network.
+	Policy PolicyLimits
+}
+
+// PolicyLimits are the default limits nodes apply when deciding whether to
+// relay a tx or accept it into their mempool.
+type PolicyLimits struct {
+	// MaxTxSize is the maximum size of a tx in bytes (maxtxsizepolicy).
+	MaxTxSize int
+	// MaxScriptSize is the maximum size of a script in bytes
+	// (maxscriptsizepolicy).
+	MaxScriptSize int
+	// MaxScriptNumLength is the maximum length in bytes of a number operated
+	// on by a script (maxscriptnumlengthpolicy).
+	MaxScriptNumLength int
+	// MaxStackMemoryUsage is the maximum number of bytes held by the stacks
+	// while executing a script (maxstackmemoryusagepolicy).
+	MaxStackMemoryUsage int
+	// DustLimit is the minimum number of satoshis a non-data output may hold.
+	DustLimit uint64
+}
+
+// IsUAHFActive returns true if SIGHASH_FORKID is required in the block at
+// the given height.
+func (p *Params) IsUAHFActive(height uint32) bool {
+	return height >= p.UAHFHeight
+}
+
+// IsGenesisActive returns true if the genesis upgrade rules apply in the block
+// at the given height.
+func (p *Params) IsGenesisActive(height uint32) bool {
+	return height >= p.GenesisHeight
+}
+
+// IsChronicleActive returns true if the Chronicle upgrade rules apply in the
+// block at the given height.
+func (p *Params) IsChronicleActive(height uint32) bool {
+	return height >= p.ChronicleHeight
+}
+
+// defaultPolicy are the policy limits nodes default to on every network.
+var defaultPolicy = PolicyLimits{
+	MaxTxSize:           10_000_000,
+	MaxScriptSize:       500_000,
+	MaxScriptNumLength:  10_000,
+	MaxStackMemoryUsage: 100_000_000,
+	DustLimit:           1,
+}
+
+// MainNetParams are the parameters of the main network.
+var MainNetParams = Params{
+	Name:             "mainnet",
+	Net:              Mainnet,
+	PubKeyHashAddrID: 0x00,
+	ScriptHashAddrID: 0x05,
+	PrivateKeyID:     0x80,
+	BIP276Network:    BIP276Mainnet,
+	UAHFHeight:       478_558,
+	GenesisHeight:    620_538,
+	ChronicleHeight:  943_816,
+	Policy:           defaultPolicy,
+}
+
+// TestNetParams are the parameters of the test network.
+var TestNetParams = Params{
+	Name:             "testnet",
+	Net:              Testnet,
+	PubKeyHashAddrID: 0x6f,
+	ScriptHashAddrID: 0xc4,
+	PrivateKeyID:     0xef,
+	BIP276Network:    BIP276Testnet,
+	UAHFHeight:       1_155_875,
+	GenesisHeight:    1_344_302,
+	ChronicleHeight:  1_713_168,
+	Policy:           defaultPolicy,
+}
+
+// STNParams are the parameters of the scaling test network.
+var STNParams = Params{
+	Name:             "stn",
+	Net:              STN,
+	PubKeyHashAddrID: 0x6f,
+	ScriptHashAddrID: 0xc4,
+	PrivateKeyID:     0xef,
+	BIP276Network:    BIP276Testnet,
+	UAHFHeight:       15,
+	GenesisHeight:    100,
+	ChronicleHeight:  200,
+	Policy:           defaultPolicy,
+}
+
+// RegressionNetParams are the parameters of the regression test network.
+var RegressionNetParams = Params{
+	Name:             "regtest",
+	Net:              Regtest,
+	PubKeyHashAddrID: 0x6f,
+	ScriptHashAddrID: 0xc4,
+	PrivateKeyID:     0xef,
+	BIP276Network:    BIP276Testnet,
+	UAHFHeight:       0,
+	GenesisHeight:    10_000,
+	ChronicleHeight:  15_000,
+	Policy:           defaultPolicy,
+}
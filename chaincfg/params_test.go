@@ -0,0 +1,45 @@
+package chaincfg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bt/v2/chaincfg"
+)
+
+func TestNetwork_Params(t *testing.T) {
+	t.Parallel()
+
+	for _, net := range chaincfg.Networks() {
+		t.Run(net.String(), func(t *testing.T) {
+			params := net.Params()
+			require.NotNil(t, params)
+			assert.Equal(t, net, params.Net)
+			assert.Equal(t, params.Name, net.String())
+			assert.Less(t, params.UAHFHeight, params.GenesisHeight)
+			assert.Less(t, params.GenesisHeight, params.ChronicleHeight)
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		assert.Nil(t, chaincfg.Network(10).Params())
+		assert.Equal(t, "Unknown Network (10)", chaincfg.Network(10).String())
+	})
+}
+
+func TestParams_ActivationHeights(t *testing.T) {
+	t.Parallel()
+
+	params := &chaincfg.MainNetParams
+
+	assert.False(t, params.IsUAHFActive(478_557))
+	assert.True(t, params.IsUAHFActive(478_558))
+
+	assert.False(t, params.IsGenesisActive(620_537))
+	assert.True(t, params.IsGenesisActive(620_538))
+
+	assert.False(t, params.IsChronicleActive(params.ChronicleHeight-1))
+	assert.True(t, params.IsChronicleActive(params.ChronicleHeight))
+}
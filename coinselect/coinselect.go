@@ -0,0 +1,323 @@
+// Package coinselect selects which utxos to spend in order to fund a tx.
+//
+// Each candidate utxo is valued at its effective value, being its satoshis less
+// the fee to spend it, so the strategies account for the fees of the inputs they
+// add. A selection reports its waste, allowing strategies to be compared:
+//
+//	for _, s := range []coinselect.Strategy{coinselect.BranchAndBound, coinselect.LargestFirst} {
+//	    sel, err := coinselect.Select(tx, fq, utxos, s)
+//	    if err == nil && (best == nil || sel.Waste < best.Waste) {
+//	        best = sel
+//	    }
+//	}
+package coinselect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+)
+
+// Static errors for err113 linter compliance
+var (
+	ErrNoExactMatch      = errors.New("no selection found without change")
+	ErrMaxInputsExceeded = errors.New("target cannot be reached within the maximum number of inputs")
+	ErrInvalidFeeQuote   = errors.New("invalid fee quote")
+)
+
+const (
+	// changeOutputSize is the size of a P2PKH change output.
+	changeOutputSize = 8 + 1 + 25
+	// changeInputSize is the size of an input spending a P2PKH change output.
+	changeInputSize = 32 + 4 + 1 + bt.P2PKHUnlockingScriptLen + 4
+	// emptyTxSize is the size of a tx with no inputs or outputs.
+	emptyTxSize = 4 + 1 + 1 + 4
+)
+
+// Coin is a candidate utxo, valued for selection.
+type Coin struct {
+	UTXO *bt.UTXO
+	// Fee is the fee to spend the utxo at the fee quote.
+	Fee uint64
+	// LongTermFee is the fee to spend the utxo at the long term fee quote.
+	LongTermFee uint64
+	// EffectiveValue is the satoshis of the utxo less Fee.
+	EffectiveValue uint64
+}
+
+// Params are the parameters a Strategy selects coins with.
+type Params struct {
+	// Target is the effective value the selected coins must cover.
+	Target uint64
+	// MaxInputs is the maximum number of coins which may be selected, or 0
+	// for no limit.
+	MaxInputs int
+	// CostOfChange is the fee to create a change output and later spend it.
+	// A selection exceeding the target by more than this would have change.
+	CostOfChange uint64
+	// Rand is the source of randomness for random strategies, or nil to use
+	// the top-level functions of math/rand/v2.
+	Rand *rand.Rand
+}
+
+// Strategy selects coins whose total effective value is at least the target.
+// It returns bt.ErrInsufficientFunds if the coins cannot cover the target, and
+// ErrMaxInputsExceeded if they cannot within the maximum number of inputs.
+type Strategy func(coins []Coin, p Params) ([]Coin, error)
+
+// Selection is the result of selecting coins.
+type Selection struct {
+	// UTXOs are the selected utxos.
+	UTXOs bt.UTXOs
+	// Total is the sum of the satoshis of the selected utxos.
+	Total uint64
+	// Fee is the fee to spend the selected utxos.
+	Fee uint64
+	// Target is the deficit which the selection covers.
+	Target uint64
+	// Excess is the effective value of the selection above the target.
+	Excess uint64
+	// Change is true if the excess is worth returning as change.
+	Change bool
+	// Waste is the cost of the selection compared to an ideal one. It is the
+	// difference between spending the selected utxos now and at the long term
+	// fee rate, plus either the cost of change or the excess given up as fees.
+	// Lower is better, and it can be negative when fees are below the long
+	// term fee rate.
+	Waste int64
+}
+
+// OptionFunc for setting selection options.
+type OptionFunc func(o *options)
+
+type options struct {
+	maxInputs    int
+	longTermFees *bt.FeeQuote
+	rand         *rand.Rand
+}
+
+// WithMaxInputs configure the maximum number of utxos which may be selected.
+func WithMaxInputs(n int) OptionFunc {
+	return func(o *options) {
+		o.maxInputs = n
+	}
+}
+
+// WithLongTermFeeQuote configure the fee quote expected to apply in the long
+// term, used in calculating the waste of a selection. Defaults to the fee quote
+// the selection is made with.
+func WithLongTermFeeQuote(fq *bt.FeeQuote) OptionFunc {
+	return func(o *options) {
+		o.longTermFees = fq
+	}
+}
+
+// WithRand configure the source of randomness for random strategies.
+func WithRand(r *rand.Rand) OptionFunc {
+	return func(o *options) {
+		o.rand = r
+	}
+}
+
+// Select selects utxos using the strategy to cover the estimated deficit of the
+// tx, being the satoshis needed for its outputs and fees, including the fees of
+// the selected inputs. The tx is not modified; add the selection with
+// tx.FromUTXOs.
+func Select(tx *bt.Tx, fq *bt.FeeQuote, utxos bt.UTXOs, s Strategy, opts ...OptionFunc) (*Selection, error) {
+	if err := checkFeeQuote(fq); err != nil {
+		return nil, err
+	}
+
+	deficit, err := tx.EstimateDeficit(fq)
+	if err != nil {
+		return nil, err
+	}
+
+	sel, err := selectCoins(deficit, fq, utxos, s, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	funded := tx.Clone()
+	if err = funded.FromUTXOs(sel.UTXOs...); err != nil {
+		return nil, err
+	}
+	if deficit, err = funded.EstimateDeficit(fq); err != nil {
+		return nil, err
+	}
+	if deficit != 0 {
+		return nil, bt.ErrInsufficientFunds
+	}
+
+	return sel, nil
+}
+
+// Getter returns a bt.UTXOGetterFunc for use with tx.Fund, which selects from
+// the utxos using the strategy. Each utxo is returned at most once, and
+// bt.ErrNoUTXO is returned once the deficit cannot be covered.
+func Getter(fq *bt.FeeQuote, utxos bt.UTXOs, s Strategy, opts ...OptionFunc) bt.UTXOGetterFunc {
+	used := make(map[*bt.UTXO]struct{}, len(utxos))
+
+	return func(_ context.Context, deficit uint64) ([]*bt.UTXO, error) {
+		remaining := make(bt.UTXOs, 0, len(utxos)-len(used))
+		for _, u := range utxos {
+			if _, ok := used[u]; !ok {
+				remaining = append(remaining, u)
+			}
+		}
+
+		sel, err := selectCoins(deficit, fq, remaining, s, opts...)
+		if err != nil {
+			if errors.Is(err, bt.ErrInsufficientFunds) || errors.Is(err, ErrMaxInputsExceeded) {
+				return nil, bt.ErrNoUTXO
+			}
+			return nil, err
+		}
+		if len(sel.UTXOs) == 0 {
+			return nil, bt.ErrNoUTXO
+		}
+
+		for _, u := range sel.UTXOs {
+			used[u] = struct{}{}
+		}
+
+		return sel.UTXOs, nil
+	}
+}
+
+func selectCoins(target uint64, fq *bt.FeeQuote, utxos bt.UTXOs, s Strategy, opts ...OptionFunc) (*Selection, error) {
+	o := &options{longTermFees: fq}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if err := checkFeeQuote(fq); err != nil {
+		return nil, err
+	}
+	if err := checkFeeQuote(o.longTermFees); err != nil {
+		return nil, fmt.Errorf("long term fee quote: %w", err)
+	}
+
+	fee, err := fq.Fee(bt.FeeTypeStandard)
+	if err != nil {
+		return nil, err
+	}
+	longTermFee, err := o.longTermFees.Fee(bt.FeeTypeStandard)
+	if err != nil {
+		return nil, err
+	}
+
+	coins, err := newCoins(utxos, fee, longTermFee)
+	if err != nil {
+		return nil, err
+	}
+
+	p := Params{
+		Target:       target,
+		MaxInputs:    o.maxInputs,
+		CostOfChange: feeFor(changeOutputSize, fee) + feeFor(changeInputSize, longTermFee),
+		Rand:         o.rand,
+	}
+
+	var selected []Coin
+	if target > 0 {
+		var total uint64
+		for _, c := range coins {
+			total += c.EffectiveValue
+		}
+		if total < target {
+			return nil, bt.ErrInsufficientFunds
+		}
+
+		if selected, err = s(coins, p); err != nil {
+			return nil, err
+		}
+	}
+
+	return newSelection(selected, p), nil
+}
+
+// newCoins values the utxos, dropping any which cost more to spend than they
+// are worth.
+func newCoins(utxos bt.UTXOs, fee, longTermFee *bt.Fee) ([]Coin, error) {
+	coins := make([]Coin, 0, len(utxos))
+	for _, u := range utxos {
+		tx := bt.NewTx()
+		if err := tx.FromUTXOs(u); err != nil {
+			return nil, err
+		}
+		size, err := tx.EstimateSize()
+		if err != nil {
+			return nil, err
+		}
+
+		c := Coin{
+			UTXO:        u,
+			Fee:         feeFor(size-emptyTxSize, fee),
+			LongTermFee: feeFor(size-emptyTxSize, longTermFee),
+		}
+		if u.Satoshis <= c.Fee {
+			continue
+		}
+		c.EffectiveValue = u.Satoshis - c.Fee
+
+		coins = append(coins, c)
+	}
+
+	return coins, nil
+}
+
+func newSelection(coins []Coin, p Params) *Selection {
+	sel := &Selection{
+		UTXOs:  make(bt.UTXOs, 0, len(coins)),
+		Target: p.Target,
+	}
+
+	var effectiveValue uint64
+	for _, c := range coins {
+		sel.UTXOs = append(sel.UTXOs, c.UTXO)
+		sel.Total += c.UTXO.Satoshis
+		sel.Fee += c.Fee
+		sel.Waste += int64(c.Fee) - int64(c.LongTermFee)
+		effectiveValue += c.EffectiveValue
+	}
+
+	sel.Excess = effectiveValue - p.Target
+	sel.Change = sel.Excess > p.CostOfChange
+	if sel.Change {
+		sel.Waste += int64(p.CostOfChange)
+	} else {
+		sel.Waste += int64(sel.Excess)
+	}
+
+	return sel
+}
+
+// checkFeeQuote returns ErrInvalidFeeQuote if a mining fee of the fee quote
+// cannot be applied, as a fee for zero bytes would divide by zero.
+func checkFeeQuote(fq *bt.FeeQuote) error {
+	for _, ft := range []bt.FeeType{bt.FeeTypeStandard, bt.FeeTypeData} {
+		fee, err := fq.Fee(ft)
+		if err != nil {
+			return err
+		}
+		if fee.MiningFee.Bytes <= 0 || fee.MiningFee.Satoshis < 0 {
+			return fmt.Errorf("%w: %s mining fee of %d satoshis for %d bytes",
+				ErrInvalidFeeQuote, ft, fee.MiningFee.Satoshis, fee.MiningFee.Bytes)
+		}
+	}
+
+	return nil
+}
+
+// feeFor returns the fee for size bytes, rounded up so that the fees of the
+// inputs of a tx never sum to less than the fee of the tx.
+func feeFor(size int, fee *bt.Fee) uint64 {
+	sats := uint64(fee.MiningFee.Satoshis)
+	bytes := uint64(fee.MiningFee.Bytes)
+
+	return (uint64(size)*sats + bytes - 1) / bytes
+}
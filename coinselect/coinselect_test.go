@@ -0,0 +1,334 @@
+package coinselect_test
+
+import (
+	"context"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/go-bt/v2/coinselect"
+)
+
+const (
+	p2pkhScript = "76a91455b61be43392125d127f1780fb038437cd67ef9c88ac"
+	address     = "mtestD3vRB7AoYWK2n6kLdZmAMLbLhDsLr"
+
+	// inputFee is the fee to spend a P2PKH utxo at 1 sat/byte.
+	inputFee = 148
+	// target is the deficit of the tx returned by newTx at 1 sat/byte, being
+	// its 10000 satoshi output and 44 bytes.
+	target = 10044
+	// costOfChange is the fee to create and spend a P2PKH output at 1 sat/byte.
+	costOfChange = 34 + 148
+)
+
+func feeQuote(sats, bytes int) *bt.FeeQuote {
+	fq := bt.NewFeeQuote()
+	for _, ft := range []bt.FeeType{bt.FeeTypeStandard, bt.FeeTypeData} {
+		fq.AddQuote(ft, &bt.Fee{
+			FeeType:   ft,
+			MiningFee: bt.FeeUnit{Satoshis: sats, Bytes: bytes},
+			RelayFee:  bt.FeeUnit{Satoshis: sats, Bytes: bytes},
+		})
+	}
+
+	return fq
+}
+
+func newTx(t *testing.T) *bt.Tx {
+	t.Helper()
+
+	tx := bt.NewTx()
+	require.NoError(t, tx.AddP2PKHOutputFromAddress(address, 10000))
+
+	return tx
+}
+
+// newUTXOs returns a P2PKH utxo for each amount, with an effective value of
+// the amount less inputFee at 1 sat/byte.
+func newUTXOs(t *testing.T, amounts ...uint64) bt.UTXOs {
+	t.Helper()
+
+	s, err := bscript.NewFromHexString(p2pkhScript)
+	require.NoError(t, err)
+
+	utxos := make(bt.UTXOs, len(amounts))
+	for i, amount := range amounts {
+		utxos[i] = &bt.UTXO{
+			TxIDHash:      &chainhash.Hash{byte(i + 1)},
+			Vout:          uint32(i),
+			LockingScript: s,
+			Satoshis:      amount,
+		}
+	}
+
+	return utxos
+}
+
+func satoshis(utxos bt.UTXOs) []uint64 {
+	sats := make([]uint64, len(utxos))
+	for i, u := range utxos {
+		sats[i] = u.Satoshis
+	}
+
+	return sats
+}
+
+func TestSelect(t *testing.T) {
+	t.Parallel()
+
+	fq := feeQuote(1, 1)
+
+	tests := map[string]struct {
+		utxos    bt.UTXOs
+		strategy coinselect.Strategy
+		opts     []coinselect.OptionFunc
+		expSats  []uint64
+		expErr   error
+	}{
+		"largest first selects the largest utxo": {
+			utxos:    newUTXOs(t, 5000+inputFee, 20000+inputFee, 10000+inputFee),
+			strategy: coinselect.LargestFirst,
+			expSats:  []uint64{20000 + inputFee},
+		},
+		"largest first within max inputs": {
+			utxos:    newUTXOs(t, 5000+inputFee, 6000+inputFee),
+			strategy: coinselect.LargestFirst,
+			opts:     []coinselect.OptionFunc{coinselect.WithMaxInputs(1)},
+			expErr:   coinselect.ErrMaxInputsExceeded,
+		},
+		"smallest first selects the smallest utxos": {
+			utxos:    newUTXOs(t, 20000+inputFee, 4000+inputFee, 3000+inputFee, 5000+inputFee),
+			strategy: coinselect.SmallestFirst,
+			expSats:  []uint64{3000 + inputFee, 4000 + inputFee, 5000 + inputFee},
+		},
+		"smallest first within max inputs selects the smallest run": {
+			utxos:    newUTXOs(t, 20000+inputFee, 4000+inputFee, 3000+inputFee, 5000+inputFee),
+			strategy: coinselect.SmallestFirst,
+			opts:     []coinselect.OptionFunc{coinselect.WithMaxInputs(2)},
+			expSats:  []uint64{5000 + inputFee, 20000 + inputFee},
+		},
+		"smallest first cannot reach target within max inputs": {
+			utxos:    newUTXOs(t, 4000+inputFee, 3000+inputFee, 5000+inputFee),
+			strategy: coinselect.SmallestFirst,
+			opts:     []coinselect.OptionFunc{coinselect.WithMaxInputs(2)},
+			expErr:   coinselect.ErrMaxInputsExceeded,
+		},
+		"uneconomical utxos are not selected": {
+			utxos:    newUTXOs(t, 100, 10044+inputFee, inputFee),
+			strategy: coinselect.SmallestFirst,
+			expSats:  []uint64{10044 + inputFee},
+		},
+		"branch and bound finds exact match": {
+			utxos:    newUTXOs(t, 4000+inputFee, 6044+inputFee, 20000+inputFee, 7000+inputFee),
+			strategy: coinselect.BranchAndBound,
+			expSats:  []uint64{6044 + inputFee, 4000 + inputFee},
+		},
+		"branch and bound finds match within cost of change": {
+			utxos:    newUTXOs(t, 3000+inputFee, 7100+inputFee, 20000+inputFee),
+			strategy: coinselect.BranchAndBound,
+			expSats:  []uint64{7100 + inputFee, 3000 + inputFee},
+		},
+		"branch and bound respects max inputs": {
+			utxos:    newUTXOs(t, 4000+inputFee, 3000+inputFee, 3044+inputFee),
+			strategy: coinselect.BranchAndBound,
+			opts:     []coinselect.OptionFunc{coinselect.WithMaxInputs(2)},
+			expErr:   coinselect.ErrNoExactMatch,
+		},
+		"branch and bound without exact match": {
+			utxos:    newUTXOs(t, 20000+inputFee, 30000+inputFee),
+			strategy: coinselect.BranchAndBound,
+			expErr:   coinselect.ErrNoExactMatch,
+		},
+		"insufficient funds": {
+			utxos:    newUTXOs(t, 5000+inputFee, 5000+inputFee),
+			strategy: coinselect.LargestFirst,
+			expErr:   bt.ErrInsufficientFunds,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := newTx(t)
+			sel, err := coinselect.Select(tx, fq, test.utxos, test.strategy, test.opts...)
+			if test.expErr != nil {
+				require.ErrorIs(t, err, test.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expSats, satoshis(sel.UTXOs))
+			assert.Equal(t, uint64(target), sel.Target)
+			assert.Equal(t, uint64(len(sel.UTXOs)*inputFee), sel.Fee)
+
+			require.NoError(t, tx.FromUTXOs(sel.UTXOs...))
+			deficit, err := tx.EstimateDeficit(fq)
+			require.NoError(t, err)
+			assert.Zero(t, deficit)
+		})
+	}
+}
+
+func TestSelect_InvalidFeeQuote(t *testing.T) {
+	t.Parallel()
+
+	utxos := newUTXOs(t, 20000+inputFee)
+
+	t.Run("zero bytes", func(t *testing.T) {
+		_, err := coinselect.Select(newTx(t), feeQuote(1, 0), utxos, coinselect.LargestFirst)
+		require.ErrorIs(t, err, coinselect.ErrInvalidFeeQuote)
+	})
+
+	t.Run("negative satoshis", func(t *testing.T) {
+		_, err := coinselect.Select(newTx(t), feeQuote(-1, 1), utxos, coinselect.LargestFirst)
+		require.ErrorIs(t, err, coinselect.ErrInvalidFeeQuote)
+	})
+
+	t.Run("long term fee quote", func(t *testing.T) {
+		_, err := coinselect.Select(newTx(t), feeQuote(1, 1), utxos, coinselect.LargestFirst,
+			coinselect.WithLongTermFeeQuote(feeQuote(1, 0)))
+		require.ErrorIs(t, err, coinselect.ErrInvalidFeeQuote)
+	})
+
+	t.Run("getter", func(t *testing.T) {
+		_, err := coinselect.Getter(feeQuote(1, 0), utxos, coinselect.LargestFirst)(context.Background(), target)
+		require.ErrorIs(t, err, coinselect.ErrInvalidFeeQuote)
+	})
+}
+
+func TestSelect_Waste(t *testing.T) {
+	t.Parallel()
+
+	fq := feeQuote(1, 1)
+
+	t.Run("selection with change wastes the cost of change", func(t *testing.T) {
+		sel, err := coinselect.Select(newTx(t), fq, newUTXOs(t, 20000+inputFee), coinselect.LargestFirst)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(20000+inputFee), sel.Total)
+		assert.Equal(t, uint64(20000-target), sel.Excess)
+		assert.True(t, sel.Change)
+		assert.Equal(t, int64(costOfChange), sel.Waste)
+	})
+
+	t.Run("selection without change wastes the excess", func(t *testing.T) {
+		sel, err := coinselect.Select(newTx(t), fq, newUTXOs(t, target+100+inputFee), coinselect.LargestFirst)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(100), sel.Excess)
+		assert.False(t, sel.Change)
+		assert.Equal(t, int64(100), sel.Waste)
+	})
+
+	t.Run("exact match has no waste", func(t *testing.T) {
+		sel, err := coinselect.Select(newTx(t), fq, newUTXOs(t, 4000+inputFee, 6044+inputFee), coinselect.BranchAndBound)
+		require.NoError(t, err)
+		assert.Zero(t, sel.Excess)
+		assert.False(t, sel.Change)
+		assert.Zero(t, sel.Waste)
+	})
+
+	t.Run("spending above the long term fee rate adds waste", func(t *testing.T) {
+		sel, err := coinselect.Select(
+			newTx(t), fq, newUTXOs(t, 4000+inputFee, 6044+inputFee), coinselect.BranchAndBound,
+			coinselect.WithLongTermFeeQuote(feeQuote(1, 2)),
+		)
+		require.NoError(t, err)
+		// Two inputs each costing half as much in the long term.
+		assert.Equal(t, int64(inputFee), sel.Waste)
+	})
+
+	t.Run("spending below the long term fee rate has negative waste", func(t *testing.T) {
+		sel, err := coinselect.Select(
+			newTx(t), fq, newUTXOs(t, 4000+inputFee, 6044+inputFee), coinselect.BranchAndBound,
+			coinselect.WithLongTermFeeQuote(feeQuote(2, 1)),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, int64(-2*inputFee), sel.Waste)
+	})
+
+	t.Run("funded tx selects nothing", func(t *testing.T) {
+		tx := newTx(t)
+		require.NoError(t, tx.FromUTXOs(newUTXOs(t, 20000)...))
+
+		sel, err := coinselect.Select(tx, fq, newUTXOs(t, 5000), coinselect.LargestFirst)
+		require.NoError(t, err)
+		assert.Empty(t, sel.UTXOs)
+		assert.Zero(t, sel.Target)
+	})
+}
+
+func TestRandomImprove(t *testing.T) {
+	t.Parallel()
+
+	fq := feeQuote(1, 1)
+
+	t.Run("covers the target", func(t *testing.T) {
+		amounts := make([]uint64, 50)
+		for i := range amounts {
+			amounts[i] = uint64(1000*(i+1)) + inputFee
+		}
+
+		for seed := uint64(0); seed < 20; seed++ {
+			sel, err := coinselect.Select(
+				newTx(t), fq, newUTXOs(t, amounts...), coinselect.RandomImprove,
+				coinselect.WithRand(rand.New(rand.NewPCG(seed, seed))),
+			)
+			require.NoError(t, err)
+
+			assert.GreaterOrEqual(t, sel.Total-sel.Fee, uint64(target))
+		}
+	})
+
+	t.Run("is deterministic for a seeded source", func(t *testing.T) {
+		utxos := newUTXOs(t, 3000, 4000, 5000, 6000, 7000, 8000, 9000)
+
+		a, err := coinselect.Select(newTx(t), fq, utxos, coinselect.RandomImprove,
+			coinselect.WithRand(rand.New(rand.NewPCG(1, 2))))
+		require.NoError(t, err)
+		b, err := coinselect.Select(newTx(t), fq, utxos, coinselect.RandomImprove,
+			coinselect.WithRand(rand.New(rand.NewPCG(1, 2))))
+		require.NoError(t, err)
+
+		assert.Equal(t, a.UTXOs, b.UTXOs)
+	})
+
+	t.Run("falls back to largest first within max inputs", func(t *testing.T) {
+		sel, err := coinselect.Select(
+			newTx(t), fq, newUTXOs(t, 1000, 1000, 1000, 1000, 20000+inputFee), coinselect.RandomImprove,
+			coinselect.WithMaxInputs(1),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{20000 + inputFee}, satoshis(sel.UTXOs))
+	})
+}
+
+func TestGetter(t *testing.T) {
+	t.Parallel()
+
+	fq := feeQuote(1, 1)
+
+	t.Run("funds tx", func(t *testing.T) {
+		tx := newTx(t)
+		utxos := newUTXOs(t, 5000+inputFee, 20000+inputFee, 10000+inputFee)
+
+		require.NoError(t, tx.Fund(context.Background(), fq, coinselect.Getter(fq, utxos, coinselect.LargestFirst)))
+		require.Len(t, tx.Inputs, 1)
+		assert.Equal(t, uint64(20000+inputFee), tx.Inputs[0].PreviousTxSatoshis)
+
+		require.NoError(t, tx.ChangeToAddress(address, fq))
+		ok, err := tx.EstimateIsFeePaidEnough(fq)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("insufficient funds", func(t *testing.T) {
+		tx := newTx(t)
+		utxos := newUTXOs(t, 5000+inputFee, 5000+inputFee)
+
+		err := tx.Fund(context.Background(), fq, coinselect.Getter(fq, utxos, coinselect.LargestFirst))
+		require.ErrorIs(t, err, bt.ErrInsufficientFunds)
+	})
+}
@@ -0,0 +1,208 @@
+package coinselect
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+)
+
+// bnbMaxTries is the maximum number of branches BranchAndBound explores.
+const bnbMaxTries = 100_000
+
+// LargestFirst selects the coins with the largest effective value first,
+// minimising the number of inputs.
+func LargestFirst(coins []Coin, p Params) ([]Coin, error) {
+	return accumulate(sortedCoins(coins, descending), p)
+}
+
+// SmallestFirst selects the coins with the smallest effective value first,
+// consolidating small utxos. If the target cannot be reached within the
+// maximum number of inputs, the smallest run of coins which can is selected.
+func SmallestFirst(coins []Coin, p Params) ([]Coin, error) {
+	sorted := sortedCoins(coins, ascending)
+
+	selected, err := accumulate(sorted, p)
+	if !errors.Is(err, ErrMaxInputsExceeded) {
+		return selected, err
+	}
+
+	var total uint64
+	for i, c := range sorted {
+		total += c.EffectiveValue
+		if i >= p.MaxInputs {
+			total -= sorted[i-p.MaxInputs].EffectiveValue
+		}
+		if i >= p.MaxInputs-1 && total >= p.Target {
+			return sorted[i-p.MaxInputs+1 : i+1], nil
+		}
+	}
+
+	return nil, ErrMaxInputsExceeded
+}
+
+// BranchAndBound searches for a selection which covers the target without
+// exceeding it by more than the cost of change, so that no change output is
+// needed. Of the selections found, the one with the least waste is returned.
+// ErrNoExactMatch is returned if there is none, in which case another strategy
+// should be used.
+func BranchAndBound(coins []Coin, p Params) ([]Coin, error) {
+	sorted := sortedCoins(coins, descending)
+
+	// remaining[i] is the total effective value of sorted[i:].
+	remaining := make([]uint64, len(sorted)+1)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		remaining[i] = remaining[i+1] + sorted[i].EffectiveValue
+	}
+
+	var (
+		tries     int
+		selected  = make([]bool, len(sorted))
+		best      []bool
+		bestWaste = int64(math.MaxInt64)
+	)
+
+	var search func(i int, total uint64, waste int64, count int)
+	search = func(i int, total uint64, waste int64, count int) {
+		if tries >= bnbMaxTries {
+			return
+		}
+		tries++
+
+		if total > p.Target+p.CostOfChange {
+			return
+		}
+		if total >= p.Target {
+			if w := waste + int64(total-p.Target); w <= bestWaste {
+				bestWaste = w
+				best = slices.Clone(selected)
+			}
+			return
+		}
+		if i == len(sorted) || total+remaining[i] < p.Target {
+			return
+		}
+		if p.MaxInputs > 0 && count == p.MaxInputs {
+			return
+		}
+
+		c := sorted[i]
+		// While fees are above the long term fees, each extra input only adds
+		// waste, so there is no improving on the best selection by adding more.
+		if waste > bestWaste && c.Fee > c.LongTermFee {
+			return
+		}
+
+		selected[i] = true
+		search(i+1, total+c.EffectiveValue, waste+int64(c.Fee)-int64(c.LongTermFee), count+1)
+		selected[i] = false
+
+		// Omitting a coin makes omitting an identical one next redundant, as
+		// including it was already explored with the first.
+		next := i + 1
+		for next < len(sorted) && sorted[next].EffectiveValue == c.EffectiveValue && sorted[next].Fee == c.Fee {
+			next++
+		}
+		search(next, total, waste, count)
+	}
+	search(0, 0, 0, 0)
+
+	if best == nil {
+		return nil, ErrNoExactMatch
+	}
+
+	result := make([]Coin, 0, len(sorted))
+	for i, ok := range best {
+		if ok {
+			result = append(result, sorted[i])
+		}
+	}
+
+	return result, nil
+}
+
+// RandomImprove selects coins at random until the target is covered, then keeps
+// adding random coins while they bring the total closer to twice the target,
+// without exceeding three times it. This leaves change of a similar size to the
+// payment, which keeps the utxo set healthy for future payments.
+//
+// If the target cannot be reached at random within the maximum number of
+// inputs, it falls back to LargestFirst.
+func RandomImprove(coins []Coin, p Params) ([]Coin, error) {
+	shuffled := slices.Clone(coins)
+	if p.Rand != nil {
+		p.Rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	} else {
+		rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	}
+
+	selected, err := accumulate(shuffled, p)
+	if errors.Is(err, ErrMaxInputsExceeded) {
+		return LargestFirst(coins, p)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var total uint64
+	for _, c := range selected {
+		total += c.EffectiveValue
+	}
+
+	ideal, upper := 2*p.Target, 3*p.Target
+	for _, c := range shuffled[len(selected):] {
+		if p.MaxInputs > 0 && len(selected) == p.MaxInputs {
+			break
+		}
+		next := total + c.EffectiveValue
+		if next > upper || distance(next, ideal) >= distance(total, ideal) {
+			break
+		}
+		selected = append(selected, c)
+		total = next
+	}
+
+	return selected, nil
+}
+
+// accumulate selects the coins in order until the target is covered.
+func accumulate(coins []Coin, p Params) ([]Coin, error) {
+	var total uint64
+	for i, c := range coins {
+		if p.MaxInputs > 0 && i == p.MaxInputs {
+			return nil, ErrMaxInputsExceeded
+		}
+		total += c.EffectiveValue
+		if total >= p.Target {
+			return coins[: i+1 : i+1], nil
+		}
+	}
+
+	return nil, bt.ErrInsufficientFunds
+}
+
+func sortedCoins(coins []Coin, compare func(a, b Coin) int) []Coin {
+	sorted := slices.Clone(coins)
+	slices.SortStableFunc(sorted, compare)
+
+	return sorted
+}
+
+func ascending(a, b Coin) int {
+	return cmp.Compare(a.EffectiveValue, b.EffectiveValue)
+}
+
+func descending(a, b Coin) int {
+	return cmp.Compare(b.EffectiveValue, a.EffectiveValue)
+}
+
+func distance(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+
+	return b - a
+}
@@ -9,7 +9,7 @@ var (
 	ErrTxTooShort        = errors.New("too short to be a tx - even an empty tx has 10 bytes")
 	ErrNLockTimeLength   = errors.New("nLockTime length must be 4 bytes long")
 	ErrEmptyValues       = errors.New("empty value or values passed, all arguments are required and cannot be empty")
-	ErrUnsupportedScript = errors.New("non-P2PKH input used in the tx - unsupported")
+	ErrUnsupportedScript = errors.New("no unlocking script estimator registered for locking script")
 	ErrInvalidScriptType = errors.New("invalid script type")
 	ErrNoUnlocker        = errors.New("unlocker not supplied")
 )
@@ -43,6 +43,7 @@ var (
 var (
 	ErrEmptyPreviousTxID     = errors.New("'PreviousTxID' not supplied")
 	ErrEmptyPreviousTxScript = errors.New("'PreviousTxScript' not supplied")
+	ErrSigHashMidstate       = errors.New("sha256 midstate cannot be marshaled")
 )
 
 // Sentinel errors reported by the fees.
@@ -69,6 +70,41 @@ var (
 	ErrOutputsNotEmpty = errors.New("transaction outputs must be empty to avoid messing with Ordinal ordering scheme")
 )
 
+// Sentinel errors reported by BEEF.
+var (
+	ErrBeefVersion         = errors.New("invalid BEEF version")
+	ErrBeefNoTxs           = errors.New("BEEF contains no transactions")
+	ErrBeefNoTx            = errors.New("BEEF has no tx")
+	ErrBeefInvalidBUMPFlag = errors.New("invalid BEEF BUMP flag")
+	ErrBeefBUMPIndex       = errors.New("BEEF BUMP index out of range")
+	ErrBeefBUMPMismatch    = errors.New("BEEF BUMP does not contain the tx")
+	ErrBeefMissingParent   = errors.New("BEEF does not contain parent tx")
+	ErrBeefTrailingBytes   = errors.New("trailing bytes after BEEF")
+)
+
+// Sentinel errors reported by merkle paths.
+var (
+	ErrMerklePathBlockHeight   = errors.New("merkle path block height out of range")
+	ErrMerklePathTreeHeight    = errors.New("merkle path tree height must be between 1 and 64")
+	ErrMerklePathTooManyLeaves = errors.New("merkle path level has too many leaves")
+	ErrMerklePathInvalidFlags  = errors.New("invalid merkle path leaf flags")
+	ErrMerklePathTrailingBytes = errors.New("trailing bytes after merkle path")
+	ErrMerklePathEmpty         = errors.New("merkle path is empty")
+	ErrMerklePathNoTxID        = errors.New("merkle path has no txid leaf")
+	ErrMerklePathTxIDNotFound  = errors.New("txid not found in merkle path")
+	ErrMerklePathMissingLeaf   = errors.New("merkle path is missing a leaf")
+
+	ErrMerklePathBlockHeightMismatch = errors.New("merkle paths have different block heights")
+	ErrMerklePathTreeHeightMismatch  = errors.New("merkle paths have different tree heights")
+	ErrMerklePathRootMismatch        = errors.New("merkle paths have different merkle roots")
+)
+
+// Sentinel errors reported by blocks.
+var (
+	ErrBlockHeaderLength  = errors.New("block header must be 80 bytes long")
+	ErrBlockTrailingBytes = errors.New("trailing bytes after block")
+)
+
 // Sentinel errors reported by PSBTs.
 var (
 	ErrDummyInput            = errors.New("failed to add dummy input 0")
@@ -80,3 +116,18 @@ var (
 	ErrInsufficientFees      = errors.New("fee paid not enough with new locking script")
 	ErrUnlockerNotFound      = errors.New("UTXO unlocker not found")
 )
+
+// Sentinel errors reported by partially signed txs.
+var (
+	ErrPartialTxMagic          = errors.New("invalid partially signed tx magic")
+	ErrPartialTxNoTx           = errors.New("partially signed tx contains no tx")
+	ErrPartialTxSigned         = errors.New("tx of partially signed tx has unlocking scripts")
+	ErrPartialTxDuplicateKey   = errors.New("duplicate key in partially signed tx")
+	ErrPartialTxInvalidKey     = errors.New("invalid key in partially signed tx")
+	ErrPartialTxInvalidValue   = errors.New("invalid value in partially signed tx")
+	ErrPartialTxMapCount       = errors.New("partially signed tx metadata does not match the tx")
+	ErrPartialTxMismatch       = errors.New("partially signed txs are for different txs")
+	ErrPartialTxCannotFinalize = errors.New("input of partially signed tx cannot be finalized")
+	ErrPartialTxNotFinalized   = errors.New("input of partially signed tx is not finalized")
+	ErrPartialTxTrailingBytes  = errors.New("trailing bytes after partially signed tx")
+)
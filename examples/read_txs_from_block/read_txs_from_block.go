@@ -3,7 +3,6 @@ package main
 
 import (
 	"bufio"
-	"io"
 	"log"
 
 	"github.com/bsv-blockchain/go-bt/v2"
@@ -29,18 +28,20 @@ func main() {
 	// Create buffered reader for this file.
 	r := bufio.NewReader(f)
 
-	// Read file header. This step is specific to file reading and
-	// may need omitted or modified for other implementations.
-	_, err = io.ReadFull(f, make([]byte, 80))
-	if err != nil {
+	// Read the block header, which precedes the txs.
+	header := &bt.BlockHeader{}
+	if _, err = header.ReadFrom(r); err != nil {
 		panic(err)
 	}
+	log.Println("block", header.Hash())
 
-	txs := bt.Txs{}
-	if _, err = txs.ReadFrom(r); err != nil {
-		panic(err)
+	// Decode the txs one at a time, reusing the same arena for the scripts of
+	// each tx, so that memory use is bounded by the largest tx in the block.
+	txr := bt.NewTxReaderWithArena(r, bt.NewArena(1024))
+	for txr.Next() {
+		log.Println(txr.Offset()+bt.BlockHeaderLen, txr.Tx().TxID())
 	}
-	for _, tx := range txs {
-		log.Println(tx.TxID())
+	if err = txr.Err(); err != nil {
+		panic(err)
 	}
 }
@@ -0,0 +1,445 @@
+package bt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+)
+
+/*
+General format of a BSV Unified Merkle Path (BUMP), see BRC-74
+--------------------------------------------------------------
+Field            Description                                                               Size
+
+block height     height of the block the path was taken from                               1 - 9 bytes VI = VarInt
+
+tree height      number of levels in the merkle tree                                       1 byte
+
+for each level:
+  nLeaves        number of leaves provided at this level                                   1 - 9 bytes VI = VarInt
+  for each leaf:
+    offset       position of the leaf within the level                                     1 - 9 bytes VI = VarInt
+    flags        0x00 hash follows, 0x01 duplicate of its sibling (no hash),               1 byte
+                 0x02 hash follows and is a txid of interest
+    hash         the leaf hash, omitted when the duplicate flag is set                     32 bytes
+--------------------------------------------------------------
+*/
+
+// Merkle path leaf flags.
+const (
+	merklePathFlagData      byte = 0x00
+	merklePathFlagDuplicate byte = 0x01
+	merklePathFlagTxID      byte = 0x02
+)
+
+// PathElement is a single leaf of a MerklePath level.
+type PathElement struct {
+	// Offset is the position of the leaf within its level of the tree.
+	Offset uint64 `json:"offset"`
+	// Hash is the hash of the leaf. It is nil when Duplicate is set.
+	Hash *chainhash.Hash `json:"hash,omitempty"`
+	// TxID marks the leaf as a transaction id that the path proves.
+	TxID bool `json:"txid,omitempty"`
+	// Duplicate marks the leaf as a duplicate of its sibling, as happens
+	// on the right-hand edge of a level with an odd number of nodes.
+	Duplicate bool `json:"duplicate,omitempty"`
+}
+
+// MerklePath is a BSV Unified Merkle Path (BUMP, BRC-74) proving the inclusion
+// of one or more transactions in a block.
+//
+// Path[0] holds the txids and their siblings, and each following level holds
+// the siblings required to compute the level above it.
+//
+// The JSON encoding follows BRC-74, with hashes hex encoded in the same
+// (reversed) byte order as txids.
+type MerklePath struct {
+	BlockHeight uint32           `json:"blockHeight"`
+	Path        [][]*PathElement `json:"path"`
+}
+
+// NewMerklePathFromBytes decodes a BRC-74 binary encoded merkle path.
+func NewMerklePathFromBytes(b []byte) (*MerklePath, error) {
+	mp := &MerklePath{}
+	n, err := mp.ReadFrom(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	if int(n) != len(b) {
+		return nil, errors.Wrapf(ErrMerklePathTrailingBytes, "read %d of %d bytes", n, len(b))
+	}
+
+	return mp, nil
+}
+
+// NewMerklePathFromString decodes a hex string of a BRC-74 binary encoded merkle path.
+func NewMerklePathFromString(str string) (*MerklePath, error) {
+	b, err := hex.DecodeString(str)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMerklePathFromBytes(b)
+}
+
+// ReadFrom reads a BRC-74 binary encoded merkle path from the `io.Reader` into the `bt.MerklePath`.
+func (mp *MerklePath) ReadFrom(r io.Reader) (int64, error) {
+	*mp = MerklePath{}
+	var bytesRead int64
+
+	var blockHeight VarInt
+	n64, err := blockHeight.ReadFrom(r)
+	bytesRead += n64
+	if err != nil {
+		return bytesRead, err
+	}
+	if uint64(blockHeight) > 0xffffffff {
+		return bytesRead, errors.Wrapf(ErrMerklePathBlockHeight, "got %d", blockHeight)
+	}
+	mp.BlockHeight = uint32(blockHeight)
+
+	var b [1]byte
+	n, err := io.ReadFull(r, b[:])
+	bytesRead += int64(n)
+	if err != nil {
+		return bytesRead, errors.Wrapf(err, "treeHeight(1): got %d bytes", n)
+	}
+	treeHeight := int(b[0])
+	if treeHeight == 0 || treeHeight > 64 {
+		return bytesRead, errors.Wrapf(ErrMerklePathTreeHeight, "got %d", treeHeight)
+	}
+
+	mp.Path = make([][]*PathElement, treeHeight)
+	for level := 0; level < treeHeight; level++ {
+		var nLeaves VarInt
+		n64, err = nLeaves.ReadFrom(r)
+		bytesRead += n64
+		if err != nil {
+			return bytesRead, err
+		}
+
+		// Each leaf takes at least two bytes (offset + flags), so a count
+		// claiming more leaves than that is rejected before allocating.
+		if uint64(nLeaves) > uint64(MaxArenaAlloc)/2 {
+			return bytesRead, errors.Wrapf(ErrMerklePathTooManyLeaves, "level %d has %d leaves", level, nLeaves)
+		}
+
+		leaves := make([]*PathElement, 0, min(uint64(nLeaves), 1024))
+		for i := uint64(0); i < uint64(nLeaves); i++ {
+			leaf := &PathElement{}
+
+			var offset VarInt
+			n64, err = offset.ReadFrom(r)
+			bytesRead += n64
+			if err != nil {
+				return bytesRead, err
+			}
+			leaf.Offset = uint64(offset)
+
+			n, err = io.ReadFull(r, b[:])
+			bytesRead += int64(n)
+			if err != nil {
+				return bytesRead, errors.Wrapf(err, "flags(1): got %d bytes", n)
+			}
+
+			switch b[0] {
+			case merklePathFlagDuplicate:
+				leaf.Duplicate = true
+				leaves = append(leaves, leaf)
+				continue
+			case merklePathFlagTxID:
+				leaf.TxID = true
+			case merklePathFlagData:
+			default:
+				return bytesRead, errors.Wrapf(ErrMerklePathInvalidFlags, "got %#x", b[0])
+			}
+
+			var hash chainhash.Hash
+			n, err = io.ReadFull(r, hash[:])
+			bytesRead += int64(n)
+			if err != nil {
+				return bytesRead, errors.Wrapf(err, "hash(32): got %d bytes", n)
+			}
+			leaf.Hash = &hash
+
+			leaves = append(leaves, leaf)
+		}
+
+		mp.Path[level] = leaves
+	}
+
+	return bytesRead, nil
+}
+
+// Bytes encodes the merkle path into the BRC-74 binary format.
+func (mp *MerklePath) Bytes() []byte {
+	h := VarInt(uint64(mp.BlockHeight)).AppendTo(make([]byte, 0, mp.size()))
+	h = append(h, byte(len(mp.Path)))
+
+	for _, level := range mp.Path {
+		h = VarInt(uint64(len(level))).AppendTo(h)
+		for _, leaf := range level {
+			h = VarInt(leaf.Offset).AppendTo(h)
+
+			switch {
+			case leaf.Duplicate:
+				h = append(h, merklePathFlagDuplicate)
+				continue
+			case leaf.TxID:
+				h = append(h, merklePathFlagTxID)
+			default:
+				h = append(h, merklePathFlagData)
+			}
+
+			if leaf.Hash != nil {
+				h = append(h, leaf.Hash[:]...)
+			} else {
+				h = append(h, make([]byte, chainhash.HashSize)...)
+			}
+		}
+	}
+
+	return h
+}
+
+// WriteTo writes the BRC-74 binary encoded merkle path to w.
+func (mp *MerklePath) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(mp.Bytes())
+	return int64(n), err
+}
+
+// String encodes the merkle path into a BRC-74 hex string.
+func (mp *MerklePath) String() string {
+	return hex.EncodeToString(mp.Bytes())
+}
+
+// size returns the length of the BRC-74 binary encoding of the merkle path.
+func (mp *MerklePath) size() int {
+	size := VarInt(uint64(mp.BlockHeight)).Length() + 1
+	for _, level := range mp.Path {
+		size += VarInt(uint64(len(level))).Length()
+		for _, leaf := range level {
+			size += VarInt(leaf.Offset).Length() + 1
+			if !leaf.Duplicate {
+				size += chainhash.HashSize
+			}
+		}
+	}
+
+	return size
+}
+
+// ContainsTxID returns true if the txid is one of the leaves at the
+// lowest level of the merkle path.
+func (mp *MerklePath) ContainsTxID(txID *chainhash.Hash) bool {
+	if txID == nil || len(mp.Path) == 0 {
+		return false
+	}
+
+	for _, leaf := range mp.Path[0] {
+		if leaf.Hash != nil && leaf.Hash.IsEqual(txID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ComputeRoot computes the merkle root of the block the path was taken from,
+// starting at the txid.
+//
+// If txID is nil, the first txid flagged leaf of the path is used.
+func (mp *MerklePath) ComputeRoot(txID *chainhash.Hash) (*chainhash.Hash, error) {
+	if len(mp.Path) == 0 {
+		return nil, ErrMerklePathEmpty
+	}
+
+	if txID == nil {
+		txID = mp.firstTxID()
+		if txID == nil {
+			return nil, ErrMerklePathNoTxID
+		}
+	}
+
+	var offset uint64
+	found := false
+	for _, leaf := range mp.Path[0] {
+		if leaf.Hash != nil && leaf.Hash.IsEqual(txID) {
+			offset, found = leaf.Offset, true
+			break
+		}
+	}
+	if !found {
+		return nil, errors.Wrapf(ErrMerklePathTxIDNotFound, "txid %s", txID)
+	}
+
+	// A block holding a single tx has the txid as its merkle root.
+	if len(mp.Path) == 1 && len(mp.Path[0]) == 1 {
+		root := *txID
+		return &root, nil
+	}
+
+	index := mp.index()
+	working := *txID
+	for height := range mp.Path {
+		sibling := mp.findOrComputeLeaf(index, height, offset^1)
+		if sibling == nil {
+			return nil, errors.Wrapf(ErrMerklePathMissingLeaf, "level %d offset %d", height, offset^1)
+		}
+
+		switch {
+		case sibling.Duplicate:
+			working = merkleTreeParent(&working, &working)
+		case offset%2 == 1:
+			working = merkleTreeParent(sibling.Hash, &working)
+		default:
+			working = merkleTreeParent(&working, sibling.Hash)
+		}
+		offset >>= 1
+	}
+
+	return &working, nil
+}
+
+// Verify computes the merkle root from the txid and returns true if it
+// matches the merkle root of the block header.
+//
+// An error is returned if the root could not be computed, for example when
+// the txid is not part of the path.
+func (mp *MerklePath) Verify(txID, merkleRoot *chainhash.Hash) (bool, error) {
+	root, err := mp.ComputeRoot(txID)
+	if err != nil {
+		return false, err
+	}
+
+	return root.IsEqual(merkleRoot), nil
+}
+
+// Combine merges other into the merkle path so that the result proves the txids
+// of both paths. Both paths must be from the same block, and so must have the
+// same block height and compute the same merkle root.
+//
+// Leaves which can be computed from the levels below them are dropped from the
+// combined path.
+func (mp *MerklePath) Combine(other *MerklePath) error {
+	if mp.BlockHeight != other.BlockHeight {
+		return errors.Wrapf(ErrMerklePathBlockHeightMismatch, "%d and %d", mp.BlockHeight, other.BlockHeight)
+	}
+	if len(mp.Path) != len(other.Path) {
+		return errors.Wrapf(ErrMerklePathTreeHeightMismatch, "%d and %d", len(mp.Path), len(other.Path))
+	}
+
+	root, err := mp.ComputeRoot(nil)
+	if err != nil {
+		return err
+	}
+	otherRoot, err := other.ComputeRoot(nil)
+	if err != nil {
+		return err
+	}
+	if !root.IsEqual(otherRoot) {
+		return errors.Wrapf(ErrMerklePathRootMismatch, "%s and %s", root, otherRoot)
+	}
+
+	index := mp.index()
+	for height, level := range other.Path {
+		for _, leaf := range level {
+			if existing, ok := index[height][leaf.Offset]; ok {
+				existing.TxID = existing.TxID || leaf.TxID
+				continue
+			}
+
+			l := *leaf
+			if leaf.Hash != nil {
+				h := *leaf.Hash
+				l.Hash = &h
+			}
+			index[height][leaf.Offset] = &l
+		}
+	}
+
+	for height := range mp.Path {
+		level := make([]*PathElement, 0, len(index[height]))
+		for _, leaf := range index[height] {
+			if height > 0 && !leaf.TxID && !leaf.Duplicate &&
+				mp.findOrComputeLeaf(index, height-1, leaf.Offset*2) != nil &&
+				mp.findOrComputeLeaf(index, height-1, leaf.Offset*2+1) != nil {
+				continue
+			}
+			level = append(level, leaf)
+		}
+		sort.Slice(level, func(i, j int) bool { return level[i].Offset < level[j].Offset })
+		mp.Path[height] = level
+	}
+
+	return nil
+}
+
+// index returns the leaves of each level of the path keyed by offset.
+func (mp *MerklePath) index() []map[uint64]*PathElement {
+	index := make([]map[uint64]*PathElement, len(mp.Path))
+	for height, level := range mp.Path {
+		index[height] = make(map[uint64]*PathElement, len(level))
+		for _, leaf := range level {
+			index[height][leaf.Offset] = leaf
+		}
+	}
+
+	return index
+}
+
+// findOrComputeLeaf returns the leaf at the height and offset, computing it
+// from the level below when the path omits it. Nil is returned if the leaf
+// can be neither found nor computed.
+func (mp *MerklePath) findOrComputeLeaf(index []map[uint64]*PathElement, height int, offset uint64) *PathElement {
+	if leaf, ok := index[height][offset]; ok {
+		return leaf
+	}
+	if height == 0 {
+		return nil
+	}
+
+	left := mp.findOrComputeLeaf(index, height-1, offset*2)
+	if left == nil || left.Hash == nil {
+		return nil
+	}
+
+	right := mp.findOrComputeLeaf(index, height-1, offset*2+1)
+	if right == nil {
+		return nil
+	}
+
+	var parent chainhash.Hash
+	if right.Duplicate {
+		parent = merkleTreeParent(left.Hash, left.Hash)
+	} else {
+		parent = merkleTreeParent(left.Hash, right.Hash)
+	}
+
+	return &PathElement{Offset: offset, Hash: &parent}
+}
+
+// firstTxID returns the hash of the first txid flagged leaf of the path.
+func (mp *MerklePath) firstTxID() *chainhash.Hash {
+	for _, leaf := range mp.Path[0] {
+		if leaf.TxID && leaf.Hash != nil {
+			return leaf.Hash
+		}
+	}
+
+	return nil
+}
+
+// merkleTreeParent returns the parent node of the left and right nodes of a merkle tree.
+func merkleTreeParent(left, right *chainhash.Hash) chainhash.Hash {
+	var b [chainhash.HashSize * 2]byte
+	copy(b[:chainhash.HashSize], left[:])
+	copy(b[chainhash.HashSize:], right[:])
+
+	return chainhash.DoubleHashH(b[:])
+}
@@ -0,0 +1,354 @@
+package bt_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+)
+
+// brc74MerklePath is the example merkle path from the BRC-74 specification.
+//
+//nolint:lll // test vector
+const brc74MerklePath = "fe8a6a0c000c04fde80b0011774f01d26412f0d16ea3f0447be0b5ebec67b0782e321a7a01cbdf7f734e30fde90b02004e53753e3fe4667073063a17987292cfdea278824e9888e52180581d7188d8fdea0b025e441996fc53f0191d649e68a200e752fb5f39e0d5617083408fa179ddc5c998fdeb0b0102fdf405000671394f72237d08a4277f4435e5b6edf7adc272f25effef27cdfe805ce71a81fdf50500262bccabec6c4af3ed00cc7a7414edea9c5efa92fb8623dd6160a001450a528201fdfb020101fd7c010093b3efca9b77ddec914f8effac691ecb54e2c81d0ab81cbc4c4b93befe418e8501bf01015e005881826eb6973c54003a02118fe270f03d46d02681c8bc71cd44c613e86302f8012e00e07a2bb8bb75e5accff266022e1e5e6e7b4d6d943a04faadcf2ab4a22f796ff30116008120cafa17309c0bb0e0ffce835286b3a2dcae48e4497ae2d2b7ced4f051507d010a00502e59ac92f46543c23006bff855d96f5e648043f0fb87a7a5949e6a9bebae430104001ccd9f8f64f4d0489b30cc815351cf425e0e78ad79a589350e4341ac165dbe45010301010000af8764ce7e1cc132ab5ed2229a005c87201c9a5ee15c0f91dd53eff31ab30cd4"
+
+const brc74MerkleRoot = "57aab6e6fb1b697174ffb64e062c4728f2ffd33ddcfa02a43b64d8cd29b483b4"
+
+// merkleTestTree returns the levels of the merkle tree of the hashes, with
+// levels of an odd width padded by duplicating their last node.
+func merkleTestTree(hashes []chainhash.Hash) [][]chainhash.Hash {
+	tree := [][]chainhash.Hash{hashes}
+	for level := hashes; len(level) > 1; level = tree[len(tree)-1] {
+		if len(level)%2 == 1 {
+			level = append(level, level[len(level)-1])
+		}
+
+		parents := make([]chainhash.Hash, 0, len(level)/2)
+		for i := 0; i < len(level); i += 2 {
+			parents = append(parents, chainhash.DoubleHashH(append(level[i][:], level[i+1][:]...)))
+		}
+		tree = append(tree, parents)
+	}
+
+	return tree
+}
+
+// merkleTestPath builds the merkle path of the hash at index idx of the tree.
+func merkleTestPath(tree [][]chainhash.Hash, idx uint64) *bt.MerklePath {
+	mp := &bt.MerklePath{BlockHeight: 100, Path: make([][]*bt.PathElement, len(tree)-1)}
+
+	txID := tree[0][idx]
+	mp.Path[0] = append(mp.Path[0], &bt.PathElement{Offset: idx, Hash: &txID, TxID: true})
+
+	offset := idx
+	for height := 0; height < len(tree)-1; height++ {
+		sib := offset ^ 1
+		if sib < uint64(len(tree[height])) {
+			h := tree[height][sib]
+			mp.Path[height] = append(mp.Path[height], &bt.PathElement{Offset: sib, Hash: &h})
+		} else {
+			mp.Path[height] = append(mp.Path[height], &bt.PathElement{Offset: sib, Duplicate: true})
+		}
+		offset >>= 1
+	}
+
+	return mp
+}
+
+func merkleTestHashes(n int) []chainhash.Hash {
+	hashes := make([]chainhash.Hash, n)
+	for i := range hashes {
+		hashes[i] = chainhash.DoubleHashH([]byte{byte(i)})
+	}
+
+	return hashes
+}
+
+func testMerklePath() *bt.MerklePath {
+	txID := chainhash.DoubleHashH([]byte("txid"))
+	sibling := chainhash.DoubleHashH([]byte("sibling"))
+	uncle := chainhash.DoubleHashH([]byte("uncle"))
+
+	return &bt.MerklePath{
+		BlockHeight: 813706,
+		Path: [][]*bt.PathElement{
+			{
+				{Offset: 2, Hash: &sibling},
+				{Offset: 3, Hash: &txID, TxID: true},
+			},
+			{
+				{Offset: 0, Hash: &uncle},
+			},
+			{
+				{Offset: 1, Duplicate: true},
+			},
+		},
+	}
+}
+
+func TestMerklePath_Bytes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round trip", func(t *testing.T) {
+		mp := testMerklePath()
+
+		got, err := bt.NewMerklePathFromBytes(mp.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, mp, got)
+	})
+
+	t.Run("round trip from string", func(t *testing.T) {
+		mp := testMerklePath()
+
+		got, err := bt.NewMerklePathFromString(mp.String())
+		require.NoError(t, err)
+		assert.Equal(t, mp.String(), got.String())
+	})
+
+	t.Run("duplicate leaves have no hash", func(t *testing.T) {
+		mp := &bt.MerklePath{
+			BlockHeight: 1,
+			Path:        [][]*bt.PathElement{{{Offset: 1, Duplicate: true}}},
+		}
+		assert.Equal(t, []byte{0x01, 0x01, 0x01, 0x01, 0x01}, mp.Bytes())
+	})
+
+	t.Run("write to", func(t *testing.T) {
+		mp := testMerklePath()
+
+		var buf bytes.Buffer
+		n, err := mp.WriteTo(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, int64(buf.Len()), n)
+		assert.Equal(t, mp.Bytes(), buf.Bytes())
+	})
+}
+
+func TestNewMerklePathFromBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		bb     []byte
+		expErr error
+	}{
+		"zero tree height": {
+			bb:     []byte{0x01, 0x00},
+			expErr: bt.ErrMerklePathTreeHeight,
+		},
+		"tree height too large": {
+			bb:     []byte{0x01, 0x41},
+			expErr: bt.ErrMerklePathTreeHeight,
+		},
+		"invalid flags": {
+			bb:     []byte{0x01, 0x01, 0x01, 0x00, 0x03},
+			expErr: bt.ErrMerklePathInvalidFlags,
+		},
+		"trailing bytes": {
+			bb:     []byte{0x01, 0x01, 0x01, 0x01, 0x01, 0x00},
+			expErr: bt.ErrMerklePathTrailingBytes,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := bt.NewMerklePathFromBytes(test.bb)
+			require.ErrorIs(t, err, test.expErr)
+		})
+	}
+
+	t.Run("truncated hash", func(t *testing.T) {
+		_, err := bt.NewMerklePathFromBytes([]byte{0x01, 0x01, 0x01, 0x00, 0x00, 0xaa})
+		require.Error(t, err)
+	})
+}
+
+func TestMerklePath_ContainsTxID(t *testing.T) {
+	t.Parallel()
+
+	mp := testMerklePath()
+	txID := chainhash.DoubleHashH([]byte("txid"))
+	uncle := chainhash.DoubleHashH([]byte("uncle"))
+
+	assert.True(t, mp.ContainsTxID(&txID))
+	assert.False(t, mp.ContainsTxID(&uncle))
+	assert.False(t, mp.ContainsTxID(nil))
+}
+
+func TestMerklePath_ComputeRoot(t *testing.T) {
+	t.Parallel()
+
+	t.Run("brc-74 example", func(t *testing.T) {
+		mp, err := bt.NewMerklePathFromString(brc74MerklePath)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(813706), mp.BlockHeight)
+		assert.Equal(t, brc74MerklePath, mp.String())
+
+		for _, leaf := range mp.Path[0] {
+			if leaf.Duplicate {
+				continue
+			}
+			root, err := mp.ComputeRoot(leaf.Hash)
+			require.NoError(t, err)
+			assert.Equal(t, brc74MerkleRoot, root.String())
+		}
+
+		root, err := mp.ComputeRoot(nil)
+		require.NoError(t, err)
+		assert.Equal(t, brc74MerkleRoot, root.String())
+	})
+
+	t.Run("every txid of generated trees", func(t *testing.T) {
+		for _, n := range []int{2, 3, 5, 8, 13} {
+			tree := merkleTestTree(merkleTestHashes(n))
+			expRoot := tree[len(tree)-1][0]
+
+			for i := range tree[0] {
+				mp := merkleTestPath(tree, uint64(i))
+				root, err := mp.ComputeRoot(&tree[0][i])
+				require.NoError(t, err)
+				assert.Equal(t, expRoot, *root, "txid %d of %d", i, n)
+			}
+		}
+	})
+
+	t.Run("single tx block", func(t *testing.T) {
+		txID := chainhash.DoubleHashH([]byte("coinbase"))
+		mp := &bt.MerklePath{Path: [][]*bt.PathElement{{{Offset: 0, Hash: &txID, TxID: true}}}}
+
+		root, err := mp.ComputeRoot(&txID)
+		require.NoError(t, err)
+		assert.Equal(t, txID, *root)
+	})
+
+	t.Run("txid not in path", func(t *testing.T) {
+		mp, err := bt.NewMerklePathFromString(brc74MerklePath)
+		require.NoError(t, err)
+
+		other := chainhash.DoubleHashH([]byte("other"))
+		_, err = mp.ComputeRoot(&other)
+		require.ErrorIs(t, err, bt.ErrMerklePathTxIDNotFound)
+	})
+
+	t.Run("missing sibling", func(t *testing.T) {
+		tree := merkleTestTree(merkleTestHashes(4))
+		mp := merkleTestPath(tree, 1)
+		mp.Path[1] = nil
+
+		_, err := mp.ComputeRoot(&tree[0][1])
+		require.ErrorIs(t, err, bt.ErrMerklePathMissingLeaf)
+	})
+
+	t.Run("empty path", func(t *testing.T) {
+		_, err := (&bt.MerklePath{}).ComputeRoot(nil)
+		require.ErrorIs(t, err, bt.ErrMerklePathEmpty)
+	})
+
+	t.Run("no txid leaf", func(t *testing.T) {
+		tree := merkleTestTree(merkleTestHashes(2))
+		mp := merkleTestPath(tree, 0)
+		mp.Path[0][0].TxID = false
+
+		_, err := mp.ComputeRoot(nil)
+		require.ErrorIs(t, err, bt.ErrMerklePathNoTxID)
+	})
+}
+
+func TestMerklePath_Verify(t *testing.T) {
+	t.Parallel()
+
+	mp, err := bt.NewMerklePathFromString(brc74MerklePath)
+	require.NoError(t, err)
+
+	root, err := chainhash.NewHashFromStr(brc74MerkleRoot)
+	require.NoError(t, err)
+
+	ok, err := mp.Verify(mp.Path[0][0].Hash, root)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	other := chainhash.DoubleHashH([]byte("other"))
+	ok, err = mp.Verify(mp.Path[0][0].Hash, &other)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = mp.Verify(&other, root)
+	require.ErrorIs(t, err, bt.ErrMerklePathTxIDNotFound)
+}
+
+func TestMerklePath_Combine(t *testing.T) {
+	t.Parallel()
+
+	t.Run("combined path proves both txids", func(t *testing.T) {
+		tree := merkleTestTree(merkleTestHashes(13))
+		expRoot := tree[len(tree)-1][0]
+
+		mp := merkleTestPath(tree, 2)
+		require.NoError(t, mp.Combine(merkleTestPath(tree, 0)))
+		require.NoError(t, mp.Combine(merkleTestPath(tree, 12)))
+
+		for _, idx := range []int{0, 2, 12} {
+			root, err := mp.ComputeRoot(&tree[0][idx])
+			require.NoError(t, err)
+			assert.Equal(t, expRoot, *root)
+		}
+
+		// the parents of txids 0 and 2 can be computed from the
+		// leaves below them, so are dropped from the combined path.
+		for _, leaf := range mp.Path[1] {
+			assert.NotContains(t, []uint64{0, 1}, leaf.Offset)
+		}
+		assert.Len(t, mp.Path[0], 6)
+
+		got, err := bt.NewMerklePathFromBytes(mp.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, mp.String(), got.String())
+	})
+
+	t.Run("different block heights", func(t *testing.T) {
+		tree := merkleTestTree(merkleTestHashes(4))
+		other := merkleTestPath(tree, 1)
+		other.BlockHeight++
+
+		err := merkleTestPath(tree, 0).Combine(other)
+		require.ErrorIs(t, err, bt.ErrMerklePathBlockHeightMismatch)
+	})
+
+	t.Run("different roots", func(t *testing.T) {
+		tree := merkleTestTree(merkleTestHashes(4))
+		otherTree := merkleTestTree(merkleTestHashes(3))
+
+		err := merkleTestPath(tree, 0).Combine(merkleTestPath(otherTree, 1))
+		require.ErrorIs(t, err, bt.ErrMerklePathRootMismatch)
+	})
+
+	t.Run("different tree heights", func(t *testing.T) {
+		tree := merkleTestTree(merkleTestHashes(4))
+		otherTree := merkleTestTree(merkleTestHashes(8))
+
+		err := merkleTestPath(tree, 0).Combine(merkleTestPath(otherTree, 1))
+		require.ErrorIs(t, err, bt.ErrMerklePathTreeHeightMismatch)
+	})
+}
+
+func TestMerklePath_JSON(t *testing.T) {
+	t.Parallel()
+
+	mp, err := bt.NewMerklePathFromString(brc74MerklePath)
+	require.NoError(t, err)
+
+	bb, err := json.Marshal(mp)
+	require.NoError(t, err)
+	assert.Contains(t, string(bb), `"blockHeight":813706`)
+	assert.Contains(t, string(bb),
+		`{"offset":3048,"hash":"304e737fdfcb017a1a322e78b067ecebb5e07b44f0a36ed1f01264d2014f7711"}`)
+	assert.Contains(t, string(bb), `{"offset":3051,"duplicate":true}`)
+
+	var got bt.MerklePath
+	require.NoError(t, json.Unmarshal(bb, &got))
+	assert.Equal(t, brc74MerklePath, got.String())
+}
@@ -0,0 +1,784 @@
+package bt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"maps"
+	"math"
+	"slices"
+
+	crypto "github.com/bsv-blockchain/go-sdk/primitives/hash"
+	"github.com/pkg/errors"
+
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/sighash"
+)
+
+/*
+General format of a partially signed transaction, modelled on BIP174 (PSBT)
+--------------------------------------------------------------------------------------
+Field            Description                                                               Size
+
+magic            "pstx" followed by 0xff                                                   5 bytes
+
+global map       the entries of the tx, terminated by 0x00                                 variable
+
+input maps       a map per input of the tx, each terminated by 0x00                        variable
+
+output maps      a map per output of the tx, each terminated by 0x00                       variable
+
+each map entry:
+  keyLen         length of the key                                                         1 - 9 bytes VI = VarInt
+  key            key type followed by the key data                                         <keyLen>-many bytes
+  valueLen       length of the value                                                       1 - 9 bytes VI = VarInt
+  value          the value                                                                 <valueLen>-many bytes
+
+global key types:
+  0x00           the tx, in extended format if it is extended, with no key data
+input key types:
+  0x02           partial signature with its SIGHASH flag, keyed by public key
+  0x03           intended SIGHASH flag as a little endian uint32, with no key data
+  0x04           the party signing the input, with no key data
+  0x06           derivation path hint, keyed by public key
+  0x07           final unlocking script, with no key data
+output key types:
+  0x02           derivation path hint, keyed by public key
+input and output key types:
+  0xfc           application metadata, keyed by name
+
+Entries are written sorted by key. Entries with a key type unknown to the map
+are read into Unknown and written back unchanged.
+--------------------------------------------------------------------------------------
+*/
+
+// PartialTxMagic is the magic prefixing a binary encoded PartialTx.
+var PartialTxMagic = []byte{0x70, 0x73, 0x74, 0x78, 0xff}
+
+// PartialTx key types.
+const (
+	partialTxGlobalTx byte = 0x00
+
+	partialTxInPartialSig  byte = 0x02
+	partialTxInSigHashFlag byte = 0x03
+	partialTxInSigner      byte = 0x04
+	partialTxInDerivation  byte = 0x06
+	partialTxInFinalScript byte = 0x07
+
+	partialTxOutDerivation byte = 0x02
+
+	partialTxMetadata byte = 0xfc
+)
+
+// The key types known to each map, which an unknown entry cannot have.
+var (
+	partialTxGlobalKeyTypes = []byte{partialTxGlobalTx}
+	partialTxInKeyTypes     = []byte{
+		partialTxInPartialSig, partialTxInSigHashFlag, partialTxInSigner,
+		partialTxInDerivation, partialTxInFinalScript, partialTxMetadata,
+	}
+	partialTxOutKeyTypes = []byte{partialTxOutDerivation, partialTxMetadata}
+)
+
+// PartialTx is a partially signed transaction, passed between the parties of a
+// multi-party signing workflow. It holds the tx along with metadata for each of
+// its inputs and outputs, such as the party signing an input, its intended
+// SIGHASH flag and the signatures collected so far.
+//
+// Each party adds its signatures, the PartialTxs of the parties are combined,
+// and once enough signatures are collected the inputs are finalized and the
+// signed tx extracted:
+//
+//	p := bt.NewPartialTx(tx)
+//	sh, err := p.SigHash(0)
+//	// sign sh
+//	err = p.AddPartialSig(0, pubKey, sig)
+//	err = p.Combine(others...)
+//	err = p.Finalize()
+//	signedTx, err := p.Extract()
+type PartialTx struct {
+	// Tx is the tx being signed, with empty unlocking scripts.
+	Tx *Tx
+	// Inputs hold the metadata of each input of Tx.
+	Inputs []*PartialTxInput
+	// Outputs hold the metadata of each output of Tx.
+	Outputs []*PartialTxOutput
+	// Unknown are the global entries with unknown key types, keyed by the hex
+	// of the key, which are passed through unchanged.
+	Unknown map[string][]byte
+}
+
+// PartialTxInput is the metadata of an input of a PartialTx.
+type PartialTxInput struct {
+	// PartialSigs are the signatures collected for the input, keyed by the hex
+	// of the public key they are for. Each signature has its SIGHASH flag
+	// appended, as with `unlocker.PartialSignatures`.
+	PartialSigs map[string][]byte
+	// SigHashFlag is the SIGHASH flag the input is intended to be signed with.
+	// Zero if not set, in which case ALL|FORKID is used.
+	SigHashFlag sighash.Flag
+	// Signer identifies the party which signs the input.
+	Signer string
+	// Derivations are hints of the derivation path of each public key, keyed
+	// by the hex of the public key.
+	Derivations map[string]string
+	// FinalScript is the unlocking script of the input once finalized.
+	FinalScript *bscript.Script
+	// Metadata is application defined metadata, keyed by name.
+	Metadata map[string][]byte
+	// Unknown are the entries with unknown key types, keyed by the hex of the
+	// key, which are passed through unchanged.
+	Unknown map[string][]byte
+}
+
+// PartialTxOutput is the metadata of an output of a PartialTx.
+type PartialTxOutput struct {
+	// Derivations are hints of the derivation path of each public key, keyed
+	// by the hex of the public key.
+	Derivations map[string]string
+	// Metadata is application defined metadata, keyed by name.
+	Metadata map[string][]byte
+	// Unknown are the entries with unknown key types, keyed by the hex of the
+	// key, which are passed through unchanged.
+	Unknown map[string][]byte
+}
+
+// NewPartialTx creates a PartialTx for a clone of the tx. The unlocking
+// scripts of any inputs already signed become their FinalScript.
+func NewPartialTx(tx *Tx) *PartialTx {
+	p := &PartialTx{
+		Tx:      tx.Clone(),
+		Inputs:  make([]*PartialTxInput, len(tx.Inputs)),
+		Outputs: make([]*PartialTxOutput, len(tx.Outputs)),
+	}
+
+	for i, in := range p.Tx.Inputs {
+		p.Inputs[i] = &PartialTxInput{}
+		if in.UnlockingScript != nil && len(*in.UnlockingScript) > 0 {
+			p.Inputs[i].FinalScript = in.UnlockingScript
+		}
+		in.UnlockingScript = &bscript.Script{}
+	}
+	for i := range p.Outputs {
+		p.Outputs[i] = &PartialTxOutput{}
+	}
+
+	return p
+}
+
+// NewPartialTxFromBytes decodes a binary encoded PartialTx.
+func NewPartialTxFromBytes(b []byte) (*PartialTx, error) {
+	p := &PartialTx{}
+	n, err := p.ReadFrom(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	if int(n) != len(b) {
+		return nil, errors.Wrapf(ErrPartialTxTrailingBytes, "read %d of %d bytes", n, len(b))
+	}
+
+	return p, nil
+}
+
+// NewPartialTxFromString decodes a hex string of a binary encoded PartialTx.
+func NewPartialTxFromString(str string) (*PartialTx, error) {
+	b, err := hex.DecodeString(str)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPartialTxFromBytes(b)
+}
+
+// SigHash returns the signature hash of the input, with the SIGHASH flag it is
+// intended to be signed with.
+func (p *PartialTx) SigHash(inputIdx uint32) ([]byte, error) {
+	if int(inputIdx) >= len(p.Inputs) {
+		return nil, ErrInputNoExist
+	}
+
+	return p.Tx.CalcInputSignatureHash(inputIdx, p.Inputs[inputIdx].sigHashFlag())
+}
+
+// AddPartialSig adds the DER signature of the public key to the input, with the
+// SIGHASH flag the input is intended to be signed with appended.
+func (p *PartialTx) AddPartialSig(inputIdx uint32, pubKey, sig []byte) error {
+	if int(inputIdx) >= len(p.Inputs) {
+		return ErrInputNoExist
+	}
+
+	in := p.Inputs[inputIdx]
+	if in.PartialSigs == nil {
+		in.PartialSigs = make(map[string][]byte)
+	}
+	sigBuf := make([]byte, 0, len(sig)+1)
+	sigBuf = append(sigBuf, sig...)
+	in.PartialSigs[hex.EncodeToString(pubKey)] = append(sigBuf, uint8(in.sigHashFlag()))
+
+	return nil
+}
+
+// Combine merges the metadata of the other PartialTxs, which must be for the
+// same tx, into p. Signatures, derivations, metadata and unknown entries are
+// merged, replacing any with the same key. The SIGHASH flag, signer and final
+// script of an input are only taken from the others if not set in p.
+func (p *PartialTx) Combine(others ...*PartialTx) error {
+	for _, o := range others {
+		if o.Tx.TxID() != p.Tx.TxID() ||
+			len(o.Inputs) != len(p.Inputs) ||
+			len(o.Outputs) != len(p.Outputs) {
+			return errors.Wrapf(ErrPartialTxMismatch, "got %s for %s", o.Tx.TxID(), p.Tx.TxID())
+		}
+	}
+
+	for _, o := range others {
+		p.Unknown = mergeMap(p.Unknown, o.Unknown)
+
+		for i, in := range p.Inputs {
+			oin := o.Inputs[i]
+			in.PartialSigs = mergeMap(in.PartialSigs, oin.PartialSigs)
+			in.Derivations = mergeMap(in.Derivations, oin.Derivations)
+			in.Metadata = mergeMap(in.Metadata, oin.Metadata)
+			in.Unknown = mergeMap(in.Unknown, oin.Unknown)
+			if in.SigHashFlag == 0 {
+				in.SigHashFlag = oin.SigHashFlag
+			}
+			if in.Signer == "" {
+				in.Signer = oin.Signer
+			}
+			if in.FinalScript == nil {
+				in.FinalScript = cloneScript(oin.FinalScript)
+			}
+		}
+
+		for i, out := range p.Outputs {
+			oout := o.Outputs[i]
+			out.Derivations = mergeMap(out.Derivations, oout.Derivations)
+			out.Metadata = mergeMap(out.Metadata, oout.Metadata)
+			out.Unknown = mergeMap(out.Unknown, oout.Unknown)
+		}
+	}
+
+	return nil
+}
+
+// Finalize builds the FinalScript of each input not yet finalized from its
+// partial signatures, clearing the signatures and derivations no longer needed.
+// P2PKH, P2PK and bare multisig inputs can be finalized.
+//
+// If an input cannot be finalized, such as when it has too few signatures, an
+// error wrapping ErrPartialTxCannotFinalize is returned, with the inputs before
+// it finalized.
+func (p *PartialTx) Finalize() error {
+	for i, in := range p.Inputs {
+		if in.FinalScript != nil {
+			continue
+		}
+
+		s, err := in.finalScript(p.Tx.Inputs[i].PreviousTxScript)
+		if err != nil {
+			return errors.Wrapf(err, "input %d", i)
+		}
+		in.FinalScript = s
+		in.PartialSigs = nil
+		in.Derivations = nil
+	}
+
+	return nil
+}
+
+// IsFinalized returns true if every input has a FinalScript.
+func (p *PartialTx) IsFinalized() bool {
+	for _, in := range p.Inputs {
+		if in.FinalScript == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Extract returns a clone of the tx with the FinalScript of each input as its
+// unlocking script. An error wrapping ErrPartialTxNotFinalized is returned if
+// any input is not finalized.
+func (p *PartialTx) Extract() (*Tx, error) {
+	for i, in := range p.Inputs {
+		if in.FinalScript == nil {
+			return nil, errors.Wrapf(ErrPartialTxNotFinalized, "input %d", i)
+		}
+	}
+
+	tx := p.Tx.Clone()
+	for i, in := range p.Inputs {
+		tx.Inputs[i].UnlockingScript = cloneScript(in.FinalScript)
+	}
+
+	return tx, nil
+}
+
+// ReadFrom reads a binary encoded PartialTx from the `io.Reader` into the
+// `bt.PartialTx`.
+func (p *PartialTx) ReadFrom(r io.Reader) (int64, error) {
+	*p = PartialTx{}
+	var bytesRead int64
+
+	magic := make([]byte, len(PartialTxMagic))
+	n, err := io.ReadFull(r, magic)
+	bytesRead += int64(n)
+	if err != nil {
+		return bytesRead, errors.Wrapf(err, "magic(%d): got %d bytes", len(PartialTxMagic), n)
+	}
+	if !bytes.Equal(magic, PartialTxMagic) {
+		return bytesRead, errors.Wrapf(ErrPartialTxMagic, "got %x", magic)
+	}
+
+	n64, err := readPartialTxMap(r, func(key, value []byte) error {
+		if key[0] != partialTxGlobalTx {
+			p.Unknown = addUnknown(p.Unknown, key, value)
+			return nil
+		}
+		if len(key) != 1 {
+			return errors.Wrapf(ErrPartialTxInvalidKey, "tx key %x", key)
+		}
+
+		tx, err := NewTxFromBytes(value)
+		if err != nil {
+			return errors.Wrap(err, "tx")
+		}
+		p.Tx = tx
+		return nil
+	})
+	bytesRead += n64
+	if err != nil {
+		return bytesRead, errors.Wrap(err, "global map")
+	}
+	if p.Tx == nil {
+		return bytesRead, ErrPartialTxNoTx
+	}
+	if err = checkUnsigned(p.Tx); err != nil {
+		return bytesRead, err
+	}
+
+	p.Inputs = make([]*PartialTxInput, len(p.Tx.Inputs))
+	for i := range p.Inputs {
+		in := &PartialTxInput{}
+		n64, err = readPartialTxMap(r, in.readEntry)
+		bytesRead += n64
+		if err != nil {
+			return bytesRead, errors.Wrapf(err, "input map %d", i)
+		}
+		p.Inputs[i] = in
+	}
+
+	p.Outputs = make([]*PartialTxOutput, len(p.Tx.Outputs))
+	for i := range p.Outputs {
+		out := &PartialTxOutput{}
+		n64, err = readPartialTxMap(r, out.readEntry)
+		bytesRead += n64
+		if err != nil {
+			return bytesRead, errors.Wrapf(err, "output map %d", i)
+		}
+		p.Outputs[i] = out
+	}
+
+	return bytesRead, nil
+}
+
+// Bytes encodes the PartialTx into the binary format. An error is returned if
+// a key is one ReadFrom would reject, such as a public key of a partial
+// signature which is not the hex of a public key, or an unknown key with a key
+// type known to its map, or if the number of input or output metadata does not
+// match the tx.
+func (p *PartialTx) Bytes() ([]byte, error) {
+	if p.Tx == nil {
+		return nil, ErrPartialTxNoTx
+	}
+	if len(p.Inputs) != len(p.Tx.Inputs) || len(p.Outputs) != len(p.Tx.Outputs) {
+		return nil, errors.Wrapf(ErrPartialTxMapCount, "got %d input and %d output maps for %d inputs and %d outputs",
+			len(p.Inputs), len(p.Outputs), len(p.Tx.Inputs), len(p.Tx.Outputs))
+	}
+
+	h := append(make([]byte, 0), PartialTxMagic...)
+
+	global, err := appendUnknown(nil, p.Unknown, partialTxGlobalKeyTypes)
+	if err != nil {
+		return nil, errors.Wrap(err, "global map")
+	}
+	global = append(global, partialTxEntry{key: []byte{partialTxGlobalTx}, value: p.Tx.SerializeBytes()})
+	h = appendPartialTxMap(h, global)
+
+	for i, in := range p.Inputs {
+		entries, err := in.entries()
+		if err != nil {
+			return nil, errors.Wrapf(err, "input map %d", i)
+		}
+		h = appendPartialTxMap(h, entries)
+	}
+
+	for i, out := range p.Outputs {
+		entries, err := out.entries()
+		if err != nil {
+			return nil, errors.Wrapf(err, "output map %d", i)
+		}
+		h = appendPartialTxMap(h, entries)
+	}
+
+	return h, nil
+}
+
+// WriteTo writes the binary encoded PartialTx to w.
+func (p *PartialTx) WriteTo(w io.Writer) (int64, error) {
+	b, err := p.Bytes()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(b)
+	return int64(n), err
+}
+
+func (in *PartialTxInput) sigHashFlag() sighash.Flag {
+	if in.SigHashFlag == 0 {
+		return sighash.AllForkID
+	}
+	return in.SigHashFlag
+}
+
+// finalScript returns the unlocking script of the locking script built from
+// the partial signatures of the input.
+func (in *PartialTxInput) finalScript(lockingScript *bscript.Script) (*bscript.Script, error) {
+	if lockingScript == nil {
+		return nil, ErrEmptyPreviousTxScript
+	}
+
+	switch {
+	case lockingScript.IsP2PKH():
+		pkh, err := lockingScript.PublicKeyHash()
+		if err != nil {
+			return nil, err
+		}
+		for pubKeyHex, sig := range in.PartialSigs {
+			pubKey, err := hex.DecodeString(pubKeyHex)
+			if err != nil {
+				return nil, errors.Wrapf(ErrPartialTxInvalidKey, "public key %s", pubKeyHex)
+			}
+			if bytes.Equal(crypto.Hash160(pubKey), pkh) {
+				s := &bscript.Script{}
+				return s, s.AppendPushDataArray([][]byte{sig, pubKey})
+			}
+		}
+		return nil, errors.Wrap(ErrPartialTxCannotFinalize, "no signature for the public key hash")
+	case lockingScript.IsP2PK():
+		parts, err := bscript.DecodeParts(*lockingScript)
+		if err != nil {
+			return nil, err
+		}
+		sig, ok := in.PartialSigs[hex.EncodeToString(parts[0])]
+		if !ok {
+			return nil, errors.Wrap(ErrPartialTxCannotFinalize, "no signature for the public key")
+		}
+		s := &bscript.Script{}
+		return s, s.AppendPushData(sig)
+	case lockingScript.IsMultiSigOut():
+		required, pubKeys, err := lockingScript.ParseMultiSig()
+		if err != nil {
+			return nil, err
+		}
+		ordered := make([][]byte, 0, required)
+		for _, pubKey := range pubKeys {
+			if len(ordered) == required {
+				break
+			}
+			if sig, ok := in.PartialSigs[hex.EncodeToString(pubKey)]; ok {
+				ordered = append(ordered, sig)
+			}
+		}
+		if len(ordered) < required {
+			return nil, errors.Wrapf(ErrPartialTxCannotFinalize, "have %d of %d signatures", len(ordered), required)
+		}
+		return bscript.NewMultiSigUnlockingScript(ordered)
+	}
+
+	return nil, errors.Wrap(ErrPartialTxCannotFinalize, "unsupported locking script")
+}
+
+func (in *PartialTxInput) readEntry(key, value []byte) error {
+	switch key[0] {
+	case partialTxInPartialSig:
+		if !isPubKey(key[1:]) {
+			return errors.Wrapf(ErrPartialTxInvalidKey, "partial signature key %x", key)
+		}
+		if in.PartialSigs == nil {
+			in.PartialSigs = make(map[string][]byte)
+		}
+		in.PartialSigs[hex.EncodeToString(key[1:])] = value
+	case partialTxInSigHashFlag:
+		if len(key) != 1 {
+			return errors.Wrapf(ErrPartialTxInvalidKey, "sighash flag key %x", key)
+		}
+		if len(value) != 4 {
+			return errors.Wrapf(ErrPartialTxInvalidValue, "sighash flag %x", value)
+		}
+		in.SigHashFlag = sighash.Flag(binary.LittleEndian.Uint32(value))
+	case partialTxInSigner:
+		if len(key) != 1 {
+			return errors.Wrapf(ErrPartialTxInvalidKey, "signer key %x", key)
+		}
+		in.Signer = string(value)
+	case partialTxInDerivation:
+		if !isPubKey(key[1:]) {
+			return errors.Wrapf(ErrPartialTxInvalidKey, "derivation key %x", key)
+		}
+		if in.Derivations == nil {
+			in.Derivations = make(map[string]string)
+		}
+		in.Derivations[hex.EncodeToString(key[1:])] = string(value)
+	case partialTxInFinalScript:
+		if len(key) != 1 {
+			return errors.Wrapf(ErrPartialTxInvalidKey, "final script key %x", key)
+		}
+		in.FinalScript = bscript.NewFromBytes(value)
+	case partialTxMetadata:
+		if in.Metadata == nil {
+			in.Metadata = make(map[string][]byte)
+		}
+		in.Metadata[string(key[1:])] = value
+	default:
+		in.Unknown = addUnknown(in.Unknown, key, value)
+	}
+
+	return nil
+}
+
+func (in *PartialTxInput) entries() ([]partialTxEntry, error) {
+	entries, err := appendUnknown(nil, in.Unknown, partialTxInKeyTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	for pubKeyHex, sig := range in.PartialSigs {
+		pubKey, err := decodePubKeyHex(pubKeyHex)
+		if err != nil {
+			return nil, errors.Wrapf(err, "partial signature public key %s", pubKeyHex)
+		}
+		entries = append(entries, partialTxEntry{key: append([]byte{partialTxInPartialSig}, pubKey...), value: sig})
+	}
+	if in.SigHashFlag != 0 {
+		entries = append(entries, partialTxEntry{
+			key:   []byte{partialTxInSigHashFlag},
+			value: binary.LittleEndian.AppendUint32(nil, uint32(in.SigHashFlag)),
+		})
+	}
+	if in.Signer != "" {
+		entries = append(entries, partialTxEntry{key: []byte{partialTxInSigner}, value: []byte(in.Signer)})
+	}
+	entries, err = appendDerivations(entries, partialTxInDerivation, in.Derivations)
+	if err != nil {
+		return nil, err
+	}
+	if in.FinalScript != nil {
+		entries = append(entries, partialTxEntry{key: []byte{partialTxInFinalScript}, value: *in.FinalScript})
+	}
+
+	return appendMetadata(entries, in.Metadata), nil
+}
+
+func (out *PartialTxOutput) readEntry(key, value []byte) error {
+	switch key[0] {
+	case partialTxOutDerivation:
+		if !isPubKey(key[1:]) {
+			return errors.Wrapf(ErrPartialTxInvalidKey, "derivation key %x", key)
+		}
+		if out.Derivations == nil {
+			out.Derivations = make(map[string]string)
+		}
+		out.Derivations[hex.EncodeToString(key[1:])] = string(value)
+	case partialTxMetadata:
+		if out.Metadata == nil {
+			out.Metadata = make(map[string][]byte)
+		}
+		out.Metadata[string(key[1:])] = value
+	default:
+		out.Unknown = addUnknown(out.Unknown, key, value)
+	}
+
+	return nil
+}
+
+func (out *PartialTxOutput) entries() ([]partialTxEntry, error) {
+	entries, err := appendUnknown(nil, out.Unknown, partialTxOutKeyTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err = appendDerivations(entries, partialTxOutDerivation, out.Derivations)
+	if err != nil {
+		return nil, err
+	}
+
+	return appendMetadata(entries, out.Metadata), nil
+}
+
+// partialTxEntry is a key value pair of a PartialTx map.
+type partialTxEntry struct {
+	key, value []byte
+}
+
+// readPartialTxMap reads the entries of a map up to its terminating 0x00,
+// passing each to readEntry. Duplicate keys are rejected.
+func readPartialTxMap(r io.Reader, readEntry func(key, value []byte) error) (int64, error) {
+	var bytesRead int64
+	seen := make(map[string]struct{})
+
+	for {
+		key, n64, err := readPartialTxBytes(r)
+		bytesRead += n64
+		if err != nil {
+			return bytesRead, errors.Wrap(err, "key")
+		}
+		if len(key) == 0 {
+			return bytesRead, nil
+		}
+		if _, ok := seen[string(key)]; ok {
+			return bytesRead, errors.Wrapf(ErrPartialTxDuplicateKey, "key %x", key)
+		}
+		seen[string(key)] = struct{}{}
+
+		value, n64, err := readPartialTxBytes(r)
+		bytesRead += n64
+		if err != nil {
+			return bytesRead, errors.Wrapf(err, "value of key %x", key)
+		}
+
+		if err = readEntry(key, value); err != nil {
+			return bytesRead, err
+		}
+	}
+}
+
+// readPartialTxBytes reads a VarInt length followed by that many bytes. The
+// bytes are copied as they are read, so a corrupt length cannot cause a large
+// allocation up front.
+func readPartialTxBytes(r io.Reader) ([]byte, int64, error) {
+	var l VarInt
+	bytesRead, err := l.ReadFrom(r)
+	if err != nil {
+		return nil, bytesRead, err
+	}
+
+	if uint64(l) > math.MaxInt64 {
+		return nil, bytesRead, errors.Wrapf(ErrPartialTxInvalidValue, "length %d", uint64(l))
+	}
+
+	var buf bytes.Buffer
+	n, err := io.CopyN(&buf, r, int64(l))
+	bytesRead += n
+	if err != nil {
+		return nil, bytesRead, errors.Wrapf(err, "got %d of %d bytes", n, uint64(l))
+	}
+
+	return buf.Bytes(), bytesRead, nil
+}
+
+// appendPartialTxMap appends the entries, sorted by key, followed by the
+// terminating 0x00.
+func appendPartialTxMap(h []byte, entries []partialTxEntry) []byte {
+	slices.SortFunc(entries, func(a, b partialTxEntry) int {
+		return bytes.Compare(a.key, b.key)
+	})
+
+	for _, e := range entries {
+		h = VarInt(uint64(len(e.key))).AppendTo(h)
+		h = append(h, e.key...)
+		h = VarInt(uint64(len(e.value))).AppendTo(h)
+		h = append(h, e.value...)
+	}
+
+	return append(h, 0x00)
+}
+
+func addUnknown(unknown map[string][]byte, key, value []byte) map[string][]byte {
+	if unknown == nil {
+		unknown = make(map[string][]byte)
+	}
+	unknown[hex.EncodeToString(key)] = value
+
+	return unknown
+}
+
+// appendUnknown appends the unknown entries, returning an error if a key is not
+// hex or has one of the known key types, as it would not be read back as
+// unknown.
+func appendUnknown(entries []partialTxEntry, unknown map[string][]byte, known []byte) ([]partialTxEntry, error) {
+	for keyHex, value := range unknown {
+		key, err := hex.DecodeString(keyHex)
+		if err != nil || len(key) == 0 || slices.Contains(known, key[0]) {
+			return nil, errors.Wrapf(ErrPartialTxInvalidKey, "unknown key %q", keyHex)
+		}
+		entries = append(entries, partialTxEntry{key: key, value: value})
+	}
+
+	return entries, nil
+}
+
+func appendDerivations(entries []partialTxEntry, keyType byte, derivations map[string]string) ([]partialTxEntry, error) {
+	for pubKeyHex, path := range derivations {
+		pubKey, err := decodePubKeyHex(pubKeyHex)
+		if err != nil {
+			return nil, errors.Wrapf(err, "derivation public key %s", pubKeyHex)
+		}
+		entries = append(entries, partialTxEntry{key: append([]byte{keyType}, pubKey...), value: []byte(path)})
+	}
+
+	return entries, nil
+}
+
+func appendMetadata(entries []partialTxEntry, metadata map[string][]byte) []partialTxEntry {
+	for name, value := range metadata {
+		entries = append(entries, partialTxEntry{key: append([]byte{partialTxMetadata}, name...), value: value})
+	}
+
+	return entries
+}
+
+// checkUnsigned returns an error if any input of the tx has an unlocking script.
+func checkUnsigned(tx *Tx) error {
+	for i, in := range tx.Inputs {
+		if in.UnlockingScript != nil && len(*in.UnlockingScript) > 0 {
+			return errors.Wrapf(ErrPartialTxSigned, "input %d", i)
+		}
+	}
+
+	return nil
+}
+
+// isPubKey returns true if b has the length of a compressed or uncompressed
+// public key.
+func isPubKey(b []byte) bool {
+	return len(b) == 33 || len(b) == 65
+}
+
+// decodePubKeyHex decodes the hex of a public key, returning ErrPartialTxInvalidKey
+// if it is not hex or not the length of a public key.
+func decodePubKeyHex(pubKeyHex string) ([]byte, error) {
+	pubKey, err := hex.DecodeString(pubKeyHex)
+	if err != nil || !isPubKey(pubKey) {
+		return nil, ErrPartialTxInvalidKey
+	}
+
+	return pubKey, nil
+}
+
+// mergeMap copies src into dst, creating dst if nil and src is not empty.
+func mergeMap[V any](dst, src map[string]V) map[string]V {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]V, len(src))
+	}
+	maps.Copy(dst, src)
+
+	return dst
+}
@@ -0,0 +1,191 @@
+package bt
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/sighash"
+)
+
+type partialTxJSON struct {
+	Tx      string                 `json:"tx"`
+	Inputs  []*partialTxInputJSON  `json:"inputs"`
+	Outputs []*partialTxOutputJSON `json:"outputs"`
+	Unknown map[string]string      `json:"unknown,omitempty"`
+}
+
+type partialTxInputJSON struct {
+	PartialSigs map[string]string `json:"partialSigs,omitempty"`
+	SigHashFlag uint32            `json:"sigHashFlag,omitempty"`
+	Signer      string            `json:"signer,omitempty"`
+	Derivations map[string]string `json:"derivations,omitempty"`
+	FinalScript *string           `json:"finalScript,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Unknown     map[string]string `json:"unknown,omitempty"`
+}
+
+type partialTxOutputJSON struct {
+	Derivations map[string]string `json:"derivations,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Unknown     map[string]string `json:"unknown,omitempty"`
+}
+
+// MarshalJSON will serialize a partially signed tx to json, with the tx, the
+// signatures, final scripts, metadata and unknown entries as hex.
+func (p *PartialTx) MarshalJSON() ([]byte, error) {
+	if p.Tx == nil {
+		return nil, ErrPartialTxNoTx
+	}
+
+	pj := partialTxJSON{
+		Tx:      hex.EncodeToString(p.Tx.SerializeBytes()),
+		Inputs:  make([]*partialTxInputJSON, len(p.Inputs)),
+		Outputs: make([]*partialTxOutputJSON, len(p.Outputs)),
+		Unknown: encodeHexMap(p.Unknown),
+	}
+
+	for i, in := range p.Inputs {
+		inj := &partialTxInputJSON{
+			PartialSigs: encodeHexMap(in.PartialSigs),
+			SigHashFlag: uint32(in.SigHashFlag),
+			Signer:      in.Signer,
+			Derivations: in.Derivations,
+			Metadata:    encodeHexMap(in.Metadata),
+			Unknown:     encodeHexMap(in.Unknown),
+		}
+		if in.FinalScript != nil {
+			s := in.FinalScript.String()
+			inj.FinalScript = &s
+		}
+		pj.Inputs[i] = inj
+	}
+
+	for i, out := range p.Outputs {
+		pj.Outputs[i] = &partialTxOutputJSON{
+			Derivations: out.Derivations,
+			Metadata:    encodeHexMap(out.Metadata),
+			Unknown:     encodeHexMap(out.Unknown),
+		}
+	}
+
+	return json.Marshal(pj)
+}
+
+// UnmarshalJSON will unmarshal a partially signed tx that has been marshaled
+// with this library. The keys are checked as they are when reading the binary
+// format.
+func (p *PartialTx) UnmarshalJSON(b []byte) error {
+	var pj partialTxJSON
+	if err := json.Unmarshal(b, &pj); err != nil {
+		return err
+	}
+
+	if pj.Tx == "" {
+		return ErrPartialTxNoTx
+	}
+	tx, err := NewTxFromString(pj.Tx)
+	if err != nil {
+		return errors.Wrap(err, "tx")
+	}
+	if err = checkUnsigned(tx); err != nil {
+		return err
+	}
+	if len(pj.Inputs) != len(tx.Inputs) || len(pj.Outputs) != len(tx.Outputs) {
+		return errors.Wrapf(ErrPartialTxMapCount, "got %d inputs and %d outputs for %d inputs and %d outputs",
+			len(pj.Inputs), len(pj.Outputs), len(tx.Inputs), len(tx.Outputs))
+	}
+
+	pt := PartialTx{
+		Tx:      tx,
+		Inputs:  make([]*PartialTxInput, len(pj.Inputs)),
+		Outputs: make([]*PartialTxOutput, len(pj.Outputs)),
+	}
+	if pt.Unknown, err = decodeHexMap(pj.Unknown); err != nil {
+		return errors.Wrap(err, "unknown")
+	}
+	if _, err = appendUnknown(nil, pt.Unknown, partialTxGlobalKeyTypes); err != nil {
+		return err
+	}
+
+	for i, inj := range pj.Inputs {
+		if inj == nil {
+			inj = &partialTxInputJSON{}
+		}
+		in := &PartialTxInput{
+			SigHashFlag: sighash.Flag(inj.SigHashFlag),
+			Signer:      inj.Signer,
+			Derivations: inj.Derivations,
+		}
+		if in.PartialSigs, err = decodeHexMap(inj.PartialSigs); err != nil {
+			return errors.Wrapf(err, "input %d partial signatures", i)
+		}
+		if in.Metadata, err = decodeHexMap(inj.Metadata); err != nil {
+			return errors.Wrapf(err, "input %d metadata", i)
+		}
+		if in.Unknown, err = decodeHexMap(inj.Unknown); err != nil {
+			return errors.Wrapf(err, "input %d unknown", i)
+		}
+		if inj.FinalScript != nil {
+			if in.FinalScript, err = bscript.NewFromHexString(*inj.FinalScript); err != nil {
+				return errors.Wrapf(err, "input %d final script", i)
+			}
+		}
+		if _, err = in.entries(); err != nil {
+			return errors.Wrapf(err, "input %d", i)
+		}
+		pt.Inputs[i] = in
+	}
+
+	for i, outj := range pj.Outputs {
+		if outj == nil {
+			outj = &partialTxOutputJSON{}
+		}
+		out := &PartialTxOutput{Derivations: outj.Derivations}
+		if out.Metadata, err = decodeHexMap(outj.Metadata); err != nil {
+			return errors.Wrapf(err, "output %d metadata", i)
+		}
+		if out.Unknown, err = decodeHexMap(outj.Unknown); err != nil {
+			return errors.Wrapf(err, "output %d unknown", i)
+		}
+		if _, err = out.entries(); err != nil {
+			return errors.Wrapf(err, "output %d", i)
+		}
+		pt.Outputs[i] = out
+	}
+
+	*p = pt
+	return nil
+}
+
+func encodeHexMap(m map[string][]byte) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+
+	hm := make(map[string]string, len(m))
+	for k, v := range m {
+		hm[k] = hex.EncodeToString(v)
+	}
+
+	return hm
+}
+
+func decodeHexMap(hm map[string]string) (map[string][]byte, error) {
+	if len(hm) == 0 {
+		return nil, nil //nolint:nilnil // an empty map is nil
+	}
+
+	m := make(map[string][]byte, len(hm))
+	for k, v := range hm {
+		b, err := hex.DecodeString(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "key %s", k)
+		}
+		m[k] = b
+	}
+
+	return m, nil
+}
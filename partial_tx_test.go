@@ -0,0 +1,317 @@
+package bt_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/sighash"
+	"github.com/bsv-blockchain/go-bt/v2/unlocker"
+)
+
+// partialTxTestKeys returns a key for a P2PKH input and the keys of a 2 of 3
+// multisig input.
+func partialTxTestKeys(t *testing.T) (*primitives.PrivateKey, []*primitives.PrivateKey) {
+	t.Helper()
+
+	keys := make([]*primitives.PrivateKey, 4)
+	for i := range keys {
+		pk, err := primitives.NewPrivateKey()
+		require.NoError(t, err)
+		keys[i] = pk
+	}
+
+	return keys[0], keys[1:]
+}
+
+// newPartialTxTestTx returns a tx spending a P2PKH utxo of the key and a 2 of
+// 3 multisig utxo of the multisig keys.
+func newPartialTxTestTx(t *testing.T, key *primitives.PrivateKey, msKeys []*primitives.PrivateKey) *bt.Tx {
+	t.Helper()
+
+	p2pkh, err := bscript.NewP2PKHFromPubKeyEC(key.PubKey())
+	require.NoError(t, err)
+	pubKeys := make([]*primitives.PublicKey, len(msKeys))
+	for i, pk := range msKeys {
+		pubKeys[i] = pk.PubKey()
+	}
+	multiSig, err := bscript.NewMultiSigFromPubKeys(2, pubKeys)
+	require.NoError(t, err)
+
+	tx := bt.NewTx()
+	require.NoError(t, tx.From("3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5", 0, p2pkh.String(), 3000))
+	require.NoError(t, tx.From("3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5", 1, multiSig.String(), 5000))
+	require.NoError(t, tx.PayToAddress(beefTestAddress, 7000))
+
+	return tx
+}
+
+// signPartialTx adds the signature of the key to the input of the PartialTx.
+func signPartialTx(t *testing.T, p *bt.PartialTx, inputIdx uint32, pk *primitives.PrivateKey) {
+	t.Helper()
+
+	sh, err := p.SigHash(inputIdx)
+	require.NoError(t, err)
+	sig, err := pk.Sign(sh)
+	require.NoError(t, err)
+	require.NoError(t, p.AddPartialSig(inputIdx, pk.PubKey().Compressed(), sig.Serialize()))
+}
+
+// assertPartialTxEqual compares the PartialTxs by the tx they hold and their
+// maps, ignoring the state cached on the tx.
+func assertPartialTxEqual(t *testing.T, exp, got *bt.PartialTx) {
+	t.Helper()
+
+	assert.Equal(t, exp.Tx.ExtendedBytes(), got.Tx.ExtendedBytes())
+	assert.Equal(t, exp.Inputs, got.Inputs)
+	assert.Equal(t, exp.Outputs, got.Outputs)
+	assert.Equal(t, exp.Unknown, got.Unknown)
+}
+
+func TestPartialTx_Finalize(t *testing.T) {
+	t.Parallel()
+
+	key, msKeys := partialTxTestKeys(t)
+
+	expTx := newPartialTxTestTx(t, key, msKeys)
+	require.NoError(t, expTx.FillInput(context.Background(), &unlocker.Simple{PrivateKey: key}, bt.UnlockerParams{InputIdx: 0}))
+	require.NoError(t, expTx.FillInput(context.Background(), &unlocker.MultiSig{
+		PrivateKeys: []*primitives.PrivateKey{msKeys[0], msKeys[2]},
+	}, bt.UnlockerParams{InputIdx: 1}))
+
+	t.Run("combined parties", func(t *testing.T) {
+		tx := newPartialTxTestTx(t, key, msKeys)
+
+		// Each party signs its own copy, which is passed around encoded.
+		a := bt.NewPartialTx(tx)
+		a.Inputs[0].Signer = "alice"
+		a.Inputs[1].Signer = "bob"
+		signPartialTx(t, a, 0, key)
+		bb, err := a.Bytes()
+		require.NoError(t, err)
+
+		b, err := bt.NewPartialTxFromBytes(bb)
+		require.NoError(t, err)
+		assert.Equal(t, "bob", b.Inputs[1].Signer)
+		signPartialTx(t, b, 1, msKeys[0])
+
+		c := bt.NewPartialTx(tx)
+		signPartialTx(t, c, 1, msKeys[2])
+
+		require.NoError(t, a.Combine(b, c))
+		assert.Len(t, a.Inputs[1].PartialSigs, 2)
+		assert.Equal(t, "bob", a.Inputs[1].Signer)
+		assert.False(t, a.IsFinalized())
+
+		require.NoError(t, a.Finalize())
+		assert.True(t, a.IsFinalized())
+		assert.Nil(t, a.Inputs[1].PartialSigs)
+
+		signed, err := a.Extract()
+		require.NoError(t, err)
+		assert.Equal(t, expTx.String(), signed.String())
+	})
+
+	t.Run("insufficient signatures", func(t *testing.T) {
+		p := bt.NewPartialTx(newPartialTxTestTx(t, key, msKeys))
+		signPartialTx(t, p, 0, key)
+		signPartialTx(t, p, 1, msKeys[1])
+
+		require.ErrorIs(t, p.Finalize(), bt.ErrPartialTxCannotFinalize)
+		assert.NotNil(t, p.Inputs[0].FinalScript)
+		assert.Nil(t, p.Inputs[1].FinalScript)
+
+		_, err := p.Extract()
+		require.ErrorIs(t, err, bt.ErrPartialTxNotFinalized)
+	})
+
+	t.Run("signed inputs are final", func(t *testing.T) {
+		p := bt.NewPartialTx(expTx)
+		assert.True(t, p.IsFinalized())
+		assert.Empty(t, *p.Tx.Inputs[0].UnlockingScript)
+
+		signed, err := p.Extract()
+		require.NoError(t, err)
+		assert.Equal(t, expTx.String(), signed.String())
+	})
+
+	t.Run("intended sighash flag", func(t *testing.T) {
+		p := bt.NewPartialTx(newPartialTxTestTx(t, key, msKeys))
+		p.Inputs[0].SigHashFlag = sighash.SingleForkID | sighash.AnyOneCanPay
+		signPartialTx(t, p, 0, key)
+
+		sig := p.Inputs[0].PartialSigs[hex.EncodeToString(key.PubKey().Compressed())]
+		assert.Equal(t, byte(sighash.SingleForkID|sighash.AnyOneCanPay), sig[len(sig)-1])
+	})
+
+	t.Run("different txs cannot be combined", func(t *testing.T) {
+		a := bt.NewPartialTx(newPartialTxTestTx(t, key, msKeys))
+		other := newPartialTxTestTx(t, key, msKeys)
+		other.LockTime = 1
+
+		require.ErrorIs(t, a.Combine(bt.NewPartialTx(other)), bt.ErrPartialTxMismatch)
+	})
+}
+
+func TestPartialTx_Bytes(t *testing.T) {
+	t.Parallel()
+
+	key, msKeys := partialTxTestKeys(t)
+
+	newPartialTx := func(t *testing.T) *bt.PartialTx {
+		p := bt.NewPartialTx(newPartialTxTestTx(t, key, msKeys))
+		p.Unknown = map[string][]byte{"f0aa": {0x01}}
+		p.Inputs[0].SigHashFlag = sighash.AllForkID
+		p.Inputs[0].Signer = "alice"
+		p.Inputs[0].Derivations = map[string]string{hex.EncodeToString(key.PubKey().Compressed()): "m/0/1"}
+		p.Inputs[0].Metadata = map[string][]byte{"note": []byte("fee input")}
+		p.Inputs[1].Unknown = map[string][]byte{"2001": {0x02, 0x03}}
+		signPartialTx(t, p, 1, msKeys[0])
+		p.Outputs[0].Derivations = map[string]string{hex.EncodeToString(msKeys[1].PubKey().Compressed()): "m/1/0"}
+		p.Outputs[0].Metadata = map[string][]byte{"label": []byte("change")}
+		p.Outputs[0].Unknown = map[string][]byte{"99": {0x04}}
+		return p
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		p := newPartialTx(t)
+		bb, err := p.Bytes()
+		require.NoError(t, err)
+		assert.Equal(t, bt.PartialTxMagic, bb[:5])
+
+		got, err := bt.NewPartialTxFromBytes(bb)
+		require.NoError(t, err)
+		assertPartialTxEqual(t, p, got)
+
+		gotBB, err := got.Bytes()
+		require.NoError(t, err)
+		assert.Equal(t, bb, gotBB)
+	})
+
+	t.Run("round trip from string", func(t *testing.T) {
+		p := newPartialTx(t)
+		p.Inputs[1].FinalScript = &bscript.Script{}
+		bb, err := p.Bytes()
+		require.NoError(t, err)
+
+		got, err := bt.NewPartialTxFromString(hex.EncodeToString(bb))
+		require.NoError(t, err)
+		assertPartialTxEqual(t, p, got)
+	})
+
+	t.Run("json round trip", func(t *testing.T) {
+		p := newPartialTx(t)
+		p.Inputs[1].FinalScript = &bscript.Script{}
+		bb, err := json.Marshal(p)
+		require.NoError(t, err)
+
+		var got bt.PartialTx
+		require.NoError(t, json.Unmarshal(bb, &got))
+		assertPartialTxEqual(t, p, &got)
+	})
+
+	t.Run("json format", func(t *testing.T) {
+		p := bt.NewPartialTx(newPartialTxTestTx(t, key, msKeys))
+		p.Inputs[0].Signer = "alice"
+		p.Inputs[0].Metadata = map[string][]byte{"note": {0xab}}
+
+		bb, err := json.Marshal(p)
+		require.NoError(t, err)
+
+		var got map[string]interface{}
+		require.NoError(t, json.Unmarshal(bb, &got))
+		assert.Equal(t, hex.EncodeToString(p.Tx.ExtendedBytes()), got["tx"])
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"signer": "alice", "metadata": map[string]interface{}{"note": "ab"}},
+			map[string]interface{}{},
+		}, got["inputs"])
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		p := newPartialTx(t)
+		bb, err := p.Bytes()
+		require.NoError(t, err)
+
+		_, err = bt.NewPartialTxFromBytes(append([]byte{0x70, 0x73, 0x62, 0x74, 0xff}, bb[5:]...))
+		require.ErrorIs(t, err, bt.ErrPartialTxMagic)
+
+		_, err = bt.NewPartialTxFromBytes(append(bb, 0x00))
+		require.ErrorIs(t, err, bt.ErrPartialTxTrailingBytes)
+
+		_, err = bt.NewPartialTxFromBytes(bb[:len(bb)-1])
+		require.Error(t, err)
+
+		_, err = bt.NewPartialTxFromBytes(append(append([]byte{}, bt.PartialTxMagic...), 0x00))
+		require.ErrorIs(t, err, bt.ErrPartialTxNoTx)
+
+		// A duplicate key in the global map.
+		dup := append([]byte{}, bt.PartialTxMagic...)
+		dup = append(dup, 0x02, 0xf0, 0xaa, 0x01, 0x01, 0x02, 0xf0, 0xaa, 0x01, 0x01)
+		_, err = bt.NewPartialTxFromBytes(dup)
+		require.ErrorIs(t, err, bt.ErrPartialTxDuplicateKey)
+
+		p.Inputs[0].PartialSigs = map[string][]byte{"zz": {0x01}}
+		_, err = p.Bytes()
+		require.ErrorIs(t, err, bt.ErrPartialTxInvalidKey)
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		pubKeyHex := hex.EncodeToString(key.PubKey().Compressed())
+		tests := map[string]func(p *bt.PartialTx){
+			"partial signature key not a public key": func(p *bt.PartialTx) {
+				p.Inputs[0].PartialSigs = map[string][]byte{"aabb": {0x01}}
+			},
+			"input derivation key not a public key": func(p *bt.PartialTx) {
+				p.Inputs[0].Derivations = map[string]string{"aabb": "m/0"}
+			},
+			"output derivation key not a public key": func(p *bt.PartialTx) {
+				p.Outputs[0].Derivations = map[string]string{pubKeyHex + "00": "m/0"}
+			},
+			"global unknown key not hex": func(p *bt.PartialTx) {
+				p.Unknown = map[string][]byte{"zz": {0x01}}
+			},
+			"global unknown key with the tx key type": func(p *bt.PartialTx) {
+				p.Unknown = map[string][]byte{"00aa": {0x01}}
+			},
+			"input unknown key with the partial signature key type": func(p *bt.PartialTx) {
+				p.Inputs[0].Unknown = map[string][]byte{"02" + pubKeyHex: {0x01}}
+			},
+			"output unknown key with the metadata key type": func(p *bt.PartialTx) {
+				p.Outputs[0].Unknown = map[string][]byte{"fc6e6f7465": {0x01}}
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				p := newPartialTx(t)
+				test(p)
+
+				_, err := p.Bytes()
+				require.ErrorIs(t, err, bt.ErrPartialTxInvalidKey)
+
+				// MarshalJSON does not check the keys, UnmarshalJSON does.
+				bb, err := json.Marshal(p)
+				require.NoError(t, err)
+				var got bt.PartialTx
+				require.ErrorIs(t, json.Unmarshal(bb, &got), bt.ErrPartialTxInvalidKey)
+			})
+		}
+	})
+
+	t.Run("signed tx rejected", func(t *testing.T) {
+		p := newPartialTx(t)
+		p.Tx.Inputs[0].UnlockingScript = bscript.NewFromBytes([]byte{0x51})
+		bb, err := p.Bytes()
+		require.NoError(t, err)
+
+		_, err = bt.NewPartialTxFromBytes(bb)
+		require.ErrorIs(t, err, bt.ErrPartialTxSigned)
+	})
+}
@@ -0,0 +1,229 @@
+// Package policy checks txs against the standardness rules applied by nodes
+// before relaying a tx or accepting it into their mempool.
+//
+// A tx which is not standard may still be valid by consensus, it is just
+// unlikely to be mined unless it is sent to a miner directly.
+package policy
+
+import (
+	"fmt"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter"
+	"github.com/bsv-blockchain/go-bt/v2/chaincfg"
+)
+
+var parser = &interpreter.DefaultOpcodeParser{}
+
+// Policy is a set of standardness rules to check a tx against. A zero value
+// for any limit, or a nil AllowedScriptTypes, disables that rule.
+type Policy struct {
+	// MaxTxSize is the maximum size of the serialized tx in bytes.
+	MaxTxSize int
+	// DustLimit is the minimum number of satoshis an output may hold.
+	// Data outputs are exempt.
+	DustLimit uint64
+	// MaxDataSize is the maximum size in bytes of the locking script of a
+	// data (OP_RETURN) output.
+	MaxDataSize int
+	// RequirePushOnly requires that unlocking scripts only push data.
+	RequirePushOnly bool
+	// RequireMinimalPush requires that unlocking scripts push data using the
+	// smallest push operator possible.
+	RequireMinimalPush bool
+	// MaxSigOps is the maximum number of signature operations across the
+	// unlocking and locking scripts of the tx.
+	MaxSigOps int
+	// AllowedScriptTypes lists the bscript.ScriptType values permitted for
+	// output locking scripts, for example bscript.ScriptTypePubKeyHash.
+	AllowedScriptTypes []string
+}
+
+// DefaultPolicy returns a Policy with the default rules of a mainnet node.
+func DefaultPolicy() *Policy {
+	return NetworkPolicy(&chaincfg.MainNetParams)
+}
+
+// NetworkPolicy returns a Policy with the default rules of a node on the
+// network with the given params.
+func NetworkPolicy(params *chaincfg.Params) *Policy {
+	return &Policy{
+		MaxTxSize:          params.Policy.MaxTxSize,
+		DustLimit:          params.Policy.DustLimit,
+		RequirePushOnly:    true,
+		RequireMinimalPush: true,
+	}
+}
+
+// Check checks the tx against the policy, returning a violation for every
+// rule which was broken, or nil if the tx is standard. The inputs of a
+// coinbase tx are not checked.
+func (p *Policy) Check(tx *bt.Tx) []Violation {
+	var vv []Violation
+
+	if p.MaxTxSize > 0 {
+		if size := tx.Size(); size > p.MaxTxSize {
+			vv = append(vv, txViolation(
+				ViolationTxSize,
+				"tx size %d exceeds maximum of %d bytes", size, p.MaxTxSize,
+			))
+		}
+	}
+
+	var sigOps int
+	if !tx.IsCoinbase() {
+		for i, in := range tx.Inputs {
+			var n int
+			vv, n = p.checkInput(vv, i, in)
+			sigOps += n
+		}
+	}
+
+	for i, out := range tx.Outputs {
+		var n int
+		vv, n = p.checkOutput(vv, i, out)
+		sigOps += n
+	}
+
+	if p.MaxSigOps > 0 && sigOps > p.MaxSigOps {
+		vv = append(vv, txViolation(
+			ViolationSigOps,
+			"tx has %d sigops, exceeding maximum of %d", sigOps, p.MaxSigOps,
+		))
+	}
+
+	return vv
+}
+
+// IsStandard returns true if the tx does not violate any rule of the policy.
+func (p *Policy) IsStandard(tx *bt.Tx) bool {
+	return len(p.Check(tx)) == 0
+}
+
+// checkInput appends the violations of the input at index idx, returning
+// them along with the number of sigops in its unlocking script.
+func (p *Policy) checkInput(vv []Violation, idx int, in *bt.Input) ([]Violation, int) {
+	if in.UnlockingScript == nil {
+		return vv, 0
+	}
+
+	parsed, err := parser.Parse(in.UnlockingScript)
+	if err != nil {
+		return append(vv, inputViolation(
+			ViolationMalformedScript, idx, "unlocking script is malformed: %s", err,
+		)), 0
+	}
+
+	if p.RequirePushOnly && !parsed.IsPushOnly() {
+		vv = append(vv, inputViolation(ViolationNotPushOnly, idx, "unlocking script is not push only"))
+	}
+
+	if p.RequireMinimalPush {
+		for _, op := range parsed {
+			if err := op.CheckMinimalDataPush(); err != nil {
+				vv = append(vv, inputViolation(ViolationNonMinimalPush, idx, "%s", err))
+				break
+			}
+		}
+	}
+
+	return vv, countSigOps(parsed)
+}
+
+// checkOutput appends the violations of the output at index idx, returning
+// them along with the number of sigops in its locking script.
+func (p *Policy) checkOutput(vv []Violation, idx int, out *bt.Output) ([]Violation, int) {
+	ls := out.LockingScript
+	if ls == nil {
+		ls = &bscript.Script{}
+	}
+
+	if p.AllowedScriptTypes != nil {
+		if st := ls.ScriptType(); !p.isScriptTypeAllowed(st) {
+			vv = append(vv, outputViolation(ViolationScriptType, idx, "script type %s is not allowed", st))
+		}
+	}
+
+	if ls.IsData() {
+		if p.MaxDataSize > 0 && len(*ls) > p.MaxDataSize {
+			vv = append(vv, outputViolation(
+				ViolationDataSize, idx,
+				"data output size %d exceeds maximum of %d bytes", len(*ls), p.MaxDataSize,
+			))
+		}
+
+		return vv, 0
+	}
+
+	if out.Satoshis < p.DustLimit {
+		vv = append(vv, outputViolation(
+			ViolationDust, idx,
+			"output of %d satoshis is below the dust limit of %d", out.Satoshis, p.DustLimit,
+		))
+	}
+
+	parsed, err := parser.Parse(ls)
+	if err != nil {
+		return append(vv, outputViolation(
+			ViolationMalformedScript, idx, "locking script is malformed: %s", err,
+		)), 0
+	}
+
+	return vv, countSigOps(parsed)
+}
+
+func (p *Policy) isScriptTypeAllowed(scriptType string) bool {
+	for _, st := range p.AllowedScriptTypes {
+		if st == scriptType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CountSigOps returns the number of signature operations in the script.
+// OP_CHECKSIG and OP_CHECKSIGVERIFY count as one. OP_CHECKMULTISIG and
+// OP_CHECKMULTISIGVERIFY count as the number of public keys when preceded by
+// OP_1 to OP_16, and as 20 otherwise.
+func CountSigOps(s *bscript.Script) (int, error) {
+	parsed, err := parser.Parse(s)
+	if err != nil {
+		return 0, err
+	}
+
+	return countSigOps(parsed), nil
+}
+
+func countSigOps(parsed interpreter.ParsedScript) int {
+	var n int
+	for i, op := range parsed {
+		switch op.Value() {
+		case bscript.OpCHECKSIG, bscript.OpCHECKSIGVERIFY:
+			n++
+		case bscript.OpCHECKMULTISIG, bscript.OpCHECKMULTISIGVERIFY:
+			if i > 0 {
+				if prev := parsed[i-1].Value(); prev >= bscript.Op1 && prev <= bscript.Op16 {
+					n += int(prev-bscript.Op1) + 1
+					continue
+				}
+			}
+			n += 20
+		}
+	}
+
+	return n
+}
+
+func txViolation(t ViolationType, format string, args ...interface{}) Violation {
+	return Violation{Type: t, InputIdx: -1, OutputIdx: -1, Description: fmt.Sprintf(format, args...)}
+}
+
+func inputViolation(t ViolationType, idx int, format string, args ...interface{}) Violation {
+	return Violation{Type: t, InputIdx: idx, OutputIdx: -1, Description: fmt.Sprintf(format, args...)}
+}
+
+func outputViolation(t ViolationType, idx int, format string, args ...interface{}) Violation {
+	return Violation{Type: t, InputIdx: -1, OutputIdx: idx, Description: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,246 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/policy"
+)
+
+const p2pkhTx = "010000000193a35408b6068499e0d5abd799d3e827d9bfe70c9b75ebe209c91d2507232651000000006b483045022100c1d77036dc6cd1f3fa1214b0688391ab7f7a16cd31ea4e5a1f7a415ef167df820220751aced6d24649fa235132f1e6969e163b9400f80043a72879237dab4a1190ad412103b8b40a84123121d260f5c109bc5a46ec819c2e4002e5ba08638783bfb4e01435ffffffff02404b4c00000000001976a91404ff367be719efa79d76e4416ffb072cd53b208888acde94a905000000001976a91404d03f746652cfcb6cb55119ab473a045137d26588ac00000000"
+
+func newTx(t *testing.T) *bt.Tx {
+	t.Helper()
+
+	tx, err := bt.NewTxFromString(p2pkhTx)
+	require.NoError(t, err)
+
+	return tx
+}
+
+func scriptFromASM(t *testing.T, asm string) *bscript.Script {
+	t.Helper()
+
+	s, err := bscript.NewFromASM(asm)
+	require.NoError(t, err)
+
+	return s
+}
+
+func TestPolicy_Check(t *testing.T) {
+	t.Parallel()
+
+	t.Run("standard tx has no violations", func(t *testing.T) {
+		tx := newTx(t)
+		assert.Nil(t, policy.DefaultPolicy().Check(tx))
+		assert.True(t, policy.DefaultPolicy().IsStandard(tx))
+	})
+
+	t.Run("zero policy has no violations", func(t *testing.T) {
+		tx := newTx(t)
+		tx.Outputs[0].Satoshis = 0
+		tx.Inputs[0].UnlockingScript = scriptFromASM(t, "OP_DUP OP_DROP")
+
+		assert.Nil(t, (&policy.Policy{}).Check(tx))
+	})
+
+	t.Run("tx too large", func(t *testing.T) {
+		tx := newTx(t)
+		p := &policy.Policy{MaxTxSize: 100}
+
+		vv := p.Check(tx)
+		require.Len(t, vv, 1)
+		assert.Equal(t, policy.ViolationTxSize, vv[0].Type)
+		assert.Equal(t, -1, vv[0].InputIdx)
+		assert.Equal(t, -1, vv[0].OutputIdx)
+		assert.Equal(t, "tx size 226 exceeds maximum of 100 bytes", vv[0].Error())
+	})
+
+	t.Run("dust outputs are reported by index", func(t *testing.T) {
+		tx := newTx(t)
+		tx.Outputs[1].Satoshis = 545
+		p := &policy.Policy{DustLimit: 546}
+
+		vv := p.Check(tx)
+		require.Len(t, vv, 1)
+		assert.Equal(t, policy.ViolationDust, vv[0].Type)
+		assert.Equal(t, -1, vv[0].InputIdx)
+		assert.Equal(t, 1, vv[0].OutputIdx)
+		assert.Equal(t, "output 1: output of 545 satoshis is below the dust limit of 546", vv[0].Error())
+	})
+
+	t.Run("data outputs are exempt from dust", func(t *testing.T) {
+		tx := newTx(t)
+		require.NoError(t, tx.AddOpReturnOutput([]byte("hello")))
+		p := &policy.Policy{DustLimit: 1}
+
+		assert.Nil(t, p.Check(tx))
+	})
+
+	t.Run("data output too large", func(t *testing.T) {
+		tx := newTx(t)
+		require.NoError(t, tx.AddOpReturnOutput(make([]byte, 100)))
+		p := &policy.Policy{MaxDataSize: 50}
+
+		vv := p.Check(tx)
+		require.Len(t, vv, 1)
+		assert.Equal(t, policy.ViolationDataSize, vv[0].Type)
+		assert.Equal(t, 2, vv[0].OutputIdx)
+	})
+
+	t.Run("unlocking script not push only", func(t *testing.T) {
+		tx := newTx(t)
+		tx.Inputs[0].UnlockingScript = scriptFromASM(t, "OP_1 OP_DUP")
+
+		vv := policy.DefaultPolicy().Check(tx)
+		require.Len(t, vv, 1)
+		assert.Equal(t, policy.ViolationNotPushOnly, vv[0].Type)
+		assert.Equal(t, 0, vv[0].InputIdx)
+		assert.Equal(t, -1, vv[0].OutputIdx)
+	})
+
+	t.Run("unlocking script with non minimal push", func(t *testing.T) {
+		tx := newTx(t)
+		// OP_PUSHDATA1 of a single byte, which should use OP_DATA_1.
+		s := bscript.Script{bscript.OpPUSHDATA1, 0x01, 0xff}
+		tx.Inputs[0].UnlockingScript = &s
+
+		vv := policy.DefaultPolicy().Check(tx)
+		require.Len(t, vv, 1)
+		assert.Equal(t, policy.ViolationNonMinimalPush, vv[0].Type)
+		assert.Equal(t, 0, vv[0].InputIdx)
+	})
+
+	t.Run("malformed unlocking script", func(t *testing.T) {
+		tx := newTx(t)
+		s := bscript.Script{bscript.OpPUSHDATA1, 0x05, 0xff}
+		tx.Inputs[0].UnlockingScript = &s
+
+		vv := policy.DefaultPolicy().Check(tx)
+		require.Len(t, vv, 1)
+		assert.Equal(t, policy.ViolationMalformedScript, vv[0].Type)
+		assert.Equal(t, 0, vv[0].InputIdx)
+	})
+
+	t.Run("malformed locking script", func(t *testing.T) {
+		tx := newTx(t)
+		s := bscript.Script{bscript.OpPUSHDATA1, 0x05, 0xff}
+		tx.Outputs[1].LockingScript = &s
+
+		vv := policy.DefaultPolicy().Check(tx)
+		require.Len(t, vv, 1)
+		assert.Equal(t, policy.ViolationMalformedScript, vv[0].Type)
+		assert.Equal(t, 1, vv[0].OutputIdx)
+	})
+
+	t.Run("too many sigops", func(t *testing.T) {
+		tx := newTx(t)
+		tx.Outputs[0].LockingScript = scriptFromASM(t, "OP_CHECKMULTISIG")
+		p := &policy.Policy{MaxSigOps: 20}
+
+		vv := p.Check(tx)
+		require.Len(t, vv, 1)
+		assert.Equal(t, policy.ViolationSigOps, vv[0].Type)
+		assert.Equal(t, "tx has 21 sigops, exceeding maximum of 20", vv[0].Description)
+	})
+
+	t.Run("script type not allowed", func(t *testing.T) {
+		tx := newTx(t)
+		require.NoError(t, tx.AddOpReturnOutput([]byte("hello")))
+		p := &policy.Policy{AllowedScriptTypes: []string{bscript.ScriptTypePubKeyHash}}
+
+		vv := p.Check(tx)
+		require.Len(t, vv, 1)
+		assert.Equal(t, policy.ViolationScriptType, vv[0].Type)
+		assert.Equal(t, 2, vv[0].OutputIdx)
+		assert.Equal(t, "output 2: script type nulldata is not allowed", vv[0].Error())
+	})
+
+	t.Run("all violations are reported", func(t *testing.T) {
+		tx := newTx(t)
+		tx.Inputs[0].UnlockingScript = scriptFromASM(t, "OP_1 OP_DUP")
+		tx.Outputs[0].Satoshis = 0
+		tx.Outputs[1].Satoshis = 0
+
+		vv := policy.DefaultPolicy().Check(tx)
+		require.Len(t, vv, 3)
+		assert.Equal(t, policy.ViolationNotPushOnly, vv[0].Type)
+		assert.Equal(t, policy.ViolationDust, vv[1].Type)
+		assert.Equal(t, 0, vv[1].OutputIdx)
+		assert.Equal(t, policy.ViolationDust, vv[2].Type)
+		assert.Equal(t, 1, vv[2].OutputIdx)
+		assert.False(t, policy.DefaultPolicy().IsStandard(tx))
+	})
+
+	t.Run("coinbase inputs are not checked", func(t *testing.T) {
+		tx := bt.NewTx()
+		require.NoError(t, tx.From(
+			"0000000000000000000000000000000000000000000000000000000000000000",
+			0xffffffff,
+			"",
+			0,
+		))
+		tx.Inputs[0].UnlockingScript = scriptFromASM(t, "OP_1 OP_DUP")
+		require.True(t, tx.IsCoinbase())
+
+		assert.Nil(t, policy.DefaultPolicy().Check(tx))
+	})
+}
+
+func TestCountSigOps(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		asm    string
+		expNum int
+	}{
+		"p2pkh": {
+			asm:    "OP_DUP OP_HASH160 04ff367be719efa79d76e4416ffb072cd53b2088 OP_EQUALVERIFY OP_CHECKSIG",
+			expNum: 1,
+		},
+		"checksigverify": {
+			asm:    "OP_CHECKSIGVERIFY OP_CHECKSIG",
+			expNum: 2,
+		},
+		"multisig with key count": {
+			asm:    "OP_1 OP_3 OP_CHECKMULTISIG",
+			expNum: 3,
+		},
+		"multisig verify with key count": {
+			asm:    "OP_2 OP_16 OP_CHECKMULTISIGVERIFY",
+			expNum: 16,
+		},
+		"multisig without key count": {
+			asm:    "OP_CHECKMULTISIG",
+			expNum: 20,
+		},
+		"no sigops": {
+			asm:    "OP_1 OP_DUP",
+			expNum: 0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			n, err := policy.CountSigOps(scriptFromASM(t, test.asm))
+			require.NoError(t, err)
+			assert.Equal(t, test.expNum, n)
+		})
+	}
+
+	t.Run("malformed script", func(t *testing.T) {
+		_, err := policy.CountSigOps(&bscript.Script{bscript.OpPUSHDATA1, 0x05})
+		require.Error(t, err)
+	})
+}
+
+func TestViolationType_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "ViolationDust", policy.ViolationDust.String())
+	assert.Equal(t, "Unknown ViolationType (100)", policy.ViolationType(100).String())
+}
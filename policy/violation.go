@@ -0,0 +1,82 @@
+package policy
+
+import "fmt"
+
+// ViolationType identifies a kind of policy violation.
+type ViolationType int
+
+// These constants are used to identify a specific Violation.
+const (
+	// ViolationTxSize is reported when the tx exceeds Policy.MaxTxSize.
+	ViolationTxSize ViolationType = iota
+
+	// ViolationDust is reported when an output holds fewer satoshis than
+	// Policy.DustLimit.
+	ViolationDust
+
+	// ViolationDataSize is reported when a data output exceeds
+	// Policy.MaxDataSize.
+	ViolationDataSize
+
+	// ViolationNotPushOnly is reported when an unlocking script contains an
+	// op which is not a push.
+	ViolationNotPushOnly
+
+	// ViolationNonMinimalPush is reported when an unlocking script pushes data
+	// without using the smallest push operator possible.
+	ViolationNonMinimalPush
+
+	// ViolationSigOps is reported when the tx exceeds Policy.MaxSigOps.
+	ViolationSigOps
+
+	// ViolationScriptType is reported when the type of an output locking
+	// script is not in Policy.AllowedScriptTypes.
+	ViolationScriptType
+
+	// ViolationMalformedScript is reported when a script cannot be parsed.
+	ViolationMalformedScript
+)
+
+var violationTypeStrings = map[ViolationType]string{
+	ViolationTxSize:          "ViolationTxSize",
+	ViolationDust:            "ViolationDust",
+	ViolationDataSize:        "ViolationDataSize",
+	ViolationNotPushOnly:     "ViolationNotPushOnly",
+	ViolationNonMinimalPush:  "ViolationNonMinimalPush",
+	ViolationSigOps:          "ViolationSigOps",
+	ViolationScriptType:      "ViolationScriptType",
+	ViolationMalformedScript: "ViolationMalformedScript",
+}
+
+// String returns the ViolationType as a human-readable name.
+func (v ViolationType) String() string {
+	if s := violationTypeStrings[v]; s != "" {
+		return s
+	}
+	return fmt.Sprintf("Unknown ViolationType (%d)", int(v))
+}
+
+// Violation is a single policy rule broken by a tx.
+type Violation struct {
+	Type ViolationType
+	// InputIdx is the index of the offending input, or -1 if the violation
+	// does not relate to an input.
+	InputIdx int
+	// OutputIdx is the index of the offending output, or -1 if the violation
+	// does not relate to an output.
+	OutputIdx int
+	// Description is a human-readable description of the violation.
+	Description string
+}
+
+// Error satisfies the error interface and prints a human-readable violation.
+func (v Violation) Error() string {
+	switch {
+	case v.InputIdx >= 0:
+		return fmt.Sprintf("input %d: %s", v.InputIdx, v.Description)
+	case v.OutputIdx >= 0:
+		return fmt.Sprintf("output %d: %s", v.OutputIdx, v.Description)
+	default:
+		return v.Description
+	}
+}
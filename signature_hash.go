@@ -2,6 +2,9 @@ package bt
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding"
+	"sync"
 
 	crypto "github.com/bsv-blockchain/go-sdk/primitives/hash"
 
@@ -16,14 +19,49 @@ type sigHashFunc func(inputIdx uint32, shf sighash.Flag) ([]byte, error)
 
 // SigHashCache holds pre-computed intermediate hash values used during
 // signature hashing. When signing multiple inputs of the same transaction,
-// these hashes are identical across inputs (for the common SighashAll case)
-// and can be computed once instead of O(N) times.
+// these hashes are identical across inputs and can be computed once instead
+// of O(N) times.
 //
-// Create with tx.NewSigHashCache() and pass to CalcInputPreimageWithCache.
+// The hashes of each output, used by SighashSingle, and the serialization of
+// the tx used by the legacy (pre-fork) algorithm are computed on first use.
+// A cache is safe for concurrent use, but must only be used with the tx it
+// was created from, and the tx must not be modified while it is in use.
+//
+// Create with tx.NewSigHashCache() and pass to CalcInputPreimageWithCache or
+// CalcInputSignatureHashWithCache.
 type SigHashCache struct {
 	PrevOutHash  []byte
 	SequenceHash []byte
 	OutputsHash  []byte
+
+	outputHashesOnce sync.Once
+	outputHashes     [][]byte
+
+	legacyOnce sync.Once
+	legacy     *legacySigHashCache
+	legacyErr  error
+}
+
+// legacySigHashCache holds the parts of the legacy preimage shared by every
+// input. The inputs are serialized with empty scripts, as every input other
+// than the one being signed is.
+type legacySigHashCache struct {
+	// inputs are the serialized inputs.
+	inputs []byte
+	// inputsNoSeq are the serialized inputs with zero sequence numbers, as
+	// signed by SighashNone and SighashSingle.
+	inputsNoSeq []byte
+	// inputEnds are the offsets of the end of each input in inputs and
+	// inputsNoSeq.
+	inputEnds []int
+	// outputs are the serialized outputs.
+	outputs []byte
+	// outputEnds are the offsets of the end of each output in outputs.
+	outputEnds []int
+	// midstates are the marshaled sha256 states having hashed the version,
+	// the input count and the inputs before each input, as signed by
+	// SighashAll.
+	midstates [][]byte
 }
 
 // NewSigHashCache pre-computes the intermediate hashes for the modern
@@ -37,6 +75,67 @@ func (tx *Tx) NewSigHashCache() *SigHashCache {
 	}
 }
 
+// outputHash returns the hash of the output at index n, as signed by
+// SighashSingle.
+func (c *SigHashCache) outputHash(tx *Tx, n int) []byte {
+	c.outputHashesOnce.Do(func() {
+		c.outputHashes = make([][]byte, len(tx.Outputs))
+		for i := range tx.Outputs {
+			c.outputHashes[i] = tx.OutputsHash(int32(i))
+		}
+	})
+
+	return c.outputHashes[n]
+}
+
+// legacyCache returns the cached parts of the legacy preimage of tx.
+func (c *SigHashCache) legacyCache(tx *Tx) (*legacySigHashCache, error) {
+	c.legacyOnce.Do(func() {
+		c.legacy, c.legacyErr = tx.newLegacySigHashCache()
+	})
+
+	return c.legacy, c.legacyErr
+}
+
+// newLegacySigHashCache serializes the inputs and outputs of tx and hashes
+// the midstate before each input.
+func (tx *Tx) newLegacySigHashCache() (*legacySigHashCache, error) {
+	lc := &legacySigHashCache{
+		inputEnds:  make([]int, len(tx.Inputs)),
+		outputEnds: make([]int, len(tx.Outputs)),
+		midstates:  make([][]byte, len(tx.Inputs)),
+	}
+
+	for i, in := range tx.Inputs {
+		lc.inputs = appendLegacyInput(lc.inputs, in, nil, in.SequenceNumber)
+		lc.inputsNoSeq = appendLegacyInput(lc.inputsNoSeq, in, nil, 0)
+		lc.inputEnds[i] = len(lc.inputs)
+	}
+	for i, out := range tx.Outputs {
+		lc.outputs = out.appendTo(lc.outputs)
+		lc.outputEnds[i] = len(lc.outputs)
+	}
+
+	h := sha256.New()
+	m, ok := h.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, ErrSigHashMidstate
+	}
+	_, _ = h.Write(tx.legacyPreimagePrefix(len(tx.Inputs)))
+	start := 0
+	for i, end := range lc.inputEnds {
+		state, err := m.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		lc.midstates[i] = state
+		_, _ = h.Write(lc.inputs[start:end])
+		start = end
+	}
+
+	return lc, nil
+}
+
 // sigStrat will decide which tx serialization to use.
 // The legacy serialization will be used for txs pre-fork
 // whereas the new serialization will be used for post-fork
@@ -52,6 +151,10 @@ func (tx *Tx) sigStrat(shf sighash.Flag) sigHashFunc {
 // to be signed. BitCoin (SV) uses a different signature hashing algorithm
 // after the UAHF fork for replay protection.
 //
+// When signing multiple inputs, use CalcInputSignatureHashWithCache with the
+// PreviousTxScript of each input and the same SigHashCache to avoid hashing
+// the whole tx for every input.
+//
 // see https://github.com/bitcoin-sv/bitcoin-sv/blob/master/doc/abc/replay-protected-sighash.md#digest-algorithm
 func (tx *Tx) CalcInputSignatureHash(inputNumber uint32, sigHashFlag sighash.Flag) ([]byte, error) {
 	sigHashFn := tx.sigStrat(sigHashFlag)
@@ -119,6 +222,54 @@ func (tx *Tx) CalcInputPreimageWithCache(inputNumber uint32, sigHashFlag sighash
 	if in == nil {
 		return nil, ErrInputNoExist
 	}
+
+	return tx.calcInputPreimageWithCache(in, inputNumber, sigHashFlag, cache)
+}
+
+// CalcInputSignatureHashWithCache is like CalcInputSignatureHash, but signs
+// scriptCode in place of the PreviousTxScript of the input, using the
+// pre-computed hashes of a SigHashCache. This is how a signature is checked
+// when executing a script, where the script code is the part of the locking
+// script following the last OP_CODESEPARATOR.
+//
+// The tx is not modified, so the cache may be shared by concurrent checks of
+// different inputs. A nil cache computes the hashes on each call.
+func (tx *Tx) CalcInputSignatureHashWithCache(inputNumber uint32, sigHashFlag sighash.Flag,
+	scriptCode *bscript.Script, cache *SigHashCache,
+) ([]byte, error) {
+	in := tx.InputIdx(int(inputNumber))
+	if in == nil {
+		return nil, ErrInputNoExist
+	}
+
+	if !sigHashFlag.Has(sighash.ForkID) && cache == nil {
+		txCopy := tx.ShallowClone()
+		txCopy.Inputs[inputNumber].PreviousTxScript = scriptCode
+		return txCopy.CalcInputSignatureHash(inputNumber, sigHashFlag)
+	}
+
+	inCopy := *in
+	inCopy.PreviousTxScript = scriptCode
+
+	if !sigHashFlag.Has(sighash.ForkID) {
+		return tx.calcLegacySignatureHashWithCache(&inCopy, inputNumber, sigHashFlag, cache)
+	}
+
+	if cache == nil {
+		cache = tx.NewSigHashCache()
+	}
+
+	buf, err := tx.calcInputPreimageWithCache(&inCopy, inputNumber, sigHashFlag, cache)
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.Sha256d(buf), nil
+}
+
+func (tx *Tx) calcInputPreimageWithCache(in *Input, inputNumber uint32, sigHashFlag sighash.Flag,
+	cache *SigHashCache,
+) ([]byte, error) {
 	if in.previousTxIDHash == nil {
 		return nil, ErrEmptyPreviousTxID
 	}
@@ -139,7 +290,7 @@ func (tx *Tx) CalcInputPreimageWithCache(inputNumber uint32, sigHashFlag sighash
 	if (sigHashFlag&31) != sighash.Single && (sigHashFlag&31) != sighash.None {
 		hashOutputs = cache.OutputsHash
 	} else if (sigHashFlag&31) == sighash.Single && inputNumber < uint32(tx.OutputCount()) {
-		hashOutputs = tx.OutputsHash(int32(inputNumber))
+		hashOutputs = cache.outputHash(tx, int(inputNumber))
 	}
 
 	return tx.calcInputPreimage(in, sigHashFlag, hashPreviousOuts, hashSequence, hashOutputs)
@@ -370,6 +521,134 @@ func (tx *Tx) CalcInputPreimageLegacy(inputNumber uint32, shf sighash.Flag) ([]b
 	return buf, nil
 }
 
+// calcLegacySignatureHashWithCache returns the legacy signature hash of the
+// input, hashing the parts of the preimage shared by every input from the
+// cache rather than serializing a copy of the tx. For SighashAll, hashing
+// resumes from the midstate having hashed the inputs before the input.
+func (tx *Tx) calcLegacySignatureHashWithCache(in *Input, inputNumber uint32, shf sighash.Flag,
+	cache *SigHashCache,
+) ([]byte, error) {
+	if in.previousTxIDHash == nil {
+		return nil, ErrEmptyPreviousTxID
+	}
+	if in.PreviousTxScript == nil {
+		return nil, ErrEmptyPreviousTxScript
+	}
+
+	// See CalcInputPreimageLegacy.
+	if shf.HasWithMask(sighash.Single) && int(inputNumber) > len(tx.Outputs)-1 {
+		return defaultHex, nil
+	}
+
+	lc, err := cache.legacyCache(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	noSeq := shf.HasWithMask(sighash.None) || shf.HasWithMask(sighash.Single)
+	start := 0
+	if inputNumber > 0 {
+		start = lc.inputEnds[inputNumber-1]
+	}
+	end := lc.inputEnds[inputNumber]
+
+	h := sha256.New()
+	switch {
+	case shf&sighash.AnyOneCanPay != 0:
+		_, _ = h.Write(tx.legacyPreimagePrefix(1))
+	case noSeq:
+		_, _ = h.Write(tx.legacyPreimagePrefix(len(tx.Inputs)))
+		_, _ = h.Write(lc.inputsNoSeq[:start])
+	default:
+		u, ok := h.(encoding.BinaryUnmarshaler)
+		if !ok {
+			return nil, ErrSigHashMidstate
+		}
+		if err = u.UnmarshalBinary(lc.midstates[inputNumber]); err != nil {
+			return nil, err
+		}
+	}
+
+	_, _ = h.Write(appendLegacyInput(nil, in, in.PreviousTxScript, in.SequenceNumber))
+
+	if shf&sighash.AnyOneCanPay == 0 {
+		if noSeq {
+			_, _ = h.Write(lc.inputsNoSeq[end:])
+		} else {
+			_, _ = h.Write(lc.inputs[end:])
+		}
+	}
+
+	switch {
+	case shf.HasWithMask(sighash.None):
+		_, _ = h.Write(VarInt(0).Bytes())
+	case shf.HasWithMask(sighash.Single):
+		_, _ = h.Write(VarInt(uint64(inputNumber) + 1).Bytes())
+		// The outputs before the output signed are blanked, with a value of
+		// -1 and an empty script.
+		blank := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x00}
+		for i := uint32(0); i < inputNumber; i++ {
+			_, _ = h.Write(blank)
+		}
+		outStart := 0
+		if inputNumber > 0 {
+			outStart = lc.outputEnds[inputNumber-1]
+		}
+		_, _ = h.Write(lc.outputs[outStart:lc.outputEnds[inputNumber]])
+	default:
+		_, _ = h.Write(VarInt(uint64(len(tx.Outputs))).Bytes())
+		_, _ = h.Write(lc.outputs)
+	}
+
+	s := uint32(shf)
+	_, _ = h.Write([]byte{
+		byte(tx.LockTime), byte(tx.LockTime >> 8),
+		byte(tx.LockTime >> 16), byte(tx.LockTime >> 24),
+		byte(s), byte(s >> 8),
+		byte(s >> 16), byte(s >> 24),
+	})
+
+	first := h.Sum(nil)
+	second := sha256.Sum256(first)
+	return second[:], nil
+}
+
+// legacyPreimagePrefix returns the version and input count of the legacy
+// preimage.
+func (tx *Tx) legacyPreimagePrefix(inputCount int) []byte {
+	buf := []byte{
+		byte(tx.Version), byte(tx.Version >> 8),
+		byte(tx.Version >> 16), byte(tx.Version >> 24),
+	}
+	return VarInt(uint64(inputCount)).AppendTo(buf)
+}
+
+// appendLegacyInput appends the input as serialized in the legacy preimage,
+// with the given script and sequence number. The script is empty if nil.
+func appendLegacyInput(buf []byte, in *Input, script *bscript.Script, seq uint32) []byte {
+	if in.previousTxIDHash != nil {
+		buf = append(buf, in.previousTxIDHash[:]...)
+	}
+	buf = append(
+		buf,
+		byte(in.PreviousTxOutIndex), byte(in.PreviousTxOutIndex>>8),
+		byte(in.PreviousTxOutIndex>>16), byte(in.PreviousTxOutIndex>>24),
+	)
+
+	if script == nil {
+		buf = append(buf, 0x00)
+	} else {
+		buf = VarInt(uint64(len(*script))).AppendTo(buf)
+		buf = append(buf, *script...)
+	}
+
+	return append(
+		buf,
+		byte(seq), byte(seq>>8),
+		byte(seq>>16), byte(seq>>24),
+	)
+}
+
 // OutputsHash returns a bytes slice of the requested output, used for generating
 // the txs signature hash. If n is -1, it will create the byte slice from all outputs.
 func (tx *Tx) OutputsHash(n int32) []byte {
@@ -66,6 +66,15 @@ func BenchmarkCalcInputPreimageLegacy(b *testing.B) {
 				}
 			}
 		})
+
+		b.Run(fmt.Sprintf("AllInputs_%d_cached", nInputs), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				cache := tx.NewSigHashCache()
+				for j := 0; j < nInputs; j++ {
+					_, _ = tx.CalcInputSignatureHashWithCache(uint32(j), sighash.All, tx.Inputs[j].PreviousTxScript, cache)
+				}
+			}
+		})
 	}
 }
 
@@ -88,6 +97,44 @@ func TestCalcInputPreimageWithCache_MatchesUncached(t *testing.T) {
 	}
 }
 
+// TestCalcInputSignatureHash_CacheMatchesUncached verifies the cached path
+// produces identical signature hashes to the uncached path for every sighash
+// flag, for both the modern and legacy algorithms.
+func TestCalcInputSignatureHash_CacheMatchesUncached(t *testing.T) {
+	tx := buildSigningTx(t, 5)
+	for i, in := range tx.Inputs {
+		in.SequenceNumber = uint32(i)
+	}
+	tx.AddOutput(&bt.Output{
+		Satoshis:      1000,
+		LockingScript: bscript.NewFromBytes([]byte{0x51}),
+	})
+	tx.AddOutput(&bt.Output{
+		Satoshis:      0,
+		LockingScript: bscript.NewFromBytes([]byte{0x00, 0x6a, 0x01, 0x02}),
+	})
+	cache := tx.NewSigHashCache()
+
+	for _, base := range []sighash.Flag{sighash.All, sighash.None, sighash.Single} {
+		for _, acp := range []sighash.Flag{0, sighash.AnyOneCanPay} {
+			for _, forkID := range []sighash.Flag{0, sighash.ForkID} {
+				flag := base | acp | forkID
+				t.Run(flag.String(), func(t *testing.T) {
+					for j := range tx.Inputs {
+						expected, err := tx.CalcInputSignatureHash(uint32(j), flag)
+						require.NoError(t, err)
+
+						got, err := tx.CalcInputSignatureHashWithCache(uint32(j), flag, tx.Inputs[j].PreviousTxScript, cache)
+						require.NoError(t, err)
+
+						require.Equal(t, expected, got, "input %d: cached signature hash mismatch", j)
+					}
+				})
+			}
+		}
+	}
+}
+
 // TestOutputsHashOptimized verifies OutputsHash still returns correct results after optimization.
 func TestOutputsHashOptimized(t *testing.T) {
 	tx := buildSigningTx(t, 3)
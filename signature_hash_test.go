@@ -161,6 +161,15 @@ func TestTx_CalcInputSignatureHash(t *testing.T) {
 			actualSigHash, err = tx.CalcInputSignatureHash(test.index, test.sigHashType)
 			require.NoError(t, err)
 			assert.Equal(t, test.expectedSigHash, hex.EncodeToString(actualSigHash))
+
+			// The script code is signed in place of the previous tx script.
+			scriptCode := tx.Inputs[test.index].PreviousTxScript
+			tx.Inputs[test.index].PreviousTxScript = &bscript.Script{}
+			for _, cache := range []*bt.SigHashCache{nil, tx.NewSigHashCache()} {
+				actualSigHash, err = tx.CalcInputSignatureHashWithCache(test.index, test.sigHashType, scriptCode, cache)
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedSigHash, hex.EncodeToString(actualSigHash))
+			}
 		})
 	}
 }
@@ -803,9 +803,10 @@ func (tx *Tx) SizeWithTypes() *TxSize {
 	}
 }
 
-// EstimateSize will return the size of tx in bytes and will add 107 bytes
-// to the unlocking script of any unsigned inputs (only P2PKH for now) found
-// to give a final size estimate of the tx size.
+// EstimateSize will return the size of tx in bytes and will add the estimated
+// length of the unlocking script of any unsigned inputs found to give a final
+// size estimate of the tx size. See RegisterUnlockingScriptEstimator for the
+// supported locking scripts.
 func (tx *Tx) EstimateSize() (int, error) {
 	tempTx, err := tx.estimatedFinalTx()
 	if err != nil {
@@ -816,8 +817,8 @@ func (tx *Tx) EstimateSize() (int, error) {
 }
 
 // EstimateSizeWithTypes will return the size of tx in bytes, including the
-// different data types (std/data/etc.), and will add 107 bytes to the unlocking
-// script of any unsigned inputs (only P2PKH for now) found to give a final size
+// different data types (std/data/etc.), and will add the estimated length of
+// the unlocking script of any unsigned inputs found to give a final size
 // estimate of the tx size.
 func (tx *Tx) EstimateSizeWithTypes() (*TxSize, error) {
 	tempTx, err := tx.estimatedFinalTx()
@@ -828,22 +829,24 @@ func (tx *Tx) EstimateSizeWithTypes() (*TxSize, error) {
 	return tempTx.SizeWithTypes(), nil
 }
 
-// estimatedFinalTx will return a clone of the tx with all inputs
+// estimatedFinalTx will return a clone of the tx with all unsigned inputs
+// given a placeholder unlocking script of the estimated length.
 func (tx *Tx) estimatedFinalTx() (*Tx, error) {
 	tempTx := tx.Clone()
 
 	for i, in := range tempTx.Inputs {
+		if in.UnlockingScript != nil && len(*in.UnlockingScript) > 0 {
+			continue
+		}
 		if in.PreviousTxScript == nil {
 			return nil, fmt.Errorf("%w at index %d in order to calc expected UnlockingScript", ErrEmptyPreviousTxScript, i)
 		}
-		if !in.PreviousTxScript.IsP2PKH() && !in.PreviousTxScript.IsP2PKHInscription() {
-			return nil, ErrUnsupportedScript
-		}
-		if in.UnlockingScript == nil || len(*in.UnlockingScript) == 0 {
-			//nolint:lll // insert dummy p2pkh unlocking script (sig + pubkey)
-			dummyUnlockingScript, _ := hex.DecodeString("4830450221009c13cbcbb16f2cfedc7abf3a4af1c3fe77df1180c0e7eee30d9bcc53ebda39da02207b258005f1bc3cf9dffa06edb358d6db2bcfc87f50516fac8e3f4686fc2a03df412103107feff22788a1fc8357240bf450fd7bca4bd45d5f8bac63818c5a7b67b03876")
-			in.UnlockingScript = bscript.NewFromBytes(dummyUnlockingScript)
+		e, ok := unlockingScriptEstimatorFor(in.PreviousTxScript)
+		if !ok {
+			return nil, fmt.Errorf("%w at index %d", ErrUnsupportedScript, i)
 		}
+		dummyUnlockingScript := make(bscript.Script, e.EstimateLength(tx, uint32(i)))
+		in.UnlockingScript = &dummyUnlockingScript
 	}
 	return tempTx, nil
 }
@@ -879,9 +882,9 @@ func (tx *Tx) IsFeePaidEnough(fees *FeeQuote) (bool, error) {
 }
 
 // EstimateIsFeePaidEnough will calculate the fees that this transaction is paying
-// including the individual fee types (std/data/etc.), and will add 107 bytes to the unlocking
-// script of any unsigned inputs (only P2PKH for now) found to give a final size
-// estimate of the tx size for fee calculation.
+// including the individual fee types (std/data/etc.), and will add the estimated length of
+// the unlocking script of any unsigned inputs found to give a final size estimate of the
+// tx size for fee calculation.
 func (tx *Tx) EstimateIsFeePaidEnough(fees *FeeQuote) (bool, error) {
 	tempTx, err := tx.estimatedFinalTx()
 	if err != nil {
@@ -933,8 +936,10 @@ func (tx *Tx) feesPaid(size *TxSize, fees *FeeQuote) (*TxFees, error) {
 	return txFees, nil
 }
 
-// estimateDeficit estimates the deficit of the transaction
-func (tx *Tx) estimateDeficit(fees *FeeQuote) (uint64, error) {
+// EstimateDeficit estimates the number of satoshis which must be added to the
+// inputs of the tx to cover its outputs and the estimated fees, returning 0
+// when the inputs already cover them.
+func (tx *Tx) EstimateDeficit(fees *FeeQuote) (uint64, error) {
 	totalInputSatoshis := tx.TotalInputSatoshis()
 	totalOutputSatoshis := tx.TotalOutputSatoshis()
 
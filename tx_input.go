@@ -7,6 +7,7 @@ import (
 
 	crypto "github.com/bsv-blockchain/go-sdk/primitives/hash"
 	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/bsv-blockchain/go-bt/v2/bscript"
 	"github.com/bsv-blockchain/go-bt/v2/chainhash"
@@ -137,7 +138,7 @@ func (tx *Tx) FromUTXOs(utxos ...*UTXO) error {
 //	    return err
 //	}
 func (tx *Tx) Fund(ctx context.Context, fq *FeeQuote, next UTXOGetterFunc) error {
-	deficit, err := tx.estimateDeficit(fq)
+	deficit, err := tx.EstimateDeficit(fq)
 	if err != nil {
 		return err
 	}
@@ -155,7 +156,7 @@ func (tx *Tx) Fund(ctx context.Context, fq *FeeQuote, next UTXOGetterFunc) error
 			return err
 		}
 
-		deficit, err = tx.estimateDeficit(fq)
+		deficit, err = tx.EstimateDeficit(fq)
 		if err != nil {
 			return err
 		}
@@ -220,15 +221,7 @@ func (tx *Tx) InsertInputUnlockingScript(index uint32, s *bscript.Script) error
 // for example local or external unlocking (hardware wallet), or
 // signature / non-signature based.
 func (tx *Tx) FillInput(ctx context.Context, unlocker Unlocker, params UnlockerParams) error {
-	if unlocker == nil {
-		return ErrNoUnlocker
-	}
-
-	if params.SigHashFlags == 0 {
-		params.SigHashFlags = sighash.AllForkID
-	}
-
-	unlockingScript, err := unlocker.UnlockingScript(ctx, tx, params)
+	unlockingScript, err := tx.unlockingScript(ctx, unlocker, params)
 	if err != nil {
 		return err
 	}
@@ -243,19 +236,101 @@ func (tx *Tx) FillInput(ctx context.Context, unlocker Unlocker, params UnlockerP
 //
 // Given this signs inputs and outputs, sighash `ALL|FORKID` is used.
 func (tx *Tx) FillAllInputs(ctx context.Context, ug UnlockerGetter) error {
-	for i, in := range tx.Inputs {
-		u, err := ug.Unlocker(ctx, in.PreviousTxScript)
+	cache := tx.NewSigHashCache()
+	for i := range tx.Inputs {
+		unlockingScript, err := tx.unlockingScriptFrom(ctx, ug, UnlockerParams{
+			InputIdx:     uint32(i),
+			SigHashFlags: sighash.AllForkID, // use SIGHASHALLFORFORKID to sign automatically
+			SigHashCache: cache,
+		})
 		if err != nil {
 			return err
 		}
 
-		if err = tx.FillInput(ctx, u, UnlockerParams{
+		tx.Inputs[i].UnlockingScript = unlockingScript
+	}
+
+	return nil
+}
+
+// FillAllInputsConcurrently is like FillAllInputs, but unlocks up to limit
+// inputs concurrently, for unlockers with a high latency such as remote
+// signers. A limit less than 1 unlocks every input concurrently. The
+// UnlockerGetter and the Unlockers it returns must be safe for concurrent use.
+//
+// The signature hash of every input is computed up front from a shared
+// SigHashCache, and handed to its Unlocker in the UnlockerParams. The
+// unlocking scripts are only inserted once every input has been unlocked, so
+// the tx is left unchanged if any input fails.
+//
+// The returned error is nil if every input was unlocked. Otherwise, it is the
+// error of the lowest failing input, along with the error of each input, which
+// is nil for those unlocked.
+func (tx *Tx) FillAllInputsConcurrently(ctx context.Context, ug UnlockerGetter, limit int) ([]error, error) {
+	cache := tx.NewSigHashCache()
+	params := make([]UnlockerParams, len(tx.Inputs))
+	scripts := make([]*bscript.Script, len(tx.Inputs))
+	inputErrs := make([]error, len(tx.Inputs))
+	for i, in := range tx.Inputs {
+		params[i] = UnlockerParams{
 			InputIdx:     uint32(i),
-			SigHashFlags: sighash.AllForkID, // use SIGHASHALLFORFORKID to sign automatically
-		}); err != nil {
-			return err
+			SigHashFlags: sighash.AllForkID,
+			SigHashCache: cache,
 		}
+		params[i].SigHash, inputErrs[i] = tx.CalcInputSignatureHashWithCache(
+			uint32(i), sighash.AllForkID, in.PreviousTxScript, cache,
+		)
 	}
 
-	return nil
+	var g errgroup.Group
+	if limit > 0 {
+		g.SetLimit(limit)
+	}
+	for i := range tx.Inputs {
+		if inputErrs[i] != nil {
+			continue
+		}
+		g.Go(func() error {
+			scripts[i], inputErrs[i] = tx.unlockingScriptFrom(ctx, ug, params[i])
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	for i, err := range inputErrs {
+		if err != nil {
+			return inputErrs, fmt.Errorf("%w at index %d", err, i)
+		}
+	}
+
+	for i, s := range scripts {
+		tx.Inputs[i].UnlockingScript = s
+	}
+
+	return nil, nil
+}
+
+// unlockingScriptFrom returns the unlocking script of the input, from the
+// Unlocker returned by the UnlockerGetter for its previous tx script.
+func (tx *Tx) unlockingScriptFrom(ctx context.Context, ug UnlockerGetter, params UnlockerParams) (*bscript.Script, error) {
+	u, err := ug.Unlocker(ctx, tx.Inputs[params.InputIdx].PreviousTxScript)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.unlockingScript(ctx, u, params)
+}
+
+// unlockingScript returns the unlocking script of the input from the Unlocker,
+// signing with sighash `ALL|FORKID` if no flags are given.
+func (tx *Tx) unlockingScript(ctx context.Context, unlocker Unlocker, params UnlockerParams) (*bscript.Script, error) {
+	if unlocker == nil {
+		return nil, ErrNoUnlocker
+	}
+
+	if params.SigHashFlags == 0 {
+		params.SigHashFlags = sighash.AllForkID
+	}
+
+	return unlocker.UnlockingScript(ctx, tx, params)
 }
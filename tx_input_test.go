@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"math"
+	"sync/atomic"
 	"testing"
 
 	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
@@ -715,3 +716,115 @@ func TestTx_FillAllInputs(t *testing.T) {
 		assert.Equal(t, rawTxBefore, tx.String())
 	})
 }
+
+type unlockerGetterFunc func(ctx context.Context, lockingScript *bscript.Script) (bt.Unlocker, error)
+
+func (f unlockerGetterFunc) Unlocker(ctx context.Context, lockingScript *bscript.Script) (bt.Unlocker, error) {
+	return f(ctx, lockingScript)
+}
+
+type unlockerFunc func(ctx context.Context, tx *bt.Tx, params bt.UnlockerParams) (*bscript.Script, error)
+
+func (f unlockerFunc) UnlockingScript(ctx context.Context, tx *bt.Tx, params bt.UnlockerParams) (*bscript.Script, error) {
+	return f(ctx, tx, params)
+}
+
+func TestTx_FillAllInputsConcurrently(t *testing.T) {
+	t.Parallel()
+
+	pk, err := primitives.PrivateKeyFromWif("L3MhnEn1pLWcggeYLk9jdkvA2wUK1iWwwrGkBbgQRqv6HPCdRxuw")
+	require.NoError(t, err)
+
+	newTx := func(t *testing.T) *bt.Tx {
+		tx := bt.NewTx()
+		for i := uint32(0); i < 5; i++ {
+			require.NoError(t, tx.From(
+				"07912972e42095fe58daaf09161c5a5da57be47c2054dc2aaa52b30fefa1940b",
+				i,
+				"76a914af2590a45ae401651fdbdf59a76ad43d1862534088ac",
+				4000000,
+			))
+		}
+		require.NoError(t, tx.ChangeToAddress("mwV3YgnowbJJB3LcyCuqiKpdivvNNFiK7M", FQPoint5SatPerByte))
+		return tx
+	}
+
+	t.Run("matches sequential fill", func(t *testing.T) {
+		expTx := newTx(t)
+		require.NoError(t, expTx.FillAllInputs(context.Background(), &unlocker.Getter{PrivateKey: pk}))
+
+		for _, limit := range []int{0, 1, 2} {
+			tx := newTx(t)
+			inputErrs, err := tx.FillAllInputsConcurrently(context.Background(), &unlocker.Getter{PrivateKey: pk}, limit)
+			require.NoError(t, err)
+			assert.Nil(t, inputErrs)
+			assert.Equal(t, expTx.String(), tx.String())
+		}
+	})
+
+	t.Run("sighashes are computed up front", func(t *testing.T) {
+		tx := newTx(t)
+		tx.Inputs[1].PreviousTxScript = nil
+
+		ug := unlockerGetterFunc(func(context.Context, *bscript.Script) (bt.Unlocker, error) {
+			return unlockerFunc(func(_ context.Context, tx *bt.Tx, params bt.UnlockerParams) (*bscript.Script, error) {
+				sh, err := tx.CalcInputSignatureHash(params.InputIdx, sighash.AllForkID)
+				require.NoError(t, err)
+				assert.Equal(t, sh, params.SigHash)
+				return bscript.NewFromBytes([]byte{0x51}), nil
+			}), nil
+		})
+
+		inputErrs, err := tx.FillAllInputsConcurrently(context.Background(), ug, 2)
+		require.ErrorIs(t, err, bt.ErrEmptyPreviousTxScript)
+		require.Len(t, inputErrs, 5)
+		for i, inputErr := range inputErrs {
+			if i == 1 {
+				require.ErrorIs(t, inputErr, bt.ErrEmptyPreviousTxScript)
+				continue
+			}
+			require.NoError(t, inputErr)
+		}
+	})
+
+	t.Run("failing inputs leave tx unchanged", func(t *testing.T) {
+		tx := newTx(t)
+		rawTxBefore := tx.String()
+
+		var calls atomic.Int32
+		ug := unlockerGetterFunc(func(ctx context.Context, lockingScript *bscript.Script) (bt.Unlocker, error) {
+			if calls.Add(1)%2 == 0 {
+				return nil, errCustomTest
+			}
+			return &unlocker.Simple{PrivateKey: pk}, nil
+		})
+
+		inputErrs, err := tx.FillAllInputsConcurrently(context.Background(), ug, 2)
+		require.ErrorIs(t, err, errCustomTest)
+		require.Len(t, inputErrs, 5)
+
+		var failed int
+		for _, inputErr := range inputErrs {
+			if inputErr != nil {
+				require.ErrorIs(t, inputErr, errCustomTest)
+				failed++
+			}
+		}
+		assert.Equal(t, 2, failed)
+		assert.Equal(t, rawTxBefore, tx.String())
+	})
+
+	t.Run("no unlocker errors", func(t *testing.T) {
+		tx := newTx(t)
+		ug := unlockerGetterFunc(func(context.Context, *bscript.Script) (bt.Unlocker, error) {
+			return nil, nil //nolint:nilnil // getter without an unlocker
+		})
+
+		inputErrs, err := tx.FillAllInputsConcurrently(context.Background(), ug, 0)
+		require.ErrorIs(t, err, bt.ErrNoUnlocker)
+		require.EqualError(t, err, bt.ErrNoUnlocker.Error()+" at index 0")
+		for _, inputErr := range inputErrs {
+			require.ErrorIs(t, inputErr, bt.ErrNoUnlocker)
+		}
+	})
+}
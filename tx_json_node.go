@@ -188,11 +188,6 @@ func (o *nodeOutputJSON) fromOutput(out *Output) error {
 	if err != nil {
 		return err
 	}
-	addresses, err := out.LockingScript.Addresses()
-	if err != nil {
-		return err
-	}
-
 	*o = nodeOutputJSON{
 		Value: float64(out.Satoshis) / 100000000,
 		Index: 0,
@@ -204,7 +199,7 @@ func (o *nodeOutputJSON) fromOutput(out *Output) error {
 		}{
 			Asm:     asm,
 			Hex:     out.LockingScriptHexString(),
-			ReqSigs: len(addresses),
+			ReqSigs: reqSigs(out.LockingScript),
 			Type:    out.LockingScript.ScriptType(),
 		},
 	}
@@ -212,6 +207,28 @@ func (o *nodeOutputJSON) fromOutput(out *Output) error {
 	return nil
 }
 
+// reqSigs returns the number of signatures required to unlock the locking
+// script, being the required sigs of a multisig script and 1 for a script
+// paying to a single key, or 0 for any other script.
+func reqSigs(s *bscript.Script) int {
+	t, ok := bscript.TemplateFor(s)
+	if !ok {
+		return 0
+	}
+	p, err := t.Params(s)
+	if err != nil {
+		return 0
+	}
+	if p.RequiredSigs > 0 {
+		return p.RequiredSigs
+	}
+	if len(p.PublicKeyHashes)+len(p.PublicKeys) == 1 {
+		return 1
+	}
+
+	return 0
+}
+
 // toOutput converts a nodeOutputJSON to an Output.
 func (o *nodeOutputJSON) toOutput() (*Output, error) {
 	out := &Output{}
@@ -639,6 +639,46 @@ func TestTxsJSON_Node_UnmarshalJSON(t *testing.T) {
 	}
 }
 
+func TestTxJSON_Node_ReqSigs(t *testing.T) {
+	t.Parallel()
+
+	pubKeys := make([]*primitives.PublicKey, 3)
+	for i := range pubKeys {
+		pk, err := primitives.NewPrivateKey()
+		require.NoError(t, err)
+		pubKeys[i] = pk.PubKey()
+	}
+	multiSig, err := bscript.NewMultiSigFromPubKeys(2, pubKeys)
+	require.NoError(t, err)
+	p2pk := &bscript.Script{}
+	require.NoError(t, p2pk.AppendPushData(pubKeys[0].Compressed()))
+	require.NoError(t, p2pk.AppendOpcodes(bscript.OpCHECKSIG))
+
+	tx := bt.NewTx()
+	tx.AddOutput(&bt.Output{Satoshis: 1000, LockingScript: multiSig})
+	tx.AddOutput(&bt.Output{Satoshis: 1000, LockingScript: p2pk})
+	require.NoError(t, tx.PayToAddress("mtestD3vRB7AoYWK2n6kLdZmAMLbLhDsLr", 1000))
+
+	bb, err := json.Marshal(tx.NodeJSON())
+	require.NoError(t, err)
+
+	var got struct {
+		Vout []struct {
+			ScriptPubKey struct {
+				ReqSigs int    `json:"reqSigs"`
+				Type    string `json:"type"`
+			} `json:"scriptPubKey"`
+		} `json:"vout"`
+	}
+	require.NoError(t, json.Unmarshal(bb, &got))
+	require.Len(t, got.Vout, 3)
+	assert.Equal(t, bscript.ScriptTypeMultiSig, got.Vout[0].ScriptPubKey.Type)
+	assert.Equal(t, 2, got.Vout[0].ScriptPubKey.ReqSigs)
+	assert.Equal(t, bscript.ScriptTypePubKey, got.Vout[1].ScriptPubKey.Type)
+	assert.Equal(t, 1, got.Vout[1].ScriptPubKey.ReqSigs)
+	assert.Equal(t, 1, got.Vout[2].ScriptPubKey.ReqSigs)
+}
+
 func TestOutput_Node_JSON(t *testing.T) {
 	tests := map[string]struct {
 		output  *bt.Output
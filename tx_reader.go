@@ -0,0 +1,154 @@
+package bt
+
+import (
+	"io"
+	"iter"
+)
+
+// TxReader decodes the txs of a block one at a time from a stream, so that
+// a block never has to be held in memory in its entirety. The stream must be
+// positioned at the varint tx count which follows the block header.
+//
+// Usage follows that of bufio.Scanner:
+//
+//	txr := bt.NewTxReader(r)
+//	for txr.Next() {
+//		tx := txr.Tx()
+//		...
+//	}
+//	if err := txr.Err(); err != nil {
+//		...
+//	}
+type TxReader struct {
+	r     io.Reader
+	arena *Arena
+
+	count     uint64
+	countRead bool
+	read      uint64
+
+	bytesRead int64
+	txOffset  int64
+	tx        *Tx
+	err       error
+}
+
+// NewTxReader returns a TxReader which decodes txs from r. Each tx is
+// allocated on the heap and may be retained by the caller.
+func NewTxReader(r io.Reader) *TxReader {
+	return &TxReader{r: r}
+}
+
+// NewTxReaderWithArena returns a TxReader which draws the scripts of each tx
+// from the arena a. The arena is Reset before every tx is decoded, so a tx,
+// and any script slices taken from it, are only valid until the next call to
+// Next. Copy anything which needs to outlive this, for example with Tx.Clone.
+func NewTxReaderWithArena(r io.Reader, a *Arena) *TxReader {
+	return &TxReader{r: r, arena: a}
+}
+
+// Count returns the number of txs in the stream, reading the leading varint
+// if it has not been read yet.
+func (txr *TxReader) Count() (uint64, error) {
+	if txr.countRead {
+		return txr.count, nil
+	}
+	if txr.err != nil {
+		return 0, txr.err
+	}
+
+	var txCount VarInt
+	n, err := txCount.ReadFrom(txr.r)
+	txr.bytesRead += n
+	if err != nil {
+		txr.err = err
+		return 0, err
+	}
+
+	txr.count = uint64(txCount)
+	txr.countRead = true
+
+	return txr.count, nil
+}
+
+// Next decodes the next tx from the stream, returning false once all txs have
+// been read or an error occurs. Err reports which of the two it was.
+func (txr *TxReader) Next() bool {
+	txr.tx = nil
+	if txr.err != nil {
+		return false
+	}
+	if _, err := txr.Count(); err != nil {
+		return false
+	}
+	if txr.read >= txr.count {
+		return false
+	}
+
+	if txr.arena != nil {
+		txr.arena.Reset()
+	}
+
+	tx := new(Tx)
+	txr.txOffset = txr.bytesRead
+	n, err := tx.ReadFromWithArena(txr.r, txr.arena)
+	txr.bytesRead += n
+	if err != nil {
+		txr.err = err
+		return false
+	}
+
+	txr.read++
+	txr.tx = tx
+
+	return true
+}
+
+// Tx returns the tx decoded by the most recent call to Next.
+func (txr *TxReader) Tx() *Tx {
+	return txr.tx
+}
+
+// Index returns the position in the block of the tx returned by Tx, the
+// coinbase being at index 0, or -1 if Tx returns nil, as it does before the
+// first call to Next and once Next has returned false.
+func (txr *TxReader) Index() int64 {
+	if txr.tx == nil {
+		return -1
+	}
+
+	return int64(txr.read) - 1 //nolint:gosec // a block cannot hold MaxInt64 txs
+}
+
+// Offset returns the byte offset of the tx returned by Tx, relative to the
+// start of the stream as it was when passed to the TxReader. When the stream
+// began with a block header, add BlockHeaderLen to get the offset within the
+// block.
+func (txr *TxReader) Offset() int64 {
+	return txr.txOffset
+}
+
+// BytesRead returns the total number of bytes read from the stream so far.
+func (txr *TxReader) BytesRead() int64 {
+	return txr.bytesRead
+}
+
+// Err returns the first error encountered while reading, if any.
+func (txr *TxReader) Err() error {
+	return txr.err
+}
+
+// All returns an iterator over the remaining txs in the stream. If an error
+// occurs it is yielded along with a nil tx, and iteration stops.
+func (txr *TxReader) All() iter.Seq2[*Tx, error] {
+	return func(yield func(*Tx, error) bool) {
+		for txr.Next() {
+			if !yield(txr.tx, nil) {
+				return
+			}
+		}
+		if txr.err != nil {
+			yield(nil, txr.err)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package bt_test
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+)
+
+func TestTxReader_Next(t *testing.T) {
+	t.Parallel()
+
+	b := readTestBlock(t)
+	block, err := bt.NewBlockFromBytes(b)
+	require.NoError(t, err)
+
+	t.Run("reads all txs with offsets", func(t *testing.T) {
+		txr := bt.NewTxReader(bufio.NewReader(bytes.NewReader(b[bt.BlockHeaderLen:])))
+
+		assert.Equal(t, int64(-1), txr.Index())
+
+		count, err := txr.Count()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(648), count)
+		assert.Equal(t, int64(-1), txr.Index())
+
+		var i int
+		for txr.Next() {
+			tx := txr.Tx()
+			assert.Equal(t, int64(i), txr.Index())
+			assert.Equal(t, block.Txs[i].TxID(), tx.TxID())
+
+			start := bt.BlockHeaderLen + int(txr.Offset())
+			assert.Equal(t, tx.Bytes(), b[start:start+tx.Size()])
+			i++
+		}
+		require.NoError(t, txr.Err())
+		assert.Equal(t, 648, i)
+		assert.Equal(t, int64(len(b)-bt.BlockHeaderLen), txr.BytesRead())
+		assert.Nil(t, txr.Tx())
+		assert.Equal(t, int64(-1), txr.Index())
+		assert.False(t, txr.Next())
+	})
+
+	t.Run("reads all txs with arena", func(t *testing.T) {
+		a := bt.NewArena(1024)
+		txr := bt.NewTxReaderWithArena(bytes.NewReader(b[bt.BlockHeaderLen:]), a)
+
+		var i int
+		for txr.Next() {
+			assert.Equal(t, block.Txs[i].TxID(), txr.Tx().TxID())
+			i++
+		}
+		require.NoError(t, txr.Err())
+		assert.Equal(t, 648, i)
+	})
+
+	t.Run("truncated stream", func(t *testing.T) {
+		txr := bt.NewTxReader(bytes.NewReader(b[bt.BlockHeaderLen : len(b)-10]))
+
+		var i int
+		for txr.Next() {
+			i++
+		}
+		require.ErrorIs(t, txr.Err(), io.ErrUnexpectedEOF)
+		assert.Equal(t, 647, i)
+		assert.False(t, txr.Next())
+	})
+
+	t.Run("empty stream", func(t *testing.T) {
+		txr := bt.NewTxReader(bytes.NewReader(nil))
+		assert.False(t, txr.Next())
+		require.Error(t, txr.Err())
+
+		_, err := txr.Count()
+		require.Error(t, err)
+	})
+
+	t.Run("no txs", func(t *testing.T) {
+		txr := bt.NewTxReader(bytes.NewReader([]byte{0x00}))
+		assert.False(t, txr.Next())
+		require.NoError(t, txr.Err())
+	})
+}
+
+func TestTxReader_All(t *testing.T) {
+	t.Parallel()
+
+	b := readTestBlock(t)
+
+	t.Run("iterates all txs", func(t *testing.T) {
+		txr := bt.NewTxReader(bytes.NewReader(b[bt.BlockHeaderLen:]))
+
+		var last *bt.Tx
+		var i int
+		for tx, err := range txr.All() {
+			require.NoError(t, err)
+			last = tx
+			i++
+		}
+		assert.Equal(t, 648, i)
+		assert.Equal(t, "b7c59d7fa17a74bbe0a05e5381f42b9ac7fe23b8a1ca40005a74802fe5b8bb5a", last.TxID())
+	})
+
+	t.Run("stops early and resumes", func(t *testing.T) {
+		txr := bt.NewTxReader(bytes.NewReader(b[bt.BlockHeaderLen:]))
+
+		for tx, err := range txr.All() {
+			require.NoError(t, err)
+			assert.True(t, tx.IsCoinbase())
+			break
+		}
+
+		var i int
+		for _, err := range txr.All() {
+			require.NoError(t, err)
+			i++
+		}
+		assert.Equal(t, 647, i)
+	})
+
+	t.Run("yields error", func(t *testing.T) {
+		txr := bt.NewTxReader(bytes.NewReader(b[bt.BlockHeaderLen : len(b)-10]))
+
+		var errs int
+		for tx, err := range txr.All() {
+			if err != nil {
+				assert.Nil(t, tx)
+				errs++
+			}
+		}
+		assert.Equal(t, 1, errs)
+	})
+}
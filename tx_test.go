@@ -1323,3 +1323,72 @@ func TestSetTxHash(t *testing.T) {
 
 	assert.Equal(t, "0000000000000000000000000000000000000000000000000000000000000000", tx.TxID())
 }
+
+func TestTx_EstimateSize_UnlockingScriptEstimator(t *testing.T) {
+	// <aabbcc> OP_EQUAL, unlocked by pushing aabbcc.
+	const puzzleScript = "03aabbcc87"
+
+	newTx := func(t *testing.T) *bt.Tx {
+		tx := bt.NewTx()
+		require.NoError(t, tx.From(
+			"a4c76f8a7c05a91dcf5699b95b54e856298e50c1ceca9a8a5569c8532c500c11",
+			0, puzzleScript, 1000,
+		))
+		require.NoError(t, tx.AddP2PKHOutputFromAddress("mtestD3vRB7AoYWK2n6kLdZmAMLbLhDsLr", 900))
+		return tx
+	}
+
+	t.Run("unsigned input without estimator is unsupported", func(t *testing.T) {
+		_, err := newTx(t).EstimateSize()
+		require.ErrorIs(t, err, bt.ErrUnsupportedScript)
+	})
+
+	t.Run("signed input without estimator is sized as is", func(t *testing.T) {
+		tx := newTx(t)
+		uscript, err := bscript.NewFromHexString("03aabbcc")
+		require.NoError(t, err)
+		tx.Inputs[0].UnlockingScript = uscript
+
+		size, err := tx.EstimateSize()
+		require.NoError(t, err)
+		assert.Equal(t, tx.Size(), size)
+	})
+
+	t.Run("registered estimator is used for unsigned inputs", func(t *testing.T) {
+		bt.RegisterUnlockingScriptEstimator(
+			func(s *bscript.Script) bool { return s.String() == puzzleScript },
+			bt.UnlockingScriptEstimatorFunc(func(_ *bt.Tx, inputIdx uint32) uint32 {
+				assert.Equal(t, uint32(0), inputIdx)
+				return 4
+			}),
+		)
+
+		tx := newTx(t)
+		size, err := tx.EstimateSize()
+		require.NoError(t, err)
+		assert.Equal(t, tx.Size()+4, size)
+
+		ok, err := tx.EstimateIsFeePaidEnough(FQPoint5SatPerByte)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		require.NoError(t, tx.ChangeToAddress("mtestD3vRB7AoYWK2n6kLdZmAMLbLhDsLr", FQPoint5SatPerByte))
+		require.Len(t, tx.Outputs, 2)
+		assert.Equal(t, uint64(100-(uint64(tx.Size())+4)/2), tx.Outputs[1].Satoshis)
+	})
+
+	t.Run("unlocker implementing estimator can be registered", func(t *testing.T) {
+		tx := bt.NewTx()
+		require.NoError(t, tx.From(
+			"a4c76f8a7c05a91dcf5699b95b54e856298e50c1ceca9a8a5569c8532c500c11",
+			0, "76a91455b61be43392125d127f1780fb038437cd67ef9c88ac", 1000,
+		))
+
+		var e bt.UnlockingScriptEstimator = &unlocker.Simple{}
+		assert.Equal(t, uint32(bt.P2PKHUnlockingScriptLen), e.EstimateLength(tx, 0))
+
+		size, err := tx.EstimateSize()
+		require.NoError(t, err)
+		assert.Equal(t, tx.Size()+bt.P2PKHUnlockingScriptLen, size)
+	})
+}
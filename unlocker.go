@@ -2,6 +2,7 @@ package bt
 
 import (
 	"context"
+	"sync"
 
 	"github.com/bsv-blockchain/go-bt/v2/bscript"
 	"github.com/bsv-blockchain/go-bt/v2/sighash"
@@ -13,6 +14,12 @@ type UnlockerParams struct {
 	InputIdx uint32
 	// SigHashFlags the be applied [DEFAULT ALL|FORKID]
 	SigHashFlags sighash.Flag
+	// SigHashCache the intermediate hashes of the tx shared by the inputs
+	// being unlocked. [OPTIONAL]
+	SigHashCache *SigHashCache
+	// SigHash the signature hash of the input for the SigHashFlags, if
+	// computed ahead of unlocking. [OPTIONAL]
+	SigHash []byte
 	// TODO: add previous tx script and sats here instead of in
 	// input (and potentially remove from input) - see issue #143
 }
@@ -27,3 +34,93 @@ type Unlocker interface {
 type UnlockerGetter interface {
 	Unlocker(ctx context.Context, lockingScript *bscript.Script) (Unlocker, error)
 }
+
+// P2PKHUnlockingScriptLen is the estimated length in bytes of a P2PKH unlocking
+// script, being a push of a 72 byte signature and a push of a 33 byte compressed
+// public key.
+const P2PKHUnlockingScriptLen = 107
+
+// UnlockingScriptEstimator is an optional interface which an Unlocker can implement
+// to report the length of the unlocking script it will produce, allowing the size
+// and fees of a tx to be estimated before its inputs are unlocked.
+type UnlockingScriptEstimator interface {
+	EstimateLength(tx *Tx, inputIdx uint32) uint32
+}
+
+// UnlockingScriptEstimatorFunc is an adapter to allow the use of an ordinary function
+// as an UnlockingScriptEstimator.
+type UnlockingScriptEstimatorFunc func(tx *Tx, inputIdx uint32) uint32
+
+// EstimateLength calls f(tx, inputIdx).
+func (f UnlockingScriptEstimatorFunc) EstimateLength(tx *Tx, inputIdx uint32) uint32 {
+	return f(tx, inputIdx)
+}
+
+type unlockingScriptEstimator struct {
+	match     func(lockingScript *bscript.Script) bool
+	estimator UnlockingScriptEstimator
+}
+
+var (
+	estimatorsMu sync.RWMutex
+	estimators   = []unlockingScriptEstimator{{
+		match: func(s *bscript.Script) bool {
+			return s.IsP2PKH() || s.IsP2PKHInscription()
+		},
+		estimator: UnlockingScriptEstimatorFunc(func(*Tx, uint32) uint32 {
+			return P2PKHUnlockingScriptLen
+		}),
+	}, {
+		match: bscript.MultiSigTemplate{}.Match,
+		estimator: UnlockingScriptEstimatorFunc(func(tx *Tx, inputIdx uint32) uint32 {
+			m, _, _ := tx.Inputs[inputIdx].PreviousTxScript.ParseMultiSig()
+			return MultiSigUnlockingScriptLen(m)
+		}),
+	}}
+)
+
+// MultiSigUnlockingScriptLen returns the estimated length in bytes of the unlocking
+// script of an m-of-n bare multisig script, being OP_0 followed by a push of m 72 byte
+// signatures.
+func MultiSigUnlockingScriptLen(m int) uint32 {
+	return 1 + uint32(m)*(1+72)
+}
+
+// RegisterUnlockingScriptEstimator registers e to estimate the length of the unlocking
+// script of any unsigned input spending a locking script for which match returns true.
+// It is consulted by EstimateSize, EstimateSizeWithTypes, EstimateIsFeePaidEnough,
+// Change and Fund. Estimators registered later take precedence, so the builtin P2PKH
+// estimator can be overridden. A bscript.ScriptTemplate implementing
+// UnlockingScriptEstimator is used for the scripts it matches without registering.
+//
+// An Unlocker which implements UnlockingScriptEstimator can be registered directly:
+//
+//	bt.RegisterUnlockingScriptEstimator(isMyContract, &MyContractUnlocker{})
+func RegisterUnlockingScriptEstimator(match func(lockingScript *bscript.Script) bool, e UnlockingScriptEstimator) {
+	estimatorsMu.Lock()
+	defer estimatorsMu.Unlock()
+
+	estimators = append(estimators, unlockingScriptEstimator{match: match, estimator: e})
+}
+
+// unlockingScriptEstimatorFor returns the most recently registered estimator which
+// matches the locking script. Failing that, it returns the template matching the
+// locking script if it implements UnlockingScriptEstimator, or false if it does not.
+func unlockingScriptEstimatorFor(lockingScript *bscript.Script) (UnlockingScriptEstimator, bool) {
+	estimatorsMu.RLock()
+	defer estimatorsMu.RUnlock()
+
+	for i := len(estimators) - 1; i >= 0; i-- {
+		if estimators[i].match(lockingScript) {
+			return estimators[i].estimator, true
+		}
+	}
+
+	if t, ok := bscript.TemplateFor(lockingScript); ok {
+		if e, ok := t.(UnlockingScriptEstimator); ok {
+			return e, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,161 @@
+package unlocker
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	bec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/sighash"
+)
+
+// PartialSignatures are signatures for a single multisig input, keyed by the hex
+// of the public key they are for. Each signature has its SIGHASH flag appended.
+//
+// Parties holding different keys can each produce a set with MultiSig.Sign, and
+// the sets merged to unlock the input once enough signatures are collected.
+type PartialSignatures map[string][]byte
+
+// Merge adds the signatures of the other sets to ps, replacing any for the same
+// public key.
+func (ps PartialSignatures) Merge(others ...PartialSignatures) {
+	for _, o := range others {
+		for pubKey, sig := range o {
+			ps[pubKey] = sig
+		}
+	}
+}
+
+// MultiSig implements the `bt.Unlocker` interface for bare multisig scripts. It
+// signs with each private key whose public key is in the locking script, and with
+// each key of the Signer identified by KeyIDs, and builds an unlocking script from
+// the first m signatures in the order of the public keys.
+type MultiSig struct {
+	PrivateKeys []*bec.PrivateKey
+	// Signer, if set, signs with each of the keys identified by KeyIDs, allowing
+	// the private keys to be held elsewhere, such as on another device.
+	Signer Signer
+	KeyIDs []string
+	// Signatures are signatures already collected for the input, such as from
+	// other parties, which are merged with those produced.
+	Signatures PartialSignatures
+}
+
+// Sign signs the input with each private key of the unlocker whose public key is in
+// the locking script, and with each key identified by KeyIDs, returning the signatures
+// produced. Signatures already held in Signatures are not included.
+//
+// The keys identified by KeyIDs are signed with in one call if the Signer implements
+// the `BatchSigner` interface. As they are chosen for the input, ErrPubKeyMismatch is
+// returned if the public key of one is not in the locking script. A nil private key
+// returns ErrNoPrivateKey.
+func (m *MultiSig) Sign(ctx context.Context, tx *bt.Tx, params bt.UnlockerParams) (PartialSignatures, error) {
+	if params.SigHashFlags == 0 {
+		params.SigHashFlags = sighash.AllForkID
+	}
+
+	if tx.Inputs[params.InputIdx].PreviousTxScript == nil {
+		return nil, bt.ErrEmptyPreviousTxScript
+	}
+	_, pubKeys, err := tx.Inputs[params.InputIdx].PreviousTxScript.ParseMultiSig()
+	if err != nil {
+		return nil, err
+	}
+
+	inScript := make(map[string]bool, len(pubKeys))
+	for _, pubKey := range pubKeys {
+		inScript[hex.EncodeToString(pubKey)] = true
+	}
+
+	sh, err := sigHash(tx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	sigs := make(PartialSignatures)
+	addSig := func(pubKey, sig []byte) {
+		sigBuf := make([]byte, 0, len(sig)+1)
+		sigBuf = append(sigBuf, sig...)
+		sigs[hex.EncodeToString(pubKey)] = append(sigBuf, uint8(params.SigHashFlags))
+	}
+
+	for _, pk := range m.PrivateKeys {
+		if pk == nil {
+			return nil, ErrNoPrivateKey
+		}
+		pubKey := pk.PubKey()
+		for _, serialised := range [][]byte{pubKey.Compressed(), pubKey.Uncompressed()} {
+			if !inScript[hex.EncodeToString(serialised)] {
+				continue
+			}
+
+			sig, err := pk.Sign(sh)
+			if err != nil {
+				return nil, err
+			}
+			addSig(serialised, sig.Serialize())
+		}
+	}
+
+	if m.Signer != nil && len(m.KeyIDs) > 0 {
+		reqs := make([]SignRequest, len(m.KeyIDs))
+		for i, keyID := range m.KeyIDs {
+			reqs[i] = SignRequest{KeyID: keyID, Digest: sh}
+		}
+
+		results, err := SignAll(ctx, m.Signer, reqs)
+		if err != nil {
+			return nil, err
+		}
+		for i, r := range results {
+			if !inScript[hex.EncodeToString(r.PublicKey)] {
+				return nil, fmt.Errorf("%w for key id %q", ErrPubKeyMismatch, m.KeyIDs[i])
+			}
+			addSig(r.PublicKey, r.Signature)
+		}
+	}
+
+	return sigs, nil
+}
+
+// UnlockingScript signs the input, merges the signatures produced with those already
+// held, and builds an unlocking script of OP_0 followed by m signatures in the order
+// of the public keys of the locking script. If fewer than m signatures are available,
+// ErrInsufficientSignatures is returned.
+func (m *MultiSig) UnlockingScript(ctx context.Context, tx *bt.Tx, params bt.UnlockerParams) (*bscript.Script, error) {
+	sigs, err := m.Sign(ctx, tx, params)
+	if err != nil {
+		return nil, err
+	}
+	sigs.Merge(m.Signatures)
+
+	required, pubKeys, err := tx.Inputs[params.InputIdx].PreviousTxScript.ParseMultiSig()
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := make([][]byte, 0, required)
+	for _, pubKey := range pubKeys {
+		if len(ordered) == required {
+			break
+		}
+		if sig, ok := sigs[hex.EncodeToString(pubKey)]; ok {
+			ordered = append(ordered, sig)
+		}
+	}
+	if len(ordered) < required {
+		return nil, fmt.Errorf("%w: have %d of %d", ErrInsufficientSignatures, len(ordered), required)
+	}
+
+	return bscript.NewMultiSigUnlockingScript(ordered)
+}
+
+// EstimateLength implements the `bt.UnlockingScriptEstimator` interface, returning
+// the length of a multisig unlocking script for the locking script of the input.
+func (m *MultiSig) EstimateLength(tx *bt.Tx, inputIdx uint32) uint32 {
+	required, _, _ := tx.Inputs[inputIdx].PreviousTxScript.ParseMultiSig()
+	return bt.MultiSigUnlockingScriptLen(required)
+}
@@ -0,0 +1,200 @@
+package unlocker_test
+
+import (
+	"context"
+	"testing"
+
+	bec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter"
+	"github.com/bsv-blockchain/go-bt/v2/unlocker"
+)
+
+func newKeys(t *testing.T, n int) []*bec.PrivateKey {
+	t.Helper()
+
+	keys := make([]*bec.PrivateKey, n)
+	for i := range keys {
+		pk, err := bec.NewPrivateKey()
+		require.NoError(t, err)
+		keys[i] = pk
+	}
+
+	return keys
+}
+
+func newMultiSigTx(t *testing.T, m int, keys []*bec.PrivateKey) (*bt.Tx, *bscript.Script) {
+	t.Helper()
+
+	pubKeys := make([]*bec.PublicKey, len(keys))
+	for i, pk := range keys {
+		pubKeys[i] = pk.PubKey()
+	}
+	lockingScript, err := bscript.NewMultiSigFromPubKeys(m, pubKeys)
+	require.NoError(t, err)
+
+	tx := bt.NewTx()
+	require.NoError(t, tx.From(
+		"a4c76f8a7c05a91dcf5699b95b54e856298e50c1ceca9a8a5569c8532c500c11",
+		0, lockingScript.String(), 10000,
+	))
+	require.NoError(t, tx.AddP2PKHOutputFromAddress("mtestD3vRB7AoYWK2n6kLdZmAMLbLhDsLr", 9000))
+
+	return tx, lockingScript
+}
+
+func requireValid(t *testing.T, tx *bt.Tx, lockingScript *bscript.Script) {
+	t.Helper()
+
+	require.NoError(t, interpreter.NewEngine().Execute(
+		interpreter.WithTx(tx, 0, &bt.Output{LockingScript: lockingScript, Satoshis: 10000}),
+		interpreter.WithForkID(),
+		interpreter.WithAfterGenesis(),
+	))
+}
+
+func TestMultiSig_UnlockingScript(t *testing.T) {
+	t.Parallel()
+
+	t.Run("2 of 3 with private keys", func(t *testing.T) {
+		keys := newKeys(t, 3)
+		tx, lockingScript := newMultiSigTx(t, 2, keys)
+
+		estimate, err := tx.EstimateSize()
+		require.NoError(t, err)
+
+		// Signed in the reverse order of the public keys.
+		u := &unlocker.MultiSig{PrivateKeys: []*bec.PrivateKey{keys[2], keys[0]}}
+		require.NoError(t, tx.FillInput(context.Background(), u, bt.UnlockerParams{}))
+
+		requireValid(t, tx, lockingScript)
+		assert.LessOrEqual(t, tx.Size(), estimate)
+		assert.Equal(t, uint32(1+2*73), u.EstimateLength(tx, 0))
+	})
+
+	t.Run("signer", func(t *testing.T) {
+		keys := newKeys(t, 3)
+		tx, lockingScript := newMultiSigTx(t, 3, keys)
+		s := &kmsSigner{LocalSigner: unlocker.LocalSigner{Keys: map[string]*bec.PrivateKey{
+			"b": keys[1],
+			"c": keys[2],
+		}}}
+
+		u := &unlocker.MultiSig{
+			PrivateKeys: []*bec.PrivateKey{keys[0]},
+			Signer:      s,
+			KeyIDs:      []string{"c", "b"},
+		}
+		require.NoError(t, tx.FillInput(context.Background(), u, bt.UnlockerParams{}))
+
+		requireValid(t, tx, lockingScript)
+		assert.Equal(t, 1, s.batchCalls)
+	})
+
+	t.Run("signer key not in the script", func(t *testing.T) {
+		keys := newKeys(t, 3)
+		tx, _ := newMultiSigTx(t, 1, keys[:2])
+		s := &unlocker.LocalSigner{Keys: map[string]*bec.PrivateKey{"c": keys[2]}}
+
+		_, err := (&unlocker.MultiSig{Signer: s, KeyIDs: []string{"c"}}).Sign(context.Background(), tx, bt.UnlockerParams{})
+		require.ErrorIs(t, err, unlocker.ErrPubKeyMismatch)
+	})
+
+	t.Run("nil private key", func(t *testing.T) {
+		keys := newKeys(t, 2)
+		tx, _ := newMultiSigTx(t, 1, keys)
+
+		_, err := (&unlocker.MultiSig{PrivateKeys: []*bec.PrivateKey{nil}}).Sign(context.Background(), tx, bt.UnlockerParams{})
+		require.ErrorIs(t, err, unlocker.ErrNoPrivateKey)
+	})
+
+	t.Run("merged partial signatures", func(t *testing.T) {
+		keys := newKeys(t, 3)
+		tx, lockingScript := newMultiSigTx(t, 3, keys)
+
+		// Each party signs with the keys they hold.
+		a, err := (&unlocker.MultiSig{PrivateKeys: keys[:1]}).Sign(context.Background(), tx, bt.UnlockerParams{})
+		require.NoError(t, err)
+		require.Len(t, a, 1)
+		b, err := (&unlocker.MultiSig{
+			Signer: &unlocker.LocalSigner{Keys: map[string]*bec.PrivateKey{"b": keys[1]}},
+			KeyIDs: []string{"b"},
+		}).Sign(context.Background(), tx, bt.UnlockerParams{})
+		require.NoError(t, err)
+		require.Len(t, b, 1)
+
+		sigs := unlocker.PartialSignatures{}
+		sigs.Merge(a, b)
+		require.Len(t, sigs, 2)
+
+		// The last party completes the input.
+		u := &unlocker.MultiSig{PrivateKeys: keys[2:], Signatures: sigs}
+		require.NoError(t, tx.FillInput(context.Background(), u, bt.UnlockerParams{}))
+
+		requireValid(t, tx, lockingScript)
+	})
+
+	t.Run("keys not in the script are ignored", func(t *testing.T) {
+		keys := newKeys(t, 3)
+		tx, _ := newMultiSigTx(t, 1, keys[:2])
+
+		sigs, err := (&unlocker.MultiSig{PrivateKeys: keys[2:]}).Sign(context.Background(), tx, bt.UnlockerParams{})
+		require.NoError(t, err)
+		assert.Empty(t, sigs)
+	})
+
+	t.Run("insufficient signatures", func(t *testing.T) {
+		keys := newKeys(t, 3)
+		tx, _ := newMultiSigTx(t, 2, keys)
+
+		u := &unlocker.MultiSig{PrivateKeys: keys[:1]}
+		err := tx.FillInput(context.Background(), u, bt.UnlockerParams{})
+		require.ErrorIs(t, err, unlocker.ErrInsufficientSignatures)
+	})
+
+	t.Run("not multisig", func(t *testing.T) {
+		tx := bt.NewTx()
+		require.NoError(t, tx.From(
+			"a4c76f8a7c05a91dcf5699b95b54e856298e50c1ceca9a8a5569c8532c500c11",
+			0, "76a91455b61be43392125d127f1780fb038437cd67ef9c88ac", 10000,
+		))
+
+		u := &unlocker.MultiSig{PrivateKeys: newKeys(t, 1)}
+		err := tx.FillInput(context.Background(), u, bt.UnlockerParams{})
+		require.ErrorIs(t, err, bscript.ErrNotMultiSig)
+	})
+}
+
+func TestGetter_MultiSig(t *testing.T) {
+	t.Parallel()
+
+	keys := newKeys(t, 2)
+
+	t.Run("private key", func(t *testing.T) {
+		tx, lockingScript := newMultiSigTx(t, 1, keys)
+
+		require.NoError(t, tx.FillAllInputs(context.Background(), &unlocker.Getter{PrivateKey: keys[1]}))
+
+		requireValid(t, tx, lockingScript)
+	})
+
+	t.Run("signer", func(t *testing.T) {
+		tx, lockingScript := newMultiSigTx(t, 1, keys)
+		s := &unlocker.LocalSigner{Keys: map[string]*bec.PrivateKey{"key": keys[1]}}
+
+		require.NoError(t, tx.FillAllInputs(context.Background(), &unlocker.Getter{Signer: s, KeyID: "key"}))
+
+		requireValid(t, tx, lockingScript)
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		tx, _ := newMultiSigTx(t, 1, keys)
+
+		err := tx.FillAllInputs(context.Background(), &unlocker.Getter{})
+		require.ErrorIs(t, err, unlocker.ErrInsufficientSignatures)
+	})
+}
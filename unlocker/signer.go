@@ -0,0 +1,156 @@
+package unlocker
+
+import (
+	"context"
+	"fmt"
+
+	bec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/sighash"
+)
+
+// Signer signs digests with keys held elsewhere, such as in an HSM or KMS. The
+// keys are identified by a key identifier, so the private keys never need to
+// be handed to the unlocker.
+type Signer interface {
+	// Sign returns the DER signature of the digest by the key identified by
+	// keyID, along with the public key, serialised as it appears in scripts.
+	Sign(ctx context.Context, keyID string, digest []byte) (sig, pubKey []byte, err error)
+}
+
+// BatchSigner is an optional interface which a Signer can implement to sign
+// many digests in one call, saving a round trip to the signer for each.
+type BatchSigner interface {
+	Signer
+	// SignBatch returns the result of each request, in the order of the
+	// requests.
+	SignBatch(ctx context.Context, reqs []SignRequest) ([]SignResult, error)
+}
+
+// SignRequest is a request for the digest to be signed by the key identified
+// by KeyID.
+type SignRequest struct {
+	KeyID  string
+	Digest []byte
+}
+
+// SignResult is the DER signature and public key returned for a SignRequest.
+type SignResult struct {
+	Signature []byte
+	PublicKey []byte
+}
+
+// LocalSigner implements the `BatchSigner` interface with private keys held in
+// memory, keyed by their key identifier. It is useful as a stand-in for a
+// remote signer.
+type LocalSigner struct {
+	Keys map[string]*bec.PrivateKey
+}
+
+// Sign signs the digest with the private key identified by keyID, returning
+// the signature and the compressed public key.
+func (s *LocalSigner) Sign(_ context.Context, keyID string, digest []byte) ([]byte, []byte, error) {
+	pk, ok := s.Keys[keyID]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnknownKeyID, keyID)
+	}
+
+	sig, err := pk.Sign(digest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sig.Serialize(), pk.PubKey().Compressed(), nil
+}
+
+// SignBatch signs each request with Sign.
+func (s *LocalSigner) SignBatch(ctx context.Context, reqs []SignRequest) ([]SignResult, error) {
+	return signEach(ctx, s, reqs)
+}
+
+// SignAll signs each request with the signer, in one call if it implements
+// the `BatchSigner` interface, otherwise one call per request.
+func SignAll(ctx context.Context, s Signer, reqs []SignRequest) ([]SignResult, error) {
+	if bs, ok := s.(BatchSigner); ok {
+		results, err := bs.SignBatch(ctx, reqs)
+		if err != nil {
+			return nil, err
+		}
+		if len(results) != len(reqs) {
+			return nil, fmt.Errorf("%w: got %d for %d requests", ErrSignatureCount, len(results), len(reqs))
+		}
+		return results, nil
+	}
+
+	return signEach(ctx, s, reqs)
+}
+
+// signEach signs each request with a call to the signer.
+func signEach(ctx context.Context, s Signer, reqs []SignRequest) ([]SignResult, error) {
+	results := make([]SignResult, len(reqs))
+	for i, req := range reqs {
+		sig, pubKey, err := s.Sign(ctx, req.KeyID, req.Digest)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = SignResult{Signature: sig, PublicKey: pubKey}
+	}
+
+	return results, nil
+}
+
+// SignAllInputs unlocks every P2PKH input of the tx with the signer, using the
+// key identified by the key id at the same index as the input. The digests of
+// every input are signed with one call if the signer implements the
+// `BatchSigner` interface.
+//
+// Like `tx.FillAllInputs`, sighash `ALL|FORKID` is used. The public key of each
+// signature is checked against the public key hash of its input, returning
+// ErrPubKeyMismatch for a wrong key id. The unlocking scripts are only inserted
+// once every input is signed, so the tx is left unchanged on error.
+func SignAllInputs(ctx context.Context, tx *bt.Tx, s Signer, keyIDs []string) error {
+	if len(keyIDs) != len(tx.Inputs) {
+		return fmt.Errorf("%w: got %d for %d inputs", ErrKeyIDCount, len(keyIDs), len(tx.Inputs))
+	}
+
+	cache := tx.NewSigHashCache()
+	reqs := make([]SignRequest, len(tx.Inputs))
+	for i, in := range tx.Inputs {
+		if in.PreviousTxScript == nil {
+			return fmt.Errorf("%w at index %d", bt.ErrEmptyPreviousTxScript, i)
+		}
+		switch in.PreviousTxScript.ScriptType() {
+		case bscript.ScriptTypePubKeyHash, bscript.ScriptTypePubKeyHashInscription:
+		default:
+			return fmt.Errorf("%w at index %d", ErrOnlyP2PKHSupported, i)
+		}
+
+		sh, err := tx.CalcInputSignatureHashWithCache(uint32(i), sighash.AllForkID, in.PreviousTxScript, cache)
+		if err != nil {
+			return err
+		}
+		reqs[i] = SignRequest{KeyID: keyIDs[i], Digest: sh}
+	}
+
+	results, err := SignAll(ctx, s, reqs)
+	if err != nil {
+		return err
+	}
+
+	scripts := make([]*bscript.Script, len(results))
+	for i, r := range results {
+		if err = checkPubKey(tx.Inputs[i].PreviousTxScript, r.PublicKey); err != nil {
+			return fmt.Errorf("%w for key id %q at index %d", err, keyIDs[i], i)
+		}
+		if scripts[i], err = bscript.NewP2PKHUnlockingScript(r.PublicKey, r.Signature, sighash.AllForkID); err != nil {
+			return err
+		}
+	}
+	for i, uscript := range scripts {
+		tx.Inputs[i].UnlockingScript = uscript
+	}
+
+	return nil
+}
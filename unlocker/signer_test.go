@@ -0,0 +1,172 @@
+package unlocker_test
+
+import (
+	"context"
+	"testing"
+
+	bec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/unlocker"
+)
+
+// kmsSigner is a stand-in for a remote signer, counting the calls made to it.
+type kmsSigner struct {
+	unlocker.LocalSigner
+	signCalls, batchCalls int
+}
+
+func (s *kmsSigner) Sign(ctx context.Context, keyID string, digest []byte) ([]byte, []byte, error) {
+	s.signCalls++
+	return s.LocalSigner.Sign(ctx, keyID, digest)
+}
+
+func (s *kmsSigner) SignBatch(ctx context.Context, reqs []unlocker.SignRequest) ([]unlocker.SignResult, error) {
+	s.batchCalls++
+	return s.LocalSigner.SignBatch(ctx, reqs)
+}
+
+// singleSigner only implements the `unlocker.Signer` interface.
+type singleSigner struct {
+	unlocker.Signer
+}
+
+func newP2PKHTx(t *testing.T, keys ...*bec.PrivateKey) *bt.Tx {
+	t.Helper()
+
+	tx := bt.NewTx()
+	for i, pk := range keys {
+		lockingScript, err := bscript.NewP2PKHFromPubKeyEC(pk.PubKey())
+		require.NoError(t, err)
+		require.NoError(t, tx.From(
+			"a4c76f8a7c05a91dcf5699b95b54e856298e50c1ceca9a8a5569c8532c500c11",
+			uint32(i), lockingScript.String(), 10000,
+		))
+	}
+	require.NoError(t, tx.AddP2PKHOutputFromAddress("mtestD3vRB7AoYWK2n6kLdZmAMLbLhDsLr", 9000))
+
+	return tx
+}
+
+func TestSimple_Signer(t *testing.T) {
+	t.Parallel()
+
+	keys := newKeys(t, 1)
+	expTx := newP2PKHTx(t, keys...)
+	require.NoError(t, expTx.FillAllInputs(context.Background(), &unlocker.Getter{PrivateKey: keys[0]}))
+
+	t.Run("simple", func(t *testing.T) {
+		tx := newP2PKHTx(t, keys...)
+		s := &kmsSigner{LocalSigner: unlocker.LocalSigner{Keys: map[string]*bec.PrivateKey{"key": keys[0]}}}
+
+		u := &unlocker.Simple{Signer: s, KeyID: "key"}
+		require.NoError(t, tx.FillInput(context.Background(), u, bt.UnlockerParams{}))
+
+		assert.Equal(t, expTx.String(), tx.String())
+		assert.Equal(t, 1, s.signCalls)
+	})
+
+	t.Run("getter", func(t *testing.T) {
+		tx := newP2PKHTx(t, keys...)
+		s := &unlocker.LocalSigner{Keys: map[string]*bec.PrivateKey{"key": keys[0]}}
+
+		require.NoError(t, tx.FillAllInputs(context.Background(), &unlocker.Getter{Signer: s, KeyID: "key"}))
+
+		assert.Equal(t, expTx.String(), tx.String())
+	})
+
+	t.Run("unknown key id", func(t *testing.T) {
+		tx := newP2PKHTx(t, keys...)
+		s := &unlocker.LocalSigner{Keys: map[string]*bec.PrivateKey{"key": keys[0]}}
+
+		u := &unlocker.Simple{Signer: s, KeyID: "other"}
+		err := tx.FillInput(context.Background(), u, bt.UnlockerParams{})
+		require.ErrorIs(t, err, unlocker.ErrUnknownKeyID)
+	})
+
+	t.Run("wrong key id", func(t *testing.T) {
+		tx := newP2PKHTx(t, keys...)
+		s := &unlocker.LocalSigner{Keys: map[string]*bec.PrivateKey{"key": keys[0], "other": newKeys(t, 1)[0]}}
+
+		u := &unlocker.Simple{Signer: s, KeyID: "other"}
+		err := tx.FillInput(context.Background(), u, bt.UnlockerParams{})
+		require.ErrorIs(t, err, unlocker.ErrPubKeyMismatch)
+		assert.Nil(t, tx.Inputs[0].UnlockingScript)
+	})
+	t.Run("no key", func(t *testing.T) {
+		tx := newP2PKHTx(t, keys...)
+
+		err := tx.FillAllInputs(context.Background(), &unlocker.Getter{})
+		require.ErrorIs(t, err, unlocker.ErrNoPrivateKey)
+	})
+}
+
+func TestSignAllInputs(t *testing.T) {
+	t.Parallel()
+
+	keys := newKeys(t, 3)
+	local := unlocker.LocalSigner{Keys: map[string]*bec.PrivateKey{
+		"a": keys[0],
+		"b": keys[1],
+		"c": keys[2],
+	}}
+
+	expTx := newP2PKHTx(t, keys[0], keys[1], keys[2], keys[0])
+	for i, pk := range []*bec.PrivateKey{keys[0], keys[1], keys[2], keys[0]} {
+		require.NoError(t, expTx.FillInput(context.Background(), &unlocker.Simple{PrivateKey: pk}, bt.UnlockerParams{
+			InputIdx: uint32(i),
+		}))
+	}
+	keyIDs := []string{"a", "b", "c", "a"}
+
+	t.Run("batch signer", func(t *testing.T) {
+		tx := newP2PKHTx(t, keys[0], keys[1], keys[2], keys[0])
+		s := &kmsSigner{LocalSigner: local}
+
+		require.NoError(t, unlocker.SignAllInputs(context.Background(), tx, s, keyIDs))
+
+		assert.Equal(t, expTx.String(), tx.String())
+		assert.Equal(t, 1, s.batchCalls)
+		assert.Equal(t, 0, s.signCalls)
+	})
+
+	t.Run("signer", func(t *testing.T) {
+		tx := newP2PKHTx(t, keys[0], keys[1], keys[2], keys[0])
+		s := &kmsSigner{LocalSigner: local}
+
+		require.NoError(t, unlocker.SignAllInputs(context.Background(), tx, singleSigner{s}, keyIDs))
+
+		assert.Equal(t, expTx.String(), tx.String())
+		assert.Equal(t, 0, s.batchCalls)
+		assert.Equal(t, 4, s.signCalls)
+	})
+
+	t.Run("key id per input required", func(t *testing.T) {
+		tx := newP2PKHTx(t, keys[0], keys[1], keys[2], keys[0])
+
+		err := unlocker.SignAllInputs(context.Background(), tx, &local, keyIDs[:3])
+		require.ErrorIs(t, err, unlocker.ErrKeyIDCount)
+	})
+
+	t.Run("failing signature leaves tx unchanged", func(t *testing.T) {
+		tx := newP2PKHTx(t, keys[0], keys[1], keys[2], keys[0])
+		rawTxBefore := tx.String()
+
+		err := unlocker.SignAllInputs(context.Background(), tx, &local, []string{"a", "b", "d", "a"})
+		require.ErrorIs(t, err, unlocker.ErrUnknownKeyID)
+		assert.Equal(t, rawTxBefore, tx.String())
+	})
+
+	t.Run("wrong key id", func(t *testing.T) {
+		tx := newP2PKHTx(t, keys[0], keys[1], keys[2], keys[0])
+		rawTxBefore := tx.String()
+
+		err := unlocker.SignAllInputs(context.Background(), tx, &local, []string{"a", "c", "b", "a"})
+		require.ErrorIs(t, err, unlocker.ErrPubKeyMismatch)
+		require.EqualError(t, err, unlocker.ErrPubKeyMismatch.Error()+` for key id "c" at index 1`)
+		assert.Equal(t, rawTxBefore, tx.String())
+	})
+}
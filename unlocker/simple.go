@@ -2,10 +2,13 @@
 package unlocker
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 
 	bec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	crypto "github.com/bsv-blockchain/go-sdk/primitives/hash"
 
 	"github.com/bsv-blockchain/go-bt/v2"
 	"github.com/bsv-blockchain/go-bt/v2/bscript"
@@ -16,10 +19,19 @@ var externalSignerFn func(message, privateKey []byte) ([]byte, error)
 
 // Static errors for err113 linter compliance
 var (
-	ErrOnlyP2PKHSupported = errors.New("currently only p2pkh supported")
+	ErrOnlyP2PKHSupported     = errors.New("currently only p2pkh supported")
+	ErrInsufficientSignatures = errors.New("insufficient signatures to unlock multisig")
+	ErrUnknownKeyID           = errors.New("unknown key id")
+	ErrKeyIDCount             = errors.New("a key id must be given for each input")
+	ErrSignatureCount         = errors.New("signer returned the wrong number of signatures")
+	ErrPubKeyMismatch         = errors.New("public key does not match the locking script")
+	ErrNoPrivateKey           = errors.New("no private key or signer to sign with")
 )
 
 // InjectExternalSignerFn allows the injection of an external signing function.
+//
+// Deprecated: the function is shared by every unlocker and is handed the private
+// key. Set the Signer of each Simple unlocker instead.
 func InjectExternalSignerFn(fn func(message, privateKey []byte) ([]byte, error)) {
 	externalSignerFn = fn
 }
@@ -28,20 +40,73 @@ func InjectExternalSignerFn(fn func(message, privateKey []byte) ([]byte, error))
 // using a bec PrivateKey.
 type Getter struct {
 	PrivateKey *bec.PrivateKey
+	// Signer and KeyID, if Signer is set, are used in place of the PrivateKey
+	// by the unlockers built.
+	Signer Signer
+	KeyID  string
+}
+
+// Template is implemented by a `bscript.ScriptTemplate` which can build a `bt.Unlocker`
+// for the locking scripts it matches. Registering one with `bscript.RegisterTemplate`
+// allows a `*unlocker.Getter` to unlock its scripts.
+type Template interface {
+	bscript.ScriptTemplate
+	Unlocker(lockingScript *bscript.Script, keys ...*bec.PrivateKey) (bt.Unlocker, error)
+}
+
+// SignerTemplate is an optional interface which a `unlocker.Template` can implement
+// to build a `bt.Unlocker` signing with a Signer, allowing a `*unlocker.Getter` with
+// a Signer to unlock its scripts.
+type SignerTemplate interface {
+	Template
+	SignerUnlocker(lockingScript *bscript.Script, s Signer, keyIDs ...string) (bt.Unlocker, error)
 }
 
-// Unlocker builds a new `*unlocker.Local` with the same private key
-// as the calling `*local.Getter`.
+// Unlocker builds a new `bt.Unlocker` with the same private key, or signer, as the
+// calling `*local.Getter`. If the template matching the locking script implements
+// `unlocker.Template`, the unlocker is built by the template, with the signer if the
+// template implements `unlocker.SignerTemplate`. A bare multisig script gets a
+// `*unlocker.MultiSig`, otherwise it is a `*unlocker.Simple`.
 //
 // For an example implementation, see `examples/unlocker_getter/`.
-func (g *Getter) Unlocker(_ context.Context, _ *bscript.Script) (bt.Unlocker, error) {
-	return &Simple{PrivateKey: g.PrivateKey}, nil
+func (g *Getter) Unlocker(_ context.Context, lockingScript *bscript.Script) (bt.Unlocker, error) {
+	if lockingScript != nil {
+		if t, ok := bscript.TemplateFor(lockingScript); ok {
+			if st, ok := t.(SignerTemplate); ok && g.Signer != nil {
+				return st.SignerUnlocker(lockingScript, g.Signer, g.KeyID)
+			}
+			if ut, ok := t.(Template); ok {
+				return ut.Unlocker(lockingScript, g.privateKeys()...)
+			}
+			if t.Type() == bscript.ScriptTypeMultiSig {
+				m := &MultiSig{PrivateKeys: g.privateKeys(), Signer: g.Signer}
+				if g.Signer != nil {
+					m.KeyIDs = []string{g.KeyID}
+				}
+				return m, nil
+			}
+		}
+	}
+
+	return &Simple{PrivateKey: g.PrivateKey, Signer: g.Signer, KeyID: g.KeyID}, nil
+}
+
+// privateKeys returns the private key of the getter, if it has one.
+func (g *Getter) privateKeys() []*bec.PrivateKey {
+	if g.PrivateKey == nil {
+		return nil
+	}
+	return []*bec.PrivateKey{g.PrivateKey}
 }
 
 // Simple implements a simple `bt.Unlocker` interface. It is used to build an unlocking script
-// using a bec Private Key.
+// using a bec Private Key, or using a Signer holding the key identified by KeyID.
 type Simple struct {
 	PrivateKey *bec.PrivateKey
+	// Signer, if set, signs with the key identified by KeyID in place of the
+	// PrivateKey.
+	Signer Signer
+	KeyID  string
 }
 
 // UnlockingScript create the unlocking script for a given input using the PrivateKey passed in through
@@ -52,22 +117,43 @@ type Simple struct {
 // signature is deterministic (same message and same key yield the same signature) and
 // canonical in accordance with RFC6979 and BIP0062.
 //
+// When signing with a Signer, the public key it returns is checked against the public
+// key hash of the locking script before the unlocking script is built, so
+// ErrPubKeyMismatch is returned for a wrong KeyID.
+//
 // For example usage, see `examples/create_tx/create_tx.go`
-func (l *Simple) UnlockingScript(_ context.Context, tx *bt.Tx, params bt.UnlockerParams) (*bscript.Script, error) {
+func (l *Simple) UnlockingScript(ctx context.Context, tx *bt.Tx, params bt.UnlockerParams) (*bscript.Script, error) {
 	if params.SigHashFlags == 0 {
 		params.SigHashFlags = sighash.AllForkID
 	}
 
-	if tx.Inputs[params.InputIdx].PreviousTxScript == nil {
+	lockingScript := tx.Inputs[params.InputIdx].PreviousTxScript
+	if lockingScript == nil {
 		return nil, bt.ErrEmptyPreviousTxScript
 	}
-	switch tx.Inputs[params.InputIdx].PreviousTxScript.ScriptType() {
+	switch lockingScript.ScriptType() {
 	case bscript.ScriptTypePubKeyHash, bscript.ScriptTypePubKeyHashInscription:
-		sh, err := tx.CalcInputSignatureHash(params.InputIdx, params.SigHashFlags)
+		sh, err := sigHash(tx, params)
 		if err != nil {
 			return nil, err
 		}
 
+		if l.Signer != nil {
+			signature, pubKey, signErr := l.Signer.Sign(ctx, l.KeyID, sh)
+			if signErr != nil {
+				return nil, signErr
+			}
+			if err = checkPubKey(lockingScript, pubKey); err != nil {
+				return nil, fmt.Errorf("%w for key id %q", err, l.KeyID)
+			}
+
+			return bscript.NewP2PKHUnlockingScript(pubKey, signature, params.SigHashFlags)
+		}
+
+		if l.PrivateKey == nil {
+			return nil, ErrNoPrivateKey
+		}
+
 		var signature []byte
 
 		if externalSignerFn != nil {
@@ -100,3 +186,34 @@ func (l *Simple) UnlockingScript(_ context.Context, tx *bt.Tx, params bt.Unlocke
 
 	return nil, ErrOnlyP2PKHSupported
 }
+
+// checkPubKey returns ErrPubKeyMismatch if the public key does not hash to the
+// public key hash of the P2PKH locking script.
+func checkPubKey(lockingScript *bscript.Script, pubKey []byte) error {
+	pkh, err := lockingScript.PublicKeyHash()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(pkh, crypto.Hash160(pubKey)) {
+		return ErrPubKeyMismatch
+	}
+
+	return nil
+}
+
+// sigHash returns the signature hash of the input being unlocked, unless it was
+// computed ahead and given in the params.
+func sigHash(tx *bt.Tx, params bt.UnlockerParams) ([]byte, error) {
+	if params.SigHash != nil {
+		return params.SigHash, nil
+	}
+
+	return tx.CalcInputSignatureHashWithCache(params.InputIdx, params.SigHashFlags,
+		tx.Inputs[params.InputIdx].PreviousTxScript, params.SigHashCache)
+}
+
+// EstimateLength implements the `bt.UnlockingScriptEstimator` interface, returning
+// the length of a P2PKH unlocking script.
+func (l *Simple) EstimateLength(_ *bt.Tx, _ uint32) uint32 {
+	return bt.P2PKHUnlockingScriptLen
+}
@@ -0,0 +1,123 @@
+package unlocker_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+
+	bec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter"
+	"github.com/bsv-blockchain/go-bt/v2/unlocker"
+)
+
+// hashPuzzle is a template of OP_SHA256 <hash> OP_EQUAL, unlocked by pushing
+// the preimage of the hash.
+type hashPuzzle struct {
+	preimage []byte
+}
+
+func (hashPuzzle) Type() string { return "hashpuzzle" }
+
+func (hashPuzzle) Match(s *bscript.Script) bool {
+	b := []byte(*s)
+	return len(b) == 35 && b[0] == bscript.OpSHA256 && b[1] == bscript.OpDATA32 && b[34] == bscript.OpEQUAL
+}
+
+func (t hashPuzzle) Params(s *bscript.Script) (*bscript.TemplateParams, error) {
+	if !t.Match(s) {
+		return nil, bscript.ErrTemplateMismatch
+	}
+	return &bscript.TemplateParams{Hash: (*s)[2:34]}, nil
+}
+
+func (hashPuzzle) Lock(p *bscript.TemplateParams) (*bscript.Script, error) {
+	s := &bscript.Script{bscript.OpSHA256}
+	if err := s.AppendPushData(p.Hash); err != nil {
+		return nil, err
+	}
+	_ = s.AppendOpcodes(bscript.OpEQUAL)
+	return s, nil
+}
+
+func (t hashPuzzle) Unlocker(_ *bscript.Script, _ ...*bec.PrivateKey) (bt.Unlocker, error) {
+	return t, nil
+}
+
+func (t hashPuzzle) UnlockingScript(_ context.Context, _ *bt.Tx, _ bt.UnlockerParams) (*bscript.Script, error) {
+	s := &bscript.Script{}
+	if err := s.AppendPushData(t.preimage); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (t hashPuzzle) EstimateLength(_ *bt.Tx, _ uint32) uint32 {
+	return uint32(len(t.preimage)) + 1
+}
+
+// signerHashPuzzle is a hashPuzzle recording the key ids of the signers it is
+// built with.
+type signerHashPuzzle struct {
+	hashPuzzle
+	keyIDs *[]string
+}
+
+func (t signerHashPuzzle) SignerUnlocker(_ *bscript.Script, _ unlocker.Signer, keyIDs ...string) (bt.Unlocker, error) {
+	*t.keyIDs = append(*t.keyIDs, keyIDs...)
+	return t.hashPuzzle, nil
+}
+
+// TestGetter_Template is not parallel, as the registered template is seen by
+// every test until it is unregistered.
+func TestGetter_Template(t *testing.T) {
+	puzzle := hashPuzzle{preimage: []byte("open sesame")}
+	hash := sha256.Sum256(puzzle.preimage)
+	lockingScript, err := puzzle.Lock(&bscript.TemplateParams{Hash: hash[:]})
+	require.NoError(t, err)
+
+	tx := bt.NewTx()
+	require.NoError(t, tx.From(
+		"a4c76f8a7c05a91dcf5699b95b54e856298e50c1ceca9a8a5569c8532c500c11",
+		0, lockingScript.String(), 1000,
+	))
+	require.NoError(t, tx.AddP2PKHOutputFromAddress("mtestD3vRB7AoYWK2n6kLdZmAMLbLhDsLr", 900))
+
+	_, err = tx.EstimateSize()
+	require.ErrorIs(t, err, bt.ErrUnsupportedScript)
+
+	t.Cleanup(bscript.RegisterTemplate(puzzle))
+	assert.Equal(t, "hashpuzzle", lockingScript.ScriptType())
+
+	size, err := tx.EstimateSize()
+	require.NoError(t, err)
+	assert.Equal(t, tx.Size()+len(puzzle.preimage)+1, size)
+
+	pk, err := bec.PrivateKeyFromWif("cNGwGSc7KRrTmdLUZ54fiSXWbhLNDc2Eg5zNucgQxyQCzuQ5YRDq")
+	require.NoError(t, err)
+	require.NoError(t, tx.FillAllInputs(context.Background(), &unlocker.Getter{PrivateKey: pk}))
+
+	assert.Equal(t, size, tx.Size())
+	require.NoError(t, interpreter.NewEngine().Execute(
+		interpreter.WithTx(tx, 0, &bt.Output{LockingScript: lockingScript, Satoshis: 1000}),
+		interpreter.WithForkID(),
+		interpreter.WithAfterGenesis(),
+	))
+
+	// A template not signing with a Signer is given no keys.
+	tx.Inputs[0].UnlockingScript = nil
+	s := &unlocker.LocalSigner{Keys: map[string]*bec.PrivateKey{"key": pk}}
+	require.NoError(t, tx.FillAllInputs(context.Background(), &unlocker.Getter{Signer: s, KeyID: "key"}))
+	assert.Equal(t, size, tx.Size())
+	// A template signing with a Signer is given it instead.
+	var keyIDs []string
+	t.Cleanup(bscript.RegisterTemplate(signerHashPuzzle{hashPuzzle: puzzle, keyIDs: &keyIDs}))
+	tx.Inputs[0].UnlockingScript = nil
+	require.NoError(t, tx.FillAllInputs(context.Background(), &unlocker.Getter{Signer: s, KeyID: "key"}))
+	assert.Equal(t, []string{"key"}, keyIDs)
+	assert.Equal(t, size, tx.Size())
+}